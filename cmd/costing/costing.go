@@ -0,0 +1,385 @@
+package costing
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+
+	"api/repository"
+)
+
+// ErrLayerConsumed is returned when a purchase is removed after some of it was
+// sold, as the cost of those sales depends on it
+var ErrLayerConsumed = errors.New("stock of the purchase has been sold")
+
+// Method is the way stock is valued and cost of goods sold is worked out
+type Method string
+
+const (
+	FIFO            Method = "fifo"
+	WeightedAverage Method = "weighted_average"
+)
+
+func (m Method) Valid() bool {
+	return m == FIFO || m == WeightedAverage
+}
+
+// Engine keeps FIFO cost layers (the remaining quantity of each purchase) and a
+// moving weighted-average cost per product and store. Both are always kept up
+// to date so that stock can be valued either way, the configured method only
+// decides which cost is recorded on order items.
+type Engine struct {
+	method Method
+}
+
+// RebuildResult counts the rows replayed by Rebuild
+type RebuildResult struct {
+	Purchases  int `json:"purchases"`
+	OrderItems int `json:"order_items"`
+}
+
+// New creates the engine for the method in the COSTING_METHOD env variable.
+// It defaults to FIFO.
+func New() (*Engine, error) {
+	method := Method(os.Getenv("COSTING_METHOD"))
+	if method == "" {
+		method = FIFO
+	}
+
+	if !method.Valid() {
+		return nil, fmt.Errorf("unsupported costing method: %s", method)
+	}
+
+	return &Engine{method: method}, nil
+}
+
+func (e *Engine) Method() Method {
+	return e.method
+}
+
+// Receive adds a purchase to the stock of its store. It must run in the same
+// transaction as the purchase insert.
+func (e *Engine) Receive(ctx context.Context, repo *repository.Queries, purchaseID, productID uint64, storeID sql.NullInt64, quantity int32, cost float64) error {
+	if err := repo.OpenCostLayer(ctx, purchaseID); err != nil {
+		return fmt.Errorf("unable to open cost layer: %w", err)
+	}
+
+	if !storeID.Valid {
+		return nil
+	}
+
+	stock, _, err := findAverage(ctx, repo, productID, uint64(storeID.Int64))
+	if err != nil {
+		return err
+	}
+
+	return saveAverage(ctx, repo, productID, uint64(storeID.Int64), stock.receive(quantity, cost))
+}
+
+// Remove takes a purchase back out of the stock of its store, undoing Receive.
+// Only purchases none of which was sold can be removed. It must run in the
+// same transaction as the purchase delete, on a purchase read with
+// LockCostLayer.
+func (e *Engine) Remove(ctx context.Context, repo *repository.Queries, purchase repository.Purchase) error {
+	if purchase.Remaining < purchase.Quantity {
+		return ErrLayerConsumed
+	}
+
+	if !purchase.StoreID.Valid {
+		return nil
+	}
+
+	storeID := uint64(purchase.StoreID.Int64)
+
+	stock, found, err := findAverage(ctx, repo, purchase.ProductID, storeID)
+	if err != nil || !found {
+		return err
+	}
+
+	return saveAverage(ctx, repo, purchase.ProductID, storeID, stock.reverse(purchase.Quantity, purchase.OrderPrice))
+}
+
+// Issue takes quantity units of a product out of the stock of a store and
+// returns their cost under the configured method. Units sold beyond what was
+// purchased are costed at the latest purchase price of the product.
+func (e *Engine) Issue(ctx context.Context, repo *repository.Queries, productID uint64, storeID sql.NullInt64, quantity int32) (float64, error) {
+	fallback, err := repo.FindLatestPurchaseCost(ctx, productID)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, fmt.Errorf("unable to find purchase cost: %w", err)
+	}
+
+	if !storeID.Valid {
+		return roundCost(float64(quantity) * fallback), nil
+	}
+
+	fifo, err := e.issueLayers(ctx, repo, productID, storeID, quantity, fallback)
+	if err != nil {
+		return 0, err
+	}
+
+	average, err := e.issueAverage(ctx, repo, productID, uint64(storeID.Int64), quantity, fallback)
+	if err != nil {
+		return 0, err
+	}
+
+	if e.method == WeightedAverage {
+		return roundCost(average), nil
+	}
+
+	return roundCost(fifo), nil
+}
+
+// issueLayers consumes the oldest purchases first
+func (e *Engine) issueLayers(ctx context.Context, repo *repository.Queries, productID uint64, storeID sql.NullInt64, quantity int32, fallback float64) (float64, error) {
+	layers, err := repo.FindCostLayers(ctx, repository.FindCostLayersParams{
+		ProductID: productID,
+		StoreID:   storeID,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("unable to find cost layers: %w", err)
+	}
+
+	takes, cost := takeLayers(layers, quantity, fallback)
+
+	for _, take := range takes {
+		err := repo.ConsumeCostLayer(ctx, repository.ConsumeCostLayerParams{
+			Remaining: take.quantity,
+			ID:        take.id,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("unable to consume cost layer: %w", err)
+		}
+	}
+
+	return cost, nil
+}
+
+func (e *Engine) issueAverage(ctx context.Context, repo *repository.Queries, productID, storeID uint64, quantity int32, fallback float64) (float64, error) {
+	stock, found, err := findAverage(ctx, repo, productID, storeID)
+	if err != nil {
+		return 0, err
+	}
+
+	if !found {
+		return float64(quantity) * fallback, nil
+	}
+
+	stock, cost := stock.issue(quantity)
+
+	if err := saveAverage(ctx, repo, productID, storeID, stock); err != nil {
+		return 0, err
+	}
+
+	return cost, nil
+}
+
+// layerTake is how many units a sale takes from a purchase
+type layerTake struct {
+	id       uint64
+	quantity int32
+}
+
+// takeLayers picks quantity units from the layers, oldest first, and returns
+// what to take from each and the cost of it all. Units beyond what the layers
+// hold are costed at fallback.
+func takeLayers(layers []repository.FindCostLayersRow, quantity int32, fallback float64) ([]layerTake, float64) {
+	var takes []layerTake
+	var cost float64
+	left := quantity
+
+	for _, layer := range layers {
+		if left == 0 {
+			break
+		}
+
+		take := min(left, layer.Remaining)
+
+		takes = append(takes, layerTake{id: layer.ID, quantity: take})
+
+		cost += float64(take) * layer.OrderPrice
+		left -= take
+	}
+
+	return takes, cost + float64(left)*fallback
+}
+
+// averageCost is the stock of a product in a store and its moving
+// weighted-average cost
+type averageCost struct {
+	quantity int32
+	cost     float64
+}
+
+// receive adds units bought at cost. Stock below zero does not weigh in, as
+// the units sold without stock were costed at the time.
+func (a averageCost) receive(quantity int32, cost float64) averageCost {
+	base := max(a.quantity, 0)
+
+	if base+quantity > 0 {
+		a.cost = (float64(base)*a.cost + float64(quantity)*cost) / float64(base+quantity)
+	} else {
+		a.cost = cost
+	}
+
+	a.quantity += quantity
+
+	return a
+}
+
+// issue takes units out at the average cost and returns the cost of them
+func (a averageCost) issue(quantity int32) (averageCost, float64) {
+	cost := float64(quantity) * a.cost
+	a.quantity -= quantity
+
+	return a, cost
+}
+
+// reverse takes units bought at cost out again, undoing receive
+func (a averageCost) reverse(quantity int32, cost float64) averageCost {
+	if left := a.quantity - quantity; left > 0 {
+		a.cost = (float64(a.quantity)*a.cost - float64(quantity)*cost) / float64(left)
+	}
+
+	a.quantity -= quantity
+
+	return a
+}
+
+// findAverage locks the average cost of a product in a store. It reports
+// whether the product was ever received there.
+func findAverage(ctx context.Context, repo *repository.Queries, productID, storeID uint64) (averageCost, bool, error) {
+	stock, err := repo.FindStockCost(ctx, repository.FindStockCostParams{
+		ProductID: productID,
+		StoreID:   storeID,
+	})
+	if err == sql.ErrNoRows {
+		return averageCost{}, false, nil
+	} else if err != nil {
+		return averageCost{}, false, fmt.Errorf("unable to find average cost: %w", err)
+	}
+
+	return averageCost{quantity: stock.Quantity, cost: stock.AverageCost}, true, nil
+}
+
+func saveAverage(ctx context.Context, repo *repository.Queries, productID, storeID uint64, stock averageCost) error {
+	err := repo.SaveStockCost(ctx, repository.SaveStockCostParams{
+		ProductID:   productID,
+		StoreID:     storeID,
+		Quantity:    stock.quantity,
+		AverageCost: stock.cost,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to update average cost: %w", err)
+	}
+
+	return nil
+}
+
+// Rebuild recomputes the cost layers, average costs and the cost of every
+// order item by replaying purchases and sales in the order they happened
+func (e *Engine) Rebuild(ctx context.Context, db *sql.DB) (RebuildResult, error) {
+	var result RebuildResult
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return result, err
+	}
+	defer tx.Rollback()
+
+	repo := repository.New(tx)
+
+	if err := repo.ResetCostLayers(ctx); err != nil {
+		return result, err
+	}
+
+	if err := repo.DeleteStockCosts(ctx); err != nil {
+		return result, err
+	}
+
+	purchases, err := repo.FindPurchasesForCosting(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	items, err := repo.FindOrderItemsForCosting(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	p := 0
+	for _, item := range items {
+		// Stock received up to the time of the sale is available to it
+		for p < len(purchases) && !purchases[p].Date.After(item.CreatedAt.Time) {
+			if err := e.receivePurchase(ctx, repo, purchases[p]); err != nil {
+				return result, err
+			}
+			p++
+		}
+
+		cost, err := e.Issue(ctx, repo, item.ProductID, item.StoreID, item.Quantity)
+		if err != nil {
+			return result, err
+		}
+
+		err = repo.UpdateOrderItemCogs(ctx, repository.UpdateOrderItemCogsParams{
+			Cogs: sql.NullFloat64{Float64: cost, Valid: true},
+			ID:   item.ID,
+		})
+		if err != nil {
+			return result, err
+		}
+	}
+
+	for ; p < len(purchases); p++ {
+		if err := e.receivePurchase(ctx, repo, purchases[p]); err != nil {
+			return result, err
+		}
+	}
+
+	err = repo.InsertCostingRebuild(ctx, repository.InsertCostingRebuildParams{
+		Purchases:  int32(len(purchases)),
+		OrderItems: int32(len(items)),
+	})
+	if err != nil {
+		return result, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return result, err
+	}
+
+	result.Purchases = len(purchases)
+	result.OrderItems = len(items)
+
+	return result, nil
+}
+
+// Prepare rebuilds the costs when that has never been done, as the costing
+// migration leaves it to the replay of Rebuild. It reports whether it did.
+func (e *Engine) Prepare(ctx context.Context, db *sql.DB) (bool, error) {
+	count, err := repository.New(db).CountCostingRebuilds(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if count > 0 {
+		return false, nil
+	}
+
+	if _, err := e.Rebuild(ctx, db); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (e *Engine) receivePurchase(ctx context.Context, repo *repository.Queries, purchase repository.FindPurchasesForCostingRow) error {
+	return e.Receive(ctx, repo, purchase.ID, purchase.ProductID, purchase.StoreID, purchase.Quantity, purchase.OrderPrice)
+}
+
+func roundCost(cost float64) float64 {
+	return math.Round(cost*100) / 100
+}
@@ -0,0 +1,189 @@
+package costing
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"api/repository"
+)
+
+func TestTakeLayers(t *testing.T) {
+	layers := []repository.FindCostLayersRow{
+		{ID: 1, Remaining: 5, OrderPrice: 2},
+		{ID: 2, Remaining: 10, OrderPrice: 3},
+	}
+
+	tests := []struct {
+		name      string
+		layers    []repository.FindCostLayersRow
+		quantity  int32
+		fallback  float64
+		wantTakes []layerTake
+		wantCost  float64
+	}{
+		{
+			name:      "within the oldest layer",
+			layers:    layers,
+			quantity:  3,
+			fallback:  4,
+			wantTakes: []layerTake{{id: 1, quantity: 3}},
+			wantCost:  6,
+		},
+		{
+			name:      "empties the oldest layer exactly",
+			layers:    layers,
+			quantity:  5,
+			fallback:  4,
+			wantTakes: []layerTake{{id: 1, quantity: 5}},
+			wantCost:  10,
+		},
+		{
+			name:      "across layers",
+			layers:    layers,
+			quantity:  8,
+			fallback:  4,
+			wantTakes: []layerTake{{id: 1, quantity: 5}, {id: 2, quantity: 3}},
+			wantCost:  19,
+		},
+		{
+			name:      "over-issue is costed at the fallback",
+			layers:    layers,
+			quantity:  18,
+			fallback:  4,
+			wantTakes: []layerTake{{id: 1, quantity: 5}, {id: 2, quantity: 10}},
+			wantCost:  52,
+		},
+		{
+			name:     "no layers",
+			quantity: 2,
+			fallback: 4,
+			wantCost: 8,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			takes, cost := takeLayers(tt.layers, tt.quantity, tt.fallback)
+
+			if !reflect.DeepEqual(takes, tt.wantTakes) {
+				t.Errorf("takeLayers() takes = %+v, want %+v", takes, tt.wantTakes)
+			}
+
+			if cost != tt.wantCost {
+				t.Errorf("takeLayers() cost = %v, want %v", cost, tt.wantCost)
+			}
+		})
+	}
+}
+
+func TestAverageCost(t *testing.T) {
+	// A step receives when cost is set, issues otherwise
+	type step struct {
+		quantity int32
+		cost     float64
+	}
+
+	tests := []struct {
+		name         string
+		steps        []step
+		wantQuantity int32
+		wantAverage  float64
+		wantIssued   float64
+	}{
+		{
+			name:         "single receipt",
+			steps:        []step{{quantity: 10, cost: 2}},
+			wantQuantity: 10,
+			wantAverage:  2,
+		},
+		{
+			name:         "receipts are weighted by quantity",
+			steps:        []step{{quantity: 10, cost: 2}, {quantity: 30, cost: 4}},
+			wantQuantity: 40,
+			wantAverage:  3.5,
+		},
+		{
+			name:         "partial issue keeps the average",
+			steps:        []step{{quantity: 10, cost: 2}, {quantity: 4}},
+			wantQuantity: 6,
+			wantAverage:  2,
+			wantIssued:   8,
+		},
+		{
+			name:         "receipt after a partial issue weighs what is left",
+			steps:        []step{{quantity: 10, cost: 2}, {quantity: 4}, {quantity: 10, cost: 5}, {quantity: 8}},
+			wantQuantity: 8,
+			wantAverage:  3.875,
+			wantIssued:   39,
+		},
+		{
+			name:         "receipt after an over-issue ignores the shortfall",
+			steps:        []step{{quantity: 5, cost: 2}, {quantity: 8}, {quantity: 10, cost: 6}},
+			wantQuantity: 7,
+			wantAverage:  6,
+			wantIssued:   16,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stock averageCost
+			var issued float64
+
+			for _, s := range tt.steps {
+				if s.cost > 0 {
+					stock = stock.receive(s.quantity, s.cost)
+					continue
+				}
+
+				var cost float64
+				stock, cost = stock.issue(s.quantity)
+				issued += cost
+			}
+
+			if stock.quantity != tt.wantQuantity {
+				t.Errorf("quantity = %d, want %d", stock.quantity, tt.wantQuantity)
+			}
+
+			if math.Abs(stock.cost-tt.wantAverage) > 1e-9 {
+				t.Errorf("average = %v, want %v", stock.cost, tt.wantAverage)
+			}
+
+			if math.Abs(issued-tt.wantIssued) > 1e-9 {
+				t.Errorf("issued = %v, want %v", issued, tt.wantIssued)
+			}
+		})
+	}
+}
+
+func TestAverageCostReverse(t *testing.T) {
+	stock := averageCost{}.receive(10, 2).receive(30, 4)
+
+	stock = stock.reverse(30, 4)
+	if stock.quantity != 10 || math.Abs(stock.cost-2) > 1e-9 {
+		t.Errorf("reverse() = %+v, want 10 units at 2", stock)
+	}
+
+	stock = stock.reverse(10, 2)
+	if stock.quantity != 0 || stock.cost != 2 {
+		t.Errorf("reverse() of all stock = %+v, want 0 units keeping the cost of 2", stock)
+	}
+}
+
+func TestRoundCost(t *testing.T) {
+	tests := []struct {
+		cost float64
+		want float64
+	}{
+		{cost: 1.234, want: 1.23},
+		{cost: 1.236, want: 1.24},
+		{cost: 19, want: 19},
+	}
+
+	for _, tt := range tests {
+		if got := roundCost(tt.cost); got != tt.want {
+			t.Errorf("roundCost(%v) = %v, want %v", tt.cost, got, tt.want)
+		}
+	}
+}
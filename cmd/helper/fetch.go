@@ -0,0 +1,120 @@
+package helper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+const (
+	fetchTimeout      = 30 * time.Second
+	fetchMaxRedirects = 3
+)
+
+var (
+	ErrFetchScheme  = errors.New("only http and https URLs can be fetched")
+	ErrFetchAddress = errors.New("URL points to a private address")
+)
+
+// publicAddress tells whether an IP can be reached from outside, so downloads
+// cannot be pointed at the server itself, the private network or cloud
+// metadata services
+func publicAddress(ip net.IP) bool {
+	return !(ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified())
+}
+
+// checkDial refuses connections to addresses that are not public. It runs
+// after DNS resolution, so a public name resolving to a private address is
+// refused too.
+func checkDial(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !publicAddress(ip) {
+		return ErrFetchAddress
+	}
+
+	return nil
+}
+
+func checkScheme(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ErrFetchScheme
+	}
+
+	return nil
+}
+
+// fetchClient downloads files named by users, like the images of an import
+var fetchClient = &http.Client{
+	Timeout: fetchTimeout,
+	Transport: &http.Transport{
+		Proxy: nil,
+		DialContext: (&net.Dialer{
+			Timeout: 10 * time.Second,
+			Control: checkDial,
+		}).DialContext,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 10 * time.Second,
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) >= fetchMaxRedirects {
+			return fmt.Errorf("stopped after %d redirects", fetchMaxRedirects)
+		}
+
+		return checkScheme(req.URL)
+	},
+}
+
+type limitedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// FetchImage downloads an image from a public http or https URL. At most one
+// byte more than an upload may have is read, so ReadImage can tell the image
+// is too large without the whole body being downloaded.
+func FetchImage(ctx context.Context, rawURL string) (io.ReadCloser, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkScheme(u); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := fetchClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		return nil, fmt.Errorf("download failed with status %d", res.StatusCode)
+	}
+
+	maxBytes := envInt64("UPLOAD_MAX_BYTES", defaultMaxImageBytes)
+
+	return limitedBody{Reader: io.LimitReader(res.Body, maxBytes+1), Closer: res.Body}, nil
+}
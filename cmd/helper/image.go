@@ -1,45 +1,81 @@
 package helper
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"image"
 	"image/jpeg"
 	"image/png"
-	"mime/multipart"
-	"net/textproto"
+	"io"
+	"net/http"
 	"os"
-	"path/filepath"
+	"strconv"
 	"time"
 
+	"api/cmd/storage"
+
 	"github.com/disintegration/imaging"
 	"github.com/google/uuid"
 	"github.com/nfnt/resize"
 )
 
-func OpenImage(filename, fileType string) (image.Image, error) {
-	file, err := os.Open(filename)
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
+const (
+	defaultMaxImageBytes  = 10 << 20
+	defaultMaxImagePixels = 40_000_000
+)
+
+var (
+	ErrUnsupportedImage = errors.New("unsupported file type")
+	ErrImageTooLarge    = errors.New("image is too large")
+	ErrInvalidImage     = errors.New("image could not be decoded")
+)
+
+// ImageUpload is an uploaded image that passed validation and is ready to be stored
+type ImageUpload struct {
+	Name        string
+	Hash        string
+	ContentType string
+	ext         string
+	data        []byte
+	img         image.Image
+}
 
+func ImageKey(filename string) string {
+	return fmt.Sprintf("images/%s", filename)
+}
+
+func ThumbnailKey(filename string) string {
+	return fmt.Sprintf("thumbnails/%s", filename)
+}
+
+func DecodeImage(r io.Reader, fileType string) (image.Image, error) {
 	switch fileType {
 	case "image/jpeg":
-		return jpeg.Decode(file)
+		return jpeg.Decode(r)
 	case "image/png":
-		return png.Decode(file)
+		return png.Decode(r)
 	default:
-		return nil, fmt.Errorf("unsupported file type")
+		return nil, ErrUnsupportedImage
 	}
 }
 
-func CreateImageVersions(src image.Image, filename, ext string, path string) error {
+func CreateImageVersions(ctx context.Context, store storage.BlobStore, src image.Image, filename, ext, fileType string) error {
 	qualities := []int{ /* 100, 75, 50, */ 25}
 
 	for _, quality := range qualities {
 		resized := resize.Resize(uint(src.Bounds().Dx()*quality/100), 0, src, resize.Lanczos3)
-		outputPath := filepath.Join(path, filename)
-		err := SaveImage(resized, outputPath, ext)
+
+		var buf bytes.Buffer
+		err := EncodeImage(&buf, resized, ext)
+		if err != nil {
+			return err
+		}
+
+		err = store.Put(ctx, ThumbnailKey(filename), &buf, int64(buf.Len()), fileType)
 		if err != nil {
 			return err
 		}
@@ -47,22 +83,37 @@ func CreateImageVersions(src image.Image, filename, ext string, path string) err
 	return nil
 }
 
-func SaveImage(img image.Image, outputPath, ext string) error {
+func EncodeImage(w io.Writer, img image.Image, ext string) error {
 	switch ext {
 	case ".jpg":
-		return imaging.Save(img, outputPath, imaging.JPEGQuality(80))
+		return imaging.Encode(w, img, imaging.JPEG, imaging.JPEGQuality(80))
 	case ".png":
-		return imaging.Save(img, outputPath, imaging.PNGCompressionLevel(png.BestCompression))
+		return imaging.Encode(w, img, imaging.PNG, imaging.PNGCompressionLevel(png.BestCompression))
 	default:
-		return fmt.Errorf("unsupported file type")
+		return ErrUnsupportedImage
 	}
 }
 
-func UploadImage(file multipart.File, header textproto.MIMEHeader) (*string, error) {
+// ReadImage reads and validates an uploaded image. The file type is sniffed
+// from its content, and the byte size and pixel count are checked before the
+// image is fully decoded so that decompression bombs are rejected early.
+func ReadImage(file io.ReadCloser) (*ImageUpload, error) {
 	defer file.Close()
 
-	// Determine the file type
-	fileType := header.Get("Content-Type")
+	maxBytes := envInt64("UPLOAD_MAX_BYTES", defaultMaxImageBytes)
+	maxPixels := envInt64("UPLOAD_MAX_PIXELS", defaultMaxImagePixels)
+
+	data, err := io.ReadAll(io.LimitReader(file, maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read file: %w", err)
+	}
+
+	if int64(len(data)) > maxBytes {
+		return nil, ErrImageTooLarge
+	}
+
+	// Determine the file type from the magic bytes
+	fileType := http.DetectContentType(data)
 	var ext string
 	switch fileType {
 	case "image/jpeg":
@@ -70,42 +121,76 @@ func UploadImage(file multipart.File, header textproto.MIMEHeader) (*string, err
 	case "image/png":
 		ext = ".png"
 	default:
-		return nil, fmt.Errorf("unsupported file type")
+		return nil, ErrUnsupportedImage
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	if config.Width <= 0 || config.Height <= 0 || int64(config.Width)*int64(config.Height) > maxPixels {
+		return nil, ErrImageTooLarge
 	}
 
+	img, err := DecodeImage(bytes.NewReader(data), fileType)
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	sum := sha256.Sum256(data)
+
 	// Generate a unique file name using UUID and timestamp
 	uuid := uuid.New()
 	timestamp := time.Now().Format("20060102150405")
-	filename := fmt.Sprintf("%s_%s%s", timestamp, uuid.String(), ext)
 
-	uploadPath := os.Getenv("UPLOADS_PATH")
-	if uploadPath == "" {
-		return nil, fmt.Errorf("something went wrong")
+	return &ImageUpload{
+		Name:        fmt.Sprintf("%s_%s%s", timestamp, uuid.String(), ext),
+		Hash:        hex.EncodeToString(sum[:]),
+		ContentType: fileType,
+		ext:         ext,
+		data:        data,
+		img:         img,
+	}, nil
+}
+
+// Store saves the original image and its thumbnail. Nothing is left behind if
+// either of them fails.
+func (u *ImageUpload) Store(ctx context.Context, store storage.BlobStore) error {
+	err := store.Put(ctx, ImageKey(u.Name), bytes.NewReader(u.data), int64(len(u.data)), u.ContentType)
+	if err != nil {
+		return fmt.Errorf("unable to store image: %w", err)
 	}
 
-	// Save the uploaded file
-	dst, err := os.Create(fmt.Sprintf("%s/%s", fmt.Sprintf("%s/images", os.Getenv("UPLOADS_PATH")), filename))
+	// Create different quality versions
+	err = CreateImageVersions(ctx, store, u.img, u.Name, u.ext, u.ContentType)
 	if err != nil {
-		return nil, fmt.Errorf("something went wrong: %s", err.Error())
+		u.Remove(ctx, store)
+		return fmt.Errorf("unable to store thumbnail: %w", err)
 	}
 
-	defer dst.Close()
+	return nil
+}
 
-	if _, err := dst.ReadFrom(file); err != nil {
-		return nil, fmt.Errorf("something went wrong: %s", err.Error())
-	}
+// Remove deletes the stored image and its thumbnail
+func (u *ImageUpload) Remove(ctx context.Context, store storage.BlobStore) error {
+	return RemoveImage(ctx, store, u.Name)
+}
 
-	// Open the saved file
-	src, err := OpenImage(fmt.Sprintf("%s/%s", fmt.Sprintf("%s/images", os.Getenv("UPLOADS_PATH")), filename), fileType)
+func RemoveImage(ctx context.Context, store storage.BlobStore, filename string) error {
+	err := store.Delete(ctx, ThumbnailKey(filename))
 	if err != nil {
-		return nil, fmt.Errorf("something went wrong: %s", err.Error())
+		return err
 	}
 
-	// Create different quality versions
-	err = CreateImageVersions(src, filename, ext, fmt.Sprintf("%s/thumbnails", os.Getenv("UPLOADS_PATH")))
-	if err != nil {
-		return nil, fmt.Errorf("something went wrong: %s", err.Error())
+	return store.Delete(ctx, ImageKey(filename))
+}
+
+func envInt64(key string, fallback int64) int64 {
+	value, err := strconv.ParseInt(os.Getenv(key), 10, 64)
+	if err != nil || value <= 0 {
+		return fallback
 	}
 
-	return &filename, nil
+	return value
 }
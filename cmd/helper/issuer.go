@@ -7,10 +7,34 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 type Issuer struct {
 	key crypto.PrivateKey
+	ttl time.Duration
+}
+
+// AccessTokenTTL reads how long access tokens last from ACCESS_TOKEN_TTL, it
+// defaults to 15 minutes. Sessions are kept going with refresh tokens.
+func AccessTokenTTL() time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv("ACCESS_TOKEN_TTL"))
+	if err != nil || ttl <= 0 {
+		return 15 * time.Minute
+	}
+
+	return ttl
+}
+
+// RefreshTokenTTL reads how long a refresh token can be used from
+// REFRESH_TOKEN_TTL, it defaults to 30 days
+func RefreshTokenTTL() time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv("REFRESH_TOKEN_TTL"))
+	if err != nil || ttl <= 0 {
+		return 30 * 24 * time.Hour
+	}
+
+	return ttl
 }
 
 func NewIssuer(path string) (*Issuer, error) {
@@ -24,23 +48,41 @@ func NewIssuer(path string) (*Issuer, error) {
 		return nil, fmt.Errorf("unable to parse as ed private key: %w", err)
 	}
 
-	return &Issuer{key: key}, nil
+	return &Issuer{key: key, ttl: AccessTokenTTL()}, nil
+}
+
+// TTL is how long the tokens issued last
+func (i *Issuer) TTL() time.Duration {
+	return i.ttl
 }
 
-func (i *Issuer) IssueToken(id uint, name string, roles []string) (string, error) {
+// IssueToken signs an access token for a session, which is the family of
+// refresh tokens it was issued with. A store picked at sign in is added as
+// the "store" claim, 0 leaves it out. The "mfa" claim tells whether the session
+// was signed in with a second factor.
+func (i *Issuer) IssueToken(id uint, name string, roles []string, session string, store uint64, mfa bool) (string, error) {
 	now := time.Now()
 
-	token := jwt.NewWithClaims(&jwt.SigningMethodEd25519{}, jwt.MapClaims{
+	claims := jwt.MapClaims{
 		"aud": "api",
 		"nbf": now.Unix(),
 		"iat": now.Unix(),
-		"exp": now.Add(time.Hour * 24 * 30).Unix(),
+		"exp": now.Add(i.ttl).Unix(),
 		"iss": "http://localhost:5000",
 		//TODO convert uint to string
 		"sub":   fmt.Sprint(id),
 		"name":  name,
 		"roles": roles,
-	})
+		"sid":   session,
+		"jti":   uuid.NewString(),
+		"mfa":   mfa,
+	}
+
+	if store != 0 {
+		claims["store"] = store
+	}
+
+	token := jwt.NewWithClaims(&jwt.SigningMethodEd25519{}, claims)
 
 	tokenString, err := token.SignedString(i.key)
 	if err != nil {
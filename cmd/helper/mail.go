@@ -1,6 +1,11 @@
 package helper
 
-import "gopkg.in/gomail.v2"
+import (
+	"bytes"
+	"html/template"
+
+	"gopkg.in/gomail.v2"
+)
 
 func SendEmail(to string, subject string, body string) error {
 	msg := gomail.NewMessage()
@@ -18,3 +23,56 @@ func SendEmail(to string, subject string, body string) error {
 
 	return nil
 }
+
+// Names of the email templates
+const (
+	EmailPasswordReset = "password-reset"
+	EmailVerification  = "email-verification"
+)
+
+var emailTemplates = template.Must(template.New("emails").Parse(`
+{{define "layout"}}<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+</head>
+<body style="font-family: Helvetica, Arial, sans-serif; font-size: 14px; color: #222;">
+{{template "content" .}}
+<p>Fixchirp</p>
+</body>
+</html>{{end}}
+{{define "password-reset"}}<p>Hi {{.Name}},</p>
+<p>We received a request to reset the password of your account. Use the link below to choose a new password, it works once and expires in {{.Expires}}.</p>
+<p><a href="{{.Link}}">Reset your password</a></p>
+<p>If you did not ask for this you can ignore this email, your password has not been changed.</p>{{end}}
+{{define "email-verification"}}<p>Hi {{.Name}},</p>
+<p>Please confirm that this is your email address. The link below works once and expires in {{.Expires}}.</p>
+<p><a href="{{.Link}}">Verify your email</a></p>
+<p>If you did not create an account you can ignore this email.</p>{{end}}
+`))
+
+// EmailData fills in an email template
+type EmailData struct {
+	Name    string
+	Link    string
+	Expires string
+}
+
+// RenderEmail writes the body of the named email template
+func RenderEmail(name string, data EmailData) (string, error) {
+	tmpl, err := emailTemplates.Clone()
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := tmpl.New("content").Parse(`{{template "` + name + `" .}}`); err != nil {
+		return "", err
+	}
+
+	var body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&body, "layout", data); err != nil {
+		return "", err
+	}
+
+	return body.String(), nil
+}
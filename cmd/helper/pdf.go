@@ -0,0 +1,145 @@
+package helper
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	pdfFontSize   = 8
+	pdfLineHeight = 6
+)
+
+// pdfTableWriter lays a table out on landscape A4 pages, repeating the header
+// on every page. Unlike CSV and XLSX, a PDF can only be written once it is
+// complete, so the document is kept in memory until Close.
+type pdfTableWriter struct {
+	out       io.Writer
+	pdf       *fpdf.Fpdf
+	translate func(string) string
+	columns   []string
+	width     float64
+}
+
+func newPDFTableWriter(w io.Writer) *pdfTableWriter {
+	pdf := fpdf.New("L", "mm", "A4", "")
+	pdf.SetMargins(10, 10, 10)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.AliasNbPages("")
+
+	writer := &pdfTableWriter{
+		out:       w,
+		pdf:       pdf,
+		translate: pdf.UnicodeTranslatorFromDescriptor(""),
+	}
+
+	pdf.SetHeaderFunc(writer.header)
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont("Helvetica", "", pdfFontSize)
+		pdf.CellFormat(0, pdfLineHeight, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "R", false, 0, "")
+	})
+
+	return writer
+}
+
+func (p *pdfTableWriter) header() {
+	if len(p.columns) == 0 {
+		return
+	}
+
+	p.pdf.SetFont("Helvetica", "B", pdfFontSize)
+	p.pdf.SetFillColor(230, 230, 230)
+
+	for _, column := range p.columns {
+		p.pdf.CellFormat(p.width, pdfLineHeight, p.fit(column), "1", 0, "L", true, 0, "")
+	}
+	p.pdf.Ln(-1)
+
+	p.pdf.SetFont("Helvetica", "", pdfFontSize)
+}
+
+func (p *pdfTableWriter) WriteHeader(columns []string) error {
+	pageWidth, _ := p.pdf.GetPageSize()
+	left, _, right, _ := p.pdf.GetMargins()
+
+	p.columns = columns
+	p.width = (pageWidth - left - right) / float64(max(len(columns), 1))
+
+	p.pdf.AddPage()
+	return p.pdf.Error()
+}
+
+func (p *pdfTableWriter) WriteRow(values []interface{}) error {
+	for _, value := range values {
+		align := "L"
+		text := formatCell(value)
+
+		switch v := value.(type) {
+		case Money:
+			align = "R"
+			text = FormatMoney(float64(v))
+		case float64, float32, int, int32, int64, uint64:
+			align = "R"
+		case time.Time:
+			text = v.UTC().Format("2006-01-02 15:04")
+		}
+
+		p.pdf.CellFormat(p.width, pdfLineHeight, p.fit(text), "1", 0, align, false, 0, "")
+	}
+	p.pdf.Ln(-1)
+
+	return p.pdf.Error()
+}
+
+// fit shortens text that is wider than a column
+func (p *pdfTableWriter) fit(text string) string {
+	text = p.translate(text)
+
+	limit := p.width - 2
+	if p.pdf.GetStringWidth(text) <= limit {
+		return text
+	}
+
+	for len(text) > 0 && p.pdf.GetStringWidth(text+"...") > limit {
+		text = text[:len(text)-1]
+	}
+
+	return text + "..."
+}
+
+func (p *pdfTableWriter) Close() error {
+	return p.pdf.Output(p.out)
+}
+
+// FormatMoney writes an amount with thousands separators and negative amounts
+// in brackets
+func FormatMoney(amount float64) string {
+	negative := amount < 0
+	if negative {
+		amount = -amount
+	}
+
+	text := strconv.FormatFloat(amount, 'f', 2, 64)
+	whole, cents, _ := strings.Cut(text, ".")
+
+	var grouped strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+
+	text = grouped.String() + "." + cents
+	if negative {
+		return "(" + text + ")"
+	}
+
+	return text
+}
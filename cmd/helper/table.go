@@ -0,0 +1,250 @@
+package helper
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+	FormatPDF  = "pdf"
+)
+
+// Money is an amount of money. Spreadsheets show it with two decimals and
+// negative amounts in brackets, as accounting expects.
+type Money float64
+
+// xlsxMoneyFormat and xlsxCountFormat are custom number formats for Money and
+// whole numbers, xlsxDateFormat is used for time.Time cells
+const (
+	xlsxMoneyFormat = "#,##0.00;(#,##0.00)"
+	xlsxCountFormat = "#,##0"
+	xlsxDateFormat  = "yyyy-mm-dd hh:mm"
+)
+
+// TableFormat returns the table format of a file from its extension
+func TableFormat(filename string) (string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".xlsx":
+		return FormatXLSX, nil
+	default:
+		return "", fmt.Errorf("unsupported file format")
+	}
+}
+
+// ReadTable reads every row of the first sheet of a CSV or XLSX file
+func ReadTable(r io.Reader, format string) ([][]string, error) {
+	switch format {
+	case FormatCSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+
+		return reader.ReadAll()
+	case FormatXLSX:
+		f, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open spreadsheet: %w", err)
+		}
+		defer f.Close()
+
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, fmt.Errorf("spreadsheet has no sheets")
+		}
+
+		return f.GetRows(sheets[0])
+	default:
+		return nil, fmt.Errorf("unsupported file format")
+	}
+}
+
+// TableWriter writes rows of a table as they are produced
+type TableWriter interface {
+	WriteHeader(columns []string) error
+	WriteRow(values []interface{}) error
+	Close() error
+}
+
+func NewTableWriter(w io.Writer, format string) (TableWriter, error) {
+	switch format {
+	case FormatCSV:
+		return &csvTableWriter{writer: csv.NewWriter(w)}, nil
+	case FormatXLSX:
+		return newXLSXTableWriter(w)
+	case FormatPDF:
+		return newPDFTableWriter(w), nil
+	default:
+		return nil, fmt.Errorf("unsupported file format")
+	}
+}
+
+// TableContentType returns the MIME type of a table format
+func TableContentType(format string) string {
+	switch format {
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatPDF:
+		return "application/pdf"
+	default:
+		return "text/csv"
+	}
+}
+
+type csvTableWriter struct {
+	writer *csv.Writer
+}
+
+func (c *csvTableWriter) WriteHeader(columns []string) error {
+	return c.writer.Write(columns)
+}
+
+func (c *csvTableWriter) WriteRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = formatCell(value)
+	}
+
+	if err := c.writer.Write(record); err != nil {
+		return err
+	}
+
+	// Flush every row so the output streams instead of piling up in memory
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+func (c *csvTableWriter) Close() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+type xlsxTableWriter struct {
+	out    io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	style  int
+	money  int
+	count  int
+	date   int
+	row    int
+}
+
+func newXLSXTableWriter(w io.Writer) (*xlsxTableWriter, error) {
+	file := excelize.NewFile()
+
+	stream, err := file.NewStreamWriter("Sheet1")
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("unable to create stream writer: %w", err)
+	}
+
+	writer := &xlsxTableWriter{out: w, file: file, stream: stream}
+
+	styles := []struct {
+		id    *int
+		style *excelize.Style
+	}{
+		{&writer.style, &excelize.Style{Font: &excelize.Font{Bold: true}}},
+		{&writer.money, &excelize.Style{CustomNumFmt: stringPtr(xlsxMoneyFormat)}},
+		{&writer.count, &excelize.Style{CustomNumFmt: stringPtr(xlsxCountFormat)}},
+		{&writer.date, &excelize.Style{CustomNumFmt: stringPtr(xlsxDateFormat)}},
+	}
+
+	for _, s := range styles {
+		if *s.id, err = file.NewStyle(s.style); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("unable to create style: %w", err)
+		}
+	}
+
+	return writer, nil
+}
+
+func stringPtr(value string) *string {
+	return &value
+}
+
+func (x *xlsxTableWriter) WriteHeader(columns []string) error {
+	// Widths can only be set before the first row is streamed
+	if err := x.stream.SetColWidth(1, max(len(columns), 1), 18); err != nil {
+		return err
+	}
+
+	values := make([]interface{}, len(columns))
+	for i, column := range columns {
+		values[i] = excelize.Cell{StyleID: x.style, Value: column}
+	}
+
+	return x.setRow(values)
+}
+
+func (x *xlsxTableWriter) WriteRow(values []interface{}) error {
+	cells := make([]interface{}, len(values))
+	for i, value := range values {
+		switch v := value.(type) {
+		case Money:
+			cells[i] = excelize.Cell{StyleID: x.money, Value: float64(v)}
+		case int, int32, int64, uint64:
+			cells[i] = excelize.Cell{StyleID: x.count, Value: v}
+		case time.Time:
+			cells[i] = excelize.Cell{StyleID: x.date, Value: v.UTC()}
+		default:
+			cells[i] = value
+		}
+	}
+
+	return x.setRow(cells)
+}
+
+func (x *xlsxTableWriter) setRow(values []interface{}) error {
+	x.row++
+
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+
+	return x.stream.SetRow(cell, values)
+}
+
+func (x *xlsxTableWriter) Close() error {
+	defer x.file.Close()
+
+	if err := x.stream.Flush(); err != nil {
+		return err
+	}
+
+	return x.file.Write(x.out)
+}
+
+func formatCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case Money:
+		return strconv.FormatFloat(float64(v), 'f', 2, 64)
+	case float64:
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', 2, 32)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
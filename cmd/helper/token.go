@@ -0,0 +1,129 @@
+package helper
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Purposes of the single use tokens given to users. The purpose is the
+// audience of the token, so a token cannot be used for anything else, nor as
+// an access token.
+const (
+	PurposePasswordReset     = "password-reset"
+	PurposeEmailVerification = "email-verification"
+	PurposeMFA               = "mfa"
+)
+
+// MFAChallengeTTL is how long a user has to type in their second factor after
+// their password
+const MFAChallengeTTL = 5 * time.Minute
+
+// PasswordResetTTL reads how long a password reset link works from
+// PASSWORD_RESET_TTL, it defaults to an hour
+func PasswordResetTTL() time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv("PASSWORD_RESET_TTL"))
+	if err != nil || ttl <= 0 {
+		return time.Hour
+	}
+
+	return ttl
+}
+
+// EmailVerificationTTL reads how long an email verification link works from
+// EMAIL_VERIFICATION_TTL, it defaults to 2 days
+func EmailVerificationTTL() time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv("EMAIL_VERIFICATION_TTL"))
+	if err != nil || ttl <= 0 {
+		return 48 * time.Hour
+	}
+
+	return ttl
+}
+
+// RequireVerifiedEmail reads REQUIRE_VERIFIED_EMAIL, when true customers have
+// to verify their email before they can check out
+func RequireVerifiedEmail() bool {
+	required, _ := strconv.ParseBool(os.Getenv("REQUIRE_VERIFIED_EMAIL"))
+	return required
+}
+
+// RequireAdminMFA reads REQUIRE_ADMIN_MFA, when true the admin routes can
+// only be used by sessions signed in with a second factor
+func RequireAdminMFA() bool {
+	required, _ := strconv.ParseBool(os.Getenv("REQUIRE_ADMIN_MFA"))
+	return required
+}
+
+// IssueActionToken signs a token that lets a user do one thing, like reset
+// their password. The ID is stored so the token can only be used once.
+func (i *Issuer) IssueActionToken(id uint64, purpose string, tokenID string, expiresAt time.Time) (string, error) {
+	now := time.Now()
+
+	claims := jwt.MapClaims{
+		"aud": purpose,
+		"nbf": now.Unix(),
+		"iat": now.Unix(),
+		"exp": expiresAt.Unix(),
+		"iss": "http://localhost:5000",
+		"sub": fmt.Sprint(id),
+		"jti": tokenID,
+	}
+
+	token := jwt.NewWithClaims(&jwt.SigningMethodEd25519{}, claims)
+
+	tokenString, err := token.SignedString(i.key)
+	if err != nil {
+		return "", fmt.Errorf("unable to sign token: %w", err)
+	}
+
+	return tokenString, nil
+}
+
+// ActionToken is a checked token issued by IssueActionToken
+type ActionToken struct {
+	ID     string
+	UserID uint64
+}
+
+// GetActionToken checks the signature, expiry and purpose of a token issued by
+// IssueActionToken
+func (v *Validator) GetActionToken(tokenString string, purpose string) (ActionToken, error) {
+	token, err := jwt.Parse(
+		tokenString,
+		func(t *jwt.Token) (interface{}, error) {
+			if _, ok := t.Method.(*jwt.SigningMethodEd25519); !ok {
+				return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+			}
+
+			return v.key, nil
+		},
+		jwt.WithAudience(purpose),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return ActionToken{}, fmt.Errorf("unable to parse token string: %w", err)
+	}
+
+	claims := token.Claims.(jwt.MapClaims)
+
+	sub, err := claims.GetSubject()
+	if err != nil {
+		return ActionToken{}, err
+	}
+
+	userID, err := strconv.ParseUint(sub, 10, 64)
+	if err != nil {
+		return ActionToken{}, fmt.Errorf("token had an invalid subject: %w", err)
+	}
+
+	id, ok := claims["jti"].(string)
+	if !ok || id == "" {
+		return ActionToken{}, fmt.Errorf("token had no ID")
+	}
+
+	return ActionToken{ID: id, UserID: userID}, nil
+}
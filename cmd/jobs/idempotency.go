@@ -0,0 +1,42 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"api/repository"
+)
+
+// IdempotencySweeper removes idempotency keys that have expired. Expired keys
+// are already ignored when a request comes in, this only keeps the table small.
+type IdempotencySweeper struct {
+	repo *repository.Queries
+}
+
+func NewIdempotencySweeper(repo *repository.Queries) *IdempotencySweeper {
+	return &IdempotencySweeper{repo: repo}
+}
+
+// Run removes expired keys every interval until the context is canceled
+func (s *IdempotencySweeper) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			removed, err := s.repo.DeleteExpiredIdempotencyKeys(ctx, time.Now())
+			if err != nil {
+				log.Printf("idempotency sweep failed: %v", err)
+				continue
+			}
+
+			if removed > 0 {
+				log.Printf("idempotency sweep removed %d keys", removed)
+			}
+		}
+	}
+}
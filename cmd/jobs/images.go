@@ -0,0 +1,178 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"api/cmd/helper"
+	"api/cmd/storage"
+	"api/repository"
+)
+
+// ImageCollector removes images that nothing refers to anymore: images rows
+// not used by any product or category, and stored files without an images row.
+// Only images older than the grace period are touched so that uploads which
+// are still in progress are left alone.
+type ImageCollector struct {
+	repo  *repository.Queries
+	blobs storage.BlobStore
+	grace time.Duration
+}
+
+type ImageReport struct {
+	DryRun bool          `json:"dry_run"`
+	Images []OrphanImage `json:"images"`
+	Files  []OrphanFile  `json:"files"`
+}
+
+type OrphanImage struct {
+	ID        uint64    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	Removed   bool      `json:"removed"`
+}
+
+type OrphanFile struct {
+	Key     string    `json:"key"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	Removed bool      `json:"removed"`
+}
+
+// ImageGCGrace reads the grace period from IMAGE_GC_GRACE, it defaults to a day
+func ImageGCGrace() time.Duration {
+	grace, err := time.ParseDuration(os.Getenv("IMAGE_GC_GRACE"))
+	if err != nil || grace <= 0 {
+		return 24 * time.Hour
+	}
+
+	return grace
+}
+
+func NewImageCollector(repo *repository.Queries, blobs storage.BlobStore, grace time.Duration) *ImageCollector {
+	return &ImageCollector{repo: repo, blobs: blobs, grace: grace}
+}
+
+// Collect finds orphaned images and, unless dryRun is set, removes them
+func (c *ImageCollector) Collect(ctx context.Context, dryRun bool) (*ImageReport, error) {
+	cutoff := time.Now().Add(-c.grace)
+	report := &ImageReport{DryRun: dryRun, Images: []OrphanImage{}, Files: []OrphanFile{}}
+
+	images, err := c.repo.FindOrphanImages(ctx, sql.NullTime{Time: cutoff, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("unable to find orphan images: %w", err)
+	}
+
+	for _, image := range images {
+		orphan := OrphanImage{ID: image.ID, Name: image.Name, CreatedAt: image.CreatedAt.Time}
+
+		if !dryRun {
+			// The row is only deleted if it is still unused at this point, and
+			// was not reused by an upload of the same image since it was found
+			deleted, err := c.repo.DeleteOrphanImage(ctx, repository.DeleteOrphanImageParams{
+				ID:        image.ID,
+				CreatedAt: sql.NullTime{Time: cutoff, Valid: true},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("unable to delete image %d: %w", image.ID, err)
+			}
+
+			if deleted > 0 {
+				if err := helper.RemoveImage(ctx, c.blobs, image.Name); err != nil {
+					return nil, fmt.Errorf("unable to remove image files %s: %w", image.Name, err)
+				}
+
+				orphan.Removed = true
+			}
+		}
+
+		report.Images = append(report.Images, orphan)
+	}
+
+	names, err := c.repo.FindImageNames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find image names: %w", err)
+	}
+
+	known := make(map[string]bool, len(names))
+	for _, name := range names {
+		known[name] = true
+	}
+
+	for _, prefix := range []string{helper.ImageKey(""), helper.ThumbnailKey("")} {
+		files, err := c.blobs.List(ctx, prefix)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range files {
+			if known[strings.TrimPrefix(file.Key, prefix)] || file.ModTime.After(cutoff) {
+				continue
+			}
+
+			orphan := OrphanFile{Key: file.Key, Size: file.Size, ModTime: file.ModTime}
+
+			if !dryRun {
+				if err := c.blobs.Delete(ctx, file.Key); err != nil {
+					return nil, fmt.Errorf("unable to remove file %s: %w", file.Key, err)
+				}
+
+				orphan.Removed = true
+			}
+
+			report.Files = append(report.Files, orphan)
+		}
+	}
+
+	return report, nil
+}
+
+// Run collects orphaned images every interval until the context is canceled
+func (c *ImageCollector) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := c.Collect(ctx, false)
+			if err != nil {
+				log.Printf("image gc failed: %v", err)
+				continue
+			}
+
+			log.Printf("image gc removed %d images and %d files", report.RemovedImages(), report.RemovedFiles())
+		}
+	}
+}
+
+// RemovedImages counts the images rows that were deleted
+func (r *ImageReport) RemovedImages() int {
+	removed := 0
+	for _, image := range r.Images {
+		if image.Removed {
+			removed++
+		}
+	}
+
+	return removed
+}
+
+// RemovedFiles counts the stored files that were deleted
+func (r *ImageReport) RemovedFiles() int {
+	removed := 0
+	for _, file := range r.Files {
+		if file.Removed {
+			removed++
+		}
+	}
+
+	return removed
+}
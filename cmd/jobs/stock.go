@@ -0,0 +1,319 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"api/cmd/helper"
+	"api/repository"
+)
+
+// StockNotifier tells someone that items have fallen to their reorder point.
+// Name tells notifiers apart in the record of which alerts each delivered.
+type StockNotifier interface {
+	Name() string
+	Notify(ctx context.Context, items []repository.FindLowStockRow) error
+}
+
+// EmailNotifier mails a list of low-stock items to every address in To
+type EmailNotifier struct {
+	To []string
+}
+
+func (n EmailNotifier) Name() string {
+	return "email"
+}
+
+func (n EmailNotifier) Notify(ctx context.Context, items []repository.FindLowStockRow) error {
+	var body strings.Builder
+
+	body.WriteString("<p>The following items are at or below their reorder point:</p><ul>")
+	for _, item := range items {
+		fmt.Fprintf(&body, "<li>%s (%s) at %s: %d left, reorder point %d, reorder %d</li>",
+			html.EscapeString(item.Name),
+			html.EscapeString(item.Sku),
+			html.EscapeString(item.Store),
+			item.Quantity,
+			item.ReorderPoint,
+			item.ReorderQuantity,
+		)
+	}
+	body.WriteString("</ul>")
+
+	subject := fmt.Sprintf("Low stock: %d items need reordering", len(items))
+
+	for _, to := range n.To {
+		if err := helper.SendEmail(to, subject, body.String()); err != nil {
+			return fmt.Errorf("unable to email %s: %w", to, err)
+		}
+	}
+
+	return nil
+}
+
+// WebhookNotifier posts the low-stock items as JSON to URL
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+func (n WebhookNotifier) Name() string {
+	return "webhook"
+}
+
+func (n WebhookNotifier) Notify(ctx context.Context, items []repository.FindLowStockRow) error {
+	data, err := json.Marshal(map[string]interface{}{
+		"event": "stock.low",
+		"items": items,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := n.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", res.StatusCode)
+	}
+
+	return nil
+}
+
+// StockNotifiers creates the notifiers configured by the STOCK_ALERT_EMAILS
+// (comma separated) and STOCK_ALERT_WEBHOOK env variables
+func StockNotifiers() []StockNotifier {
+	var notifiers []StockNotifier
+
+	var emails []string
+	for _, email := range strings.Split(os.Getenv("STOCK_ALERT_EMAILS"), ",") {
+		if email = strings.TrimSpace(email); email != "" {
+			emails = append(emails, email)
+		}
+	}
+
+	if len(emails) > 0 {
+		notifiers = append(notifiers, EmailNotifier{To: emails})
+	}
+
+	if url := os.Getenv("STOCK_ALERT_WEBHOOK"); url != "" {
+		notifiers = append(notifiers, WebhookNotifier{URL: url})
+	}
+
+	return notifiers
+}
+
+// StockChecker raises an alert the first time an item falls to its reorder
+// point in a store. The alert stays open, and no new alert is sent for the
+// item, until its stock is back above the reorder point. Each notifier is
+// recorded to have delivered an alert, so one that failed is retried on the
+// next check without the others sending it again.
+type StockChecker struct {
+	db        *sql.DB
+	notifiers []StockNotifier
+}
+
+type StockReport struct {
+	Alerted  int  `json:"alerted"`
+	Resolved int  `json:"resolved"`
+	Skipped  bool `json:"skipped"`
+}
+
+func NewStockChecker(db *sql.DB, notifiers ...StockNotifier) *StockChecker {
+	return &StockChecker{db: db, notifiers: notifiers}
+}
+
+type stockKey struct {
+	productID uint64
+	storeID   uint64
+}
+
+type deliveryKey struct {
+	alertID  uint64
+	notifier string
+}
+
+// Check compares stock levels with the open alerts, notifies about items that
+// have fallen to their reorder point and resolves the ones restocked. Only one
+// instance checks at a time, the others skip the check.
+func (c *StockChecker) Check(ctx context.Context) (*StockReport, error) {
+	// The lock belongs to a connection, so the check runs on a single one
+	conn, err := c.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	repo := repository.New(conn)
+
+	locked, err := repo.LockStockCheck(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to lock stock check: %w", err)
+	}
+
+	if locked == 0 {
+		return &StockReport{Skipped: true}, nil
+	}
+
+	defer func() {
+		if err := repo.UnlockStockCheck(context.Background()); err != nil {
+			log.Printf("unable to unlock stock check: %v", err)
+		}
+	}()
+
+	return c.check(ctx, repo)
+}
+
+func (c *StockChecker) check(ctx context.Context, repo *repository.Queries) (*StockReport, error) {
+	report := &StockReport{}
+
+	items, err := repo.FindLowStock(ctx, sql.NullInt64{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to find low stock: %w", err)
+	}
+
+	alerts, err := repo.FindOpenStockAlerts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find open alerts: %w", err)
+	}
+
+	open := make(map[stockKey]repository.StockAlert, len(alerts))
+	for _, alert := range alerts {
+		open[stockKey{alert.ProductID, alert.StoreID}] = alert
+	}
+
+	// Alerts are recorded before anyone is notified, the deliveries below
+	// tell what is left to send
+	low := make(map[uint64]repository.FindLowStockRow, len(items))
+	var order []uint64
+
+	for _, item := range items {
+		key := stockKey{item.ProductID, item.StoreID}
+
+		if alert, ok := open[key]; ok {
+			delete(open, key)
+			low[alert.ID] = item
+			order = append(order, alert.ID)
+			continue
+		}
+
+		id, err := repo.InsertStockAlert(ctx, repository.InsertStockAlertParams{
+			ProductID:    item.ProductID,
+			StoreID:      item.StoreID,
+			Quantity:     int32(item.Quantity),
+			ReorderPoint: item.ReorderPoint,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to record stock alert: %w", err)
+		}
+
+		low[uint64(id)] = item
+		order = append(order, uint64(id))
+		report.Alerted++
+	}
+
+	deliveries, err := repo.FindOpenStockAlertDeliveries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find stock alert deliveries: %w", err)
+	}
+
+	delivered := make(map[deliveryKey]bool, len(deliveries))
+	for _, d := range deliveries {
+		delivered[deliveryKey{d.AlertID, d.Notifier}] = true
+	}
+
+	var failed []error
+
+	for _, notifier := range c.notifiers {
+		var ids []uint64
+		var pending []repository.FindLowStockRow
+
+		for _, id := range order {
+			if !delivered[deliveryKey{id, notifier.Name()}] {
+				ids = append(ids, id)
+				pending = append(pending, low[id])
+			}
+		}
+
+		if len(pending) == 0 {
+			continue
+		}
+
+		// A failed notifier is retried on the next check, the others go on
+		if err := notifier.Notify(ctx, pending); err != nil {
+			failed = append(failed, fmt.Errorf("unable to send stock alert by %s: %w", notifier.Name(), err))
+			continue
+		}
+
+		for _, id := range ids {
+			err := repo.InsertStockAlertDelivery(ctx, repository.InsertStockAlertDeliveryParams{
+				AlertID:  id,
+				Notifier: notifier.Name(),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("unable to record stock alert delivery: %w", err)
+			}
+		}
+	}
+
+	// What is left open is no longer low on stock
+	for _, alert := range open {
+		if err := repo.ResolveStockAlert(ctx, alert.ID); err != nil {
+			return nil, fmt.Errorf("unable to resolve stock alert %d: %w", alert.ID, err)
+		}
+
+		report.Resolved++
+	}
+
+	return report, errors.Join(failed...)
+}
+
+// Run checks stock levels every interval until the context is canceled
+func (c *StockChecker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := c.Check(ctx)
+			if err != nil {
+				log.Printf("stock check failed: %v", err)
+			}
+
+			if report == nil {
+				continue
+			}
+
+			if report.Alerted > 0 || report.Resolved > 0 {
+				log.Printf("stock check raised %d alerts and resolved %d", report.Alerted, report.Resolved)
+			}
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package label
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"strings"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/ean"
+)
+
+const (
+	SymbologyEAN13   = "ean13"
+	SymbologyUPCA    = "upca"
+	SymbologyCode128 = "code128"
+)
+
+// internalPrefix starts the EAN-13 codes made for products without one. The
+// 200-299 range is kept for use inside a company, so it never clashes with a
+// manufacturer's code.
+const internalPrefix = "200"
+
+// CheckDigit is the GS1 check digit of an EAN or UPC code without its check
+// digit: the digits are weighted 3 and 1 alternately from the right
+func CheckDigit(digits string) int {
+	sum := 0
+
+	for i := 0; i < len(digits); i++ {
+		digit := int(digits[len(digits)-1-i] - '0')
+		if i%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+
+	return (10 - sum%10) % 10
+}
+
+func numeric(code string) bool {
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return code != ""
+}
+
+// validGTIN reports whether code has the given length and a valid check digit
+func validGTIN(code string, length int) bool {
+	if len(code) != length || !numeric(code) {
+		return false
+	}
+
+	return CheckDigit(code[:length-1]) == int(code[length-1]-'0')
+}
+
+// Detect guesses the symbology of a scanned code
+func Detect(code string) string {
+	switch {
+	case validGTIN(code, 13):
+		return SymbologyEAN13
+	case validGTIN(code, 12):
+		return SymbologyUPCA
+	default:
+		return SymbologyCode128
+	}
+}
+
+// Normalize checks code against its symbology. An EAN-13 or UPC-A code given
+// without its check digit has it added.
+func Normalize(symbology, code string) (string, error) {
+	code = strings.TrimSpace(code)
+
+	switch symbology {
+	case SymbologyEAN13, SymbologyUPCA:
+		length := 13
+		if symbology == SymbologyUPCA {
+			length = 12
+		}
+
+		if len(code) == length-1 && numeric(code) {
+			code += fmt.Sprint(CheckDigit(code))
+		}
+
+		if !validGTIN(code, length) {
+			return "", fmt.Errorf("%s should be %d digits with a valid check digit", strings.ToUpper(symbology), length)
+		}
+	case SymbologyCode128:
+		if code == "" || len(code) > 64 {
+			return "", fmt.Errorf("CODE128 should be between 1 and 64 characters")
+		}
+
+		for _, c := range code {
+			if c < 32 || c > 126 {
+				return "", fmt.Errorf("CODE128 should only contain printable ASCII characters")
+			}
+		}
+	default:
+		return "", fmt.Errorf("unsupported symbology: %s", symbology)
+	}
+
+	return code, nil
+}
+
+// Internal is the EAN-13 code made for a product from its ID
+func Internal(productID uint64) (string, error) {
+	if productID >= 1_000_000_000 {
+		return "", fmt.Errorf("product ID %d is too large for an internal EAN-13", productID)
+	}
+
+	code := fmt.Sprintf("%s%09d", internalPrefix, productID)
+
+	return code + fmt.Sprint(CheckDigit(code)), nil
+}
+
+// Encode draws code as a barcode of the given symbology
+func Encode(symbology, code string) (barcode.Barcode, error) {
+	switch symbology {
+	case SymbologyEAN13:
+		return ean.Encode(code)
+	case SymbologyUPCA:
+		// A UPC-A code is an EAN-13 code starting with 0
+		return ean.Encode("0" + code)
+	case SymbologyCode128:
+		return code128.Encode(code)
+	default:
+		return nil, fmt.Errorf("unsupported symbology: %s", symbology)
+	}
+}
+
+// PNG draws code as a PNG image of width by height pixels
+func PNG(symbology, code string, width, height int) ([]byte, error) {
+	bc, err := Encode(symbology, code)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode barcode: %w", err)
+	}
+
+	// Each bar has to be a whole number of pixels wide to scan reliably
+	modules := bc.Bounds().Dx()
+	width = max(width/modules, 1) * modules
+
+	bc, err = barcode.Scale(bc, width, height)
+	if err != nil {
+		return nil, fmt.Errorf("unable to scale barcode: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, bc); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
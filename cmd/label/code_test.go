@@ -0,0 +1,87 @@
+package label
+
+import "testing"
+
+func TestCheckDigit(t *testing.T) {
+	tests := []struct {
+		digits string
+		want   int
+	}{
+		{digits: "400638133393", want: 1},
+		{digits: "590123412345", want: 7},
+		{digits: "03600029145", want: 2},
+		{digits: "200000000001", want: 5},
+		{digits: "000000000000", want: 0},
+	}
+
+	for _, tt := range tests {
+		if got := CheckDigit(tt.digits); got != tt.want {
+			t.Errorf("CheckDigit(%s) = %d, want %d", tt.digits, got, tt.want)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name      string
+		symbology string
+		code      string
+		want      string
+		wantErr   bool
+	}{
+		{name: "EAN-13 with check digit", symbology: SymbologyEAN13, code: "4006381333931", want: "4006381333931"},
+		{name: "EAN-13 check digit added", symbology: SymbologyEAN13, code: "590123412345", want: "5901234123457"},
+		{name: "EAN-13 spaces trimmed", symbology: SymbologyEAN13, code: " 4006381333931 ", want: "4006381333931"},
+		{name: "EAN-13 wrong check digit", symbology: SymbologyEAN13, code: "4006381333932", wantErr: true},
+		{name: "EAN-13 not numeric", symbology: SymbologyEAN13, code: "40063813339a1", wantErr: true},
+		{name: "UPC-A with check digit", symbology: SymbologyUPCA, code: "036000291452", want: "036000291452"},
+		{name: "UPC-A check digit added", symbology: SymbologyUPCA, code: "03600029145", want: "036000291452"},
+		{name: "UPC-A wrong check digit", symbology: SymbologyUPCA, code: "036000291453", wantErr: true},
+		{name: "UPC-A given an EAN-13", symbology: SymbologyUPCA, code: "4006381333931", wantErr: true},
+		{name: "CODE128", symbology: SymbologyCode128, code: "SKU-42", want: "SKU-42"},
+		{name: "CODE128 empty", symbology: SymbologyCode128, code: "", wantErr: true},
+		{name: "CODE128 not printable", symbology: SymbologyCode128, code: "SKU\x0142", wantErr: true},
+		{name: "unsupported symbology", symbology: "qr", code: "4006381333931", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.symbology, tt.code)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Normalize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Normalize() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInternal(t *testing.T) {
+	tests := []struct {
+		productID uint64
+		want      string
+		wantErr   bool
+	}{
+		{productID: 1, want: "2000000000015"},
+		{productID: 123456789, want: "2001234567893"},
+		{productID: 999999999, want: "2009999999997"},
+		{productID: 1_000_000_000, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := Internal(tt.productID)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("Internal(%d) error = %v, wantErr %v", tt.productID, err, tt.wantErr)
+		}
+
+		if got != tt.want {
+			t.Errorf("Internal(%d) = %s, want %s", tt.productID, got, tt.want)
+		}
+
+		if !tt.wantErr && Detect(got) != SymbologyEAN13 {
+			t.Errorf("Internal(%d) = %s is not a valid EAN-13", tt.productID, got)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package label
+
+import (
+	"bytes"
+	"io"
+
+	"api/cmd/helper"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// Sheet layout of 21 labels of 63.5 by 38.1 mm on A4, the size of the common
+// L7160 label sheets
+const (
+	sheetColumns = 3
+	sheetRows    = 7
+	sheetLeft    = 7.2
+	sheetTop     = 15.1
+	sheetGap     = 2.5
+	labelWidth   = 63.5
+	labelHeight  = 38.1
+	labelPadding = 3.0
+	barHeight    = 16.0
+)
+
+// Label is one printed label. Price is left off when nil.
+type Label struct {
+	Name      string
+	SKU       string
+	Code      string
+	Symbology string
+	Price     *float64
+}
+
+// Write lays the labels out on as many sheets as they need
+func Write(w io.Writer, labels []Label) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+
+	translate := pdf.UnicodeTranslatorFromDescriptor("")
+	inner := labelWidth - 2*labelPadding
+	registered := map[string]bool{}
+
+	if len(labels) == 0 {
+		pdf.AddPage()
+	}
+
+	for i, l := range labels {
+		slot := i % (sheetColumns * sheetRows)
+		if slot == 0 {
+			pdf.AddPage()
+		}
+
+		x := sheetLeft + float64(slot%sheetColumns)*(labelWidth+sheetGap) + labelPadding
+		y := sheetTop + float64(slot/sheetColumns)*labelHeight + labelPadding
+
+		pdf.SetFont("Helvetica", "B", 8)
+		pdf.SetXY(x, y)
+		pdf.CellFormat(inner, 4, fit(pdf, translate(l.Name), inner), "", 0, "L", false, 0, "")
+
+		name := l.Symbology + ":" + l.Code
+		if !registered[name] {
+			code, err := PNG(l.Symbology, l.Code, 600, 160)
+			if err != nil {
+				return err
+			}
+
+			pdf.RegisterImageOptionsReader(name, fpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(code))
+			registered[name] = true
+		}
+		pdf.ImageOptions(name, x, y+5, inner, barHeight, false, fpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+
+		pdf.SetFont("Helvetica", "", 7)
+		pdf.SetXY(x, y+5+barHeight)
+		pdf.CellFormat(inner, 3.5, l.Code, "", 0, "C", false, 0, "")
+
+		pdf.SetXY(x, y+26)
+		pdf.CellFormat(inner/2, 5, fit(pdf, translate(l.SKU), inner/2), "", 0, "L", false, 0, "")
+
+		if l.Price != nil {
+			pdf.SetFont("Helvetica", "B", 11)
+			pdf.SetXY(x+inner/2, y+26)
+			pdf.CellFormat(inner/2, 5, helper.FormatMoney(*l.Price), "", 0, "R", false, 0, "")
+		}
+	}
+
+	if err := pdf.Error(); err != nil {
+		return err
+	}
+
+	return pdf.Output(w)
+}
+
+// fit shortens text with an ellipsis until it fits in width. The text has
+// already been translated to the single byte font encoding.
+func fit(pdf *fpdf.Fpdf, text string, width float64) string {
+	if pdf.GetStringWidth(text) <= width {
+		return text
+	}
+
+	for text != "" && pdf.GetStringWidth(text+"...") > width {
+		text = text[:len(text)-1]
+	}
+
+	return text + "..."
+}
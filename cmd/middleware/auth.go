@@ -2,13 +2,16 @@ package middleware
 
 import (
 	"api/cmd/helper"
+	"api/cmd/revocation"
 	"api/repository"
 	"context"
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
@@ -17,21 +20,80 @@ type tokenKey string
 
 const tokenContextKey tokenKey = "token"
 
+type storesKey string
+
+const storesContextKey storesKey = "stores"
+
+// StoreHeader picks the store a request is made for, in place of the store
+// picked at sign in
+const StoreHeader = "X-Store-ID"
+
 type Auth struct {
 	helper.Validator
+	revocations revocation.Store
+	repo        *repository.Queries
 }
 
-func NewAuth(pem string) (*Auth, error) {
+func NewAuth(pem string, revocations revocation.Store, repo *repository.Queries) (*Auth, error) {
 	validator, err := helper.NewValidator(pem)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create validator: %w", err)
 	}
 
 	return &Auth{
-		Validator: *validator,
+		Validator:   *validator,
+		revocations: revocations,
+		repo:        repo,
 	}, nil
 }
 
+// verify parses a token and checks that neither the token, its session nor
+// its user has been revoked since it was issued
+func (a *Auth) verify(ctx context.Context, tokenString string) (*jwt.Token, error) {
+	token, err := a.GetToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := token.Claims.(jwt.MapClaims)
+
+	jti, _ := claims["jti"].(string)
+	session, _ := claims["sid"].(string)
+	if jti == "" || session == "" {
+		return nil, errors.New("token has no ID or session")
+	}
+
+	sub, err := claims.GetSubject()
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := strconv.ParseUint(sub, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	issuedAt, err := claims.GetIssuedAt()
+	if err != nil || issuedAt == nil {
+		return nil, errors.New("token has no issue time")
+	}
+
+	for _, key := range []string{revocation.TokenKey(jti), revocation.SessionKey(session), revocation.UserKey(userID)} {
+		at, revoked, err := a.revocations.RevokedAt(ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("unable to check revocation: %w", err)
+		}
+
+		// iat is in whole seconds, so a token issued in the second of the
+		// revocation counts as revoked
+		if revoked && !issuedAt.After(at.Truncate(time.Second)) {
+			return nil, errors.New("token has been revoked")
+		}
+	}
+
+	return token, nil
+}
+
 func (a *Auth) HandleHTTP(h http.Handler) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		parts := strings.Split(r.Header.Get("Authorization"), " ")
@@ -46,7 +108,7 @@ func (a *Auth) HandleHTTP(h http.Handler) http.HandlerFunc {
 
 		tokenString := parts[1]
 
-		token, err := a.GetToken(tokenString)
+		token, err := a.verify(r.Context(), tokenString)
 		if err != nil {
 			w.WriteHeader(http.StatusUnauthorized)
 			w.Write([]byte("Unauthorised"))
@@ -63,6 +125,12 @@ func (a *Auth) HandleHTTP(h http.Handler) http.HandlerFunc {
 
 func (a *Auth) AuthJWT(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A router guarded by RequireMFA has checked the token already
+		if _, err := ContextGetToken(r.Context()); err == nil {
+			h.ServeHTTP(w, r)
+			return
+		}
+
 		parts := strings.Split(r.Header.Get("Authorization"), " ")
 
 		if len(parts) < 2 || parts[0] != "Bearer" {
@@ -75,7 +143,7 @@ func (a *Auth) AuthJWT(h http.Handler) http.Handler {
 
 		tokenString := parts[1]
 
-		token, err := a.GetToken(tokenString)
+		token, err := a.verify(r.Context(), tokenString)
 		if err != nil {
 			w.WriteHeader(http.StatusUnauthorized)
 			w.Write([]byte("Unauthorised"))
@@ -117,7 +185,8 @@ func MustContextGetToken(ctx context.Context) (*jwt.Token, error) {
 	return t, nil
 }
 
-func GuardAdmin(ctx context.Context, repo *repository.Queries) (uint64, error) {
+// UserID is the ID of the user the token in the context was issued to
+func UserID(ctx context.Context) (uint64, error) {
 	token, err := MustContextGetToken(ctx)
 	if err != nil {
 		return 0, err
@@ -128,75 +197,210 @@ func GuardAdmin(ctx context.Context, repo *repository.Queries) (uint64, error) {
 		return 0, err
 	}
 
-	id, err := strconv.ParseUint(sub, 10, 64)
-	if err != nil {
-		return 0, err
-	}
+	return strconv.ParseUint(sub, 10, 64)
+}
 
-	isAdmin, err := repo.CheckUserRole(context.Background(), repository.CheckUserRoleParams{UserID: id, RoleID: 1})
-	if err != nil {
-		return 0, err
+// RequirePermission lets a request through only when one of the user's roles
+// grants every listed permission. It has to run after AuthJWT.
+func (a *Auth) RequirePermission(permissions ...string) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, err := UserID(r.Context())
+			if err != nil {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			for _, permission := range permissions {
+				allowed, err := a.repo.CheckUserPermission(r.Context(), repository.CheckUserPermissionParams{
+					UserID: userID,
+					Name:   permission,
+				})
+				if err != nil {
+					fmt.Println(err)
+					http.Error(w, "Something went wrong", http.StatusInternalServerError)
+					return
+				}
+
+				if !allowed {
+					http.Error(w, "Forbidden", http.StatusForbidden)
+					return
+				}
+			}
+
+			h.ServeHTTP(w, r)
+		})
 	}
+}
 
-	if !isAdmin {
-		return 0, fmt.Errorf("user is not an admin")
-	}
+// StoreScope is the stores a request may act on. All is set when the caller
+// is not limited to some stores and did not pick one.
+type StoreScope struct {
+	All    bool
+	Stores []uint64
+}
 
-	return id, nil
+// Allows reports whether the scope covers a store
+func (s StoreScope) Allows(storeID uint64) bool {
+	return s.All || slices.Contains(s.Stores, storeID)
 }
 
-func GuardCustomer(ctx context.Context, repo *repository.Queries) (uint64, error) {
-	token, err := MustContextGetToken(ctx)
-	if err != nil {
-		return 0, err
+// ContextGetStores returns the scope set by RequireStorePermission. Routes
+// guarded by RequirePermission are granted everywhere, so they see every store.
+func ContextGetStores(ctx context.Context) StoreScope {
+	scope, ok := ctx.Value(storesContextKey).(StoreScope)
+	if !ok {
+		return StoreScope{All: true}
 	}
 
-	sub, err := token.Claims.GetSubject()
-	if err != nil {
-		return 0, err
-	}
+	return scope
+}
 
-	id, err := strconv.ParseUint(sub, 10, 64)
-	if err != nil {
-		return 0, err
+// SelectedStore is the store picked with the X-Store-ID header, or else the
+// store picked at sign in
+func SelectedStore(r *http.Request) (uint64, bool, error) {
+	if header := r.Header.Get(StoreHeader); header != "" {
+		id, err := strconv.ParseUint(header, 10, 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid %s header", StoreHeader)
+		}
+
+		return id, true, nil
 	}
 
-	isAdmin, err := repo.CheckUserRole(context.Background(), repository.CheckUserRoleParams{UserID: id, RoleID: 2})
+	token, err := MustContextGetToken(r.Context())
 	if err != nil {
-		return 0, err
+		return 0, false, err
 	}
 
-	if !isAdmin {
-		return 0, fmt.Errorf("user is not a customer")
+	// JSON numbers are decoded as float64
+	store, ok := token.Claims.(jwt.MapClaims)["store"].(float64)
+	if !ok || store < 1 {
+		return 0, false, nil
 	}
 
-	return id, nil
+	return uint64(store), true, nil
 }
 
-func GuardCashier(ctx context.Context, repo *repository.Queries) (uint64, error) {
-	token, err := MustContextGetToken(ctx)
-	if err != nil {
-		return 0, err
+// RequireStorePermission is RequirePermission for routes that filter what
+// they return by store. A permission may also come from the user's role at a
+// store, and the stores the request may act on are put in the context for
+// ContextGetStores. A picked store narrows the scope to that store.
+func (a *Auth) RequireStorePermission(permissions ...string) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			userID, err := UserID(ctx)
+			if err != nil {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			selected, picked, err := SelectedStore(r)
+			if err != nil {
+				http.Error(w, "Invalid store ID", http.StatusBadRequest)
+				return
+			}
+
+			scope := StoreScope{All: true}
+
+			for _, permission := range permissions {
+				allowed, err := a.repo.CheckUserPermission(ctx, repository.CheckUserPermissionParams{
+					UserID: userID,
+					Name:   permission,
+				})
+				if err != nil {
+					fmt.Println(err)
+					http.Error(w, "Something went wrong", http.StatusInternalServerError)
+					return
+				}
+
+				if allowed {
+					continue
+				}
+
+				stores, err := a.repo.FindUserPermissionStores(ctx, repository.FindUserPermissionStoresParams{
+					UserID: userID,
+					Name:   permission,
+				})
+				if err != nil {
+					fmt.Println(err)
+					http.Error(w, "Something went wrong", http.StatusInternalServerError)
+					return
+				}
+
+				if !scope.All {
+					stores = slices.DeleteFunc(stores, func(id uint64) bool {
+						return !slices.Contains(scope.Stores, id)
+					})
+				}
+
+				if len(stores) == 0 {
+					http.Error(w, "Forbidden", http.StatusForbidden)
+					return
+				}
+
+				scope = StoreScope{Stores: stores}
+			}
+
+			if picked {
+				if !scope.Allows(selected) {
+					http.Error(w, "Forbidden", http.StatusForbidden)
+					return
+				}
+
+				scope = StoreScope{Stores: []uint64{selected}}
+			}
+
+			h.ServeHTTP(w, r.WithContext(context.WithValue(ctx, storesContextKey, scope)))
+		})
 	}
+}
 
-	sub, err := token.Claims.GetSubject()
-	if err != nil {
-		return 0, err
-	}
+// RequireVerified lets a request through only when the user has verified
+// their email address or, for users who signed up with one, their phone. It
+// has to run after AuthJWT.
+func (a *Auth) RequireVerified(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, err := UserID(r.Context())
+		if err != nil {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
 
-	id, err := strconv.ParseUint(sub, 10, 64)
-	if err != nil {
-		return 0, err
-	}
+		user, err := a.repo.FindUserByID(r.Context(), userID)
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
 
-	isCashier, err := repo.CheckUserRole(context.Background(), repository.CheckUserRoleParams{UserID: id, RoleID: 3})
-	if err != nil {
-		return 0, err
-	}
+		if !user.EmailVerifiedAt.Valid && !user.PhoneVerifiedAt.Valid {
+			http.Error(w, "Verify your email address or phone first", http.StatusForbidden)
+			return
+		}
 
-	if !isCashier {
-		return 0, fmt.Errorf("user is not a cashier")
-	}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// RequireMFA lets a request through only when its session was signed in with
+// a second factor. It checks the token itself, so it can guard a whole router
+// ahead of AuthJWT.
+func (a *Auth) RequireMFA(h http.Handler) http.Handler {
+	return a.AuthJWT(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, err := ContextGetToken(r.Context())
+		if err != nil {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		if mfa, _ := token.Claims.(jwt.MapClaims)["mfa"].(bool); !mfa {
+			http.Error(w, "Two-factor authentication required", http.StatusForbidden)
+			return
+		}
 
-	return id, nil
+		h.ServeHTTP(w, r)
+	}))
 }
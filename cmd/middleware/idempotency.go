@@ -0,0 +1,222 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"api/repository"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const IdempotencyHeader = "Idempotency-Key"
+
+// idempotencyProcessingTimeout is how long a key may wait for its first
+// response. A key still waiting after that was left behind by a request that
+// never finished, such as one whose process died, and goes to the next one.
+const idempotencyProcessingTimeout = 5 * time.Minute
+
+// Idempotency makes a request safe to retry. The first response to a key is
+// stored and replayed for retries that send the same key with the same
+// request, so a dropped connection cannot create an order or purchase twice.
+// Keys are scoped to the user and must run after AuthJWT.
+type Idempotency struct {
+	repo *repository.Queries
+	ttl  time.Duration
+}
+
+// IdempotencyKeyTTL reads how long keys are kept from IDEMPOTENCY_KEY_TTL, it
+// defaults to a day
+func IdempotencyKeyTTL() time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv("IDEMPOTENCY_KEY_TTL"))
+	if err != nil || ttl <= 0 {
+		return 24 * time.Hour
+	}
+
+	return ttl
+}
+
+func NewIdempotency(repo *repository.Queries, ttl time.Duration) *Idempotency {
+	return &Idempotency{repo: repo, ttl: ttl}
+}
+
+func (i *Idempotency) Handle(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyHeader)
+		if key == "" {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := r.Context()
+
+		if len(key) > 255 {
+			http.Error(w, "Idempotency-Key should not exceed 255 characters", http.StatusBadRequest)
+			return
+		}
+
+		userID, err := UserID(ctx)
+		if err != nil {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := requestHash(r, body)
+
+		stored, err := i.repo.FindIdempotencyKey(ctx, repository.FindIdempotencyKeyParams{
+			UserID:         userID,
+			IdempotencyKey: key,
+		})
+		if err == nil && (stored.ExpiresAt.Before(time.Now()) || abandoned(stored)) {
+			if err := i.repo.DeleteIdempotencyKey(ctx, stored.ID); err != nil {
+				fmt.Println(err)
+				http.Error(w, "Something went wrong", http.StatusInternalServerError)
+				return
+			}
+			err = sql.ErrNoRows
+		}
+
+		switch {
+		case err == nil:
+			replay(w, stored, hash)
+			return
+		case err != sql.ErrNoRows:
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		id, err := i.repo.InsertIdempotencyKey(ctx, repository.InsertIdempotencyKeyParams{
+			UserID:         userID,
+			IdempotencyKey: key,
+			Method:         r.Method,
+			Path:           r.URL.Path,
+			RequestHash:    hash,
+			ExpiresAt:      time.Now().Add(i.ttl),
+		})
+
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+			// Another request with this key got in first
+			http.Error(w, "A request with this Idempotency-Key is still being processed", http.StatusConflict)
+			return
+		}
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		// The response is recorded even when the client has gone away
+		done := context.WithoutCancel(ctx)
+
+		// A handler that panics leaves no response, so the key is freed for
+		// the request to be tried again
+		defer func() {
+			if p := recover(); p != nil {
+				if err := i.repo.DeleteIdempotencyKey(done, uint64(id)); err != nil {
+					fmt.Println(err)
+				}
+				panic(p)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(recorder, r)
+
+		// Server errors are not stored so the request can be tried again
+		if recorder.status >= http.StatusInternalServerError {
+			if err := i.repo.DeleteIdempotencyKey(done, uint64(id)); err != nil {
+				fmt.Println(err)
+			}
+			return
+		}
+
+		err = i.repo.SaveIdempotencyResponse(done, repository.SaveIdempotencyResponseParams{
+			StatusCode:   sql.NullInt32{Int32: int32(recorder.status), Valid: true},
+			ContentType:  sql.NullString{String: recorder.Header().Get("Content-Type"), Valid: true},
+			ResponseBody: recorder.body.Bytes(),
+			ID:           uint64(id),
+		})
+		if err != nil {
+			fmt.Println(err)
+		}
+	})
+}
+
+// replay writes the stored response of a key, unless the key was sent with a
+// different request or the first request has not finished yet
+func replay(w http.ResponseWriter, stored repository.IdempotencyKey, hash string) {
+	if stored.RequestHash != hash {
+		http.Error(w, "Idempotency-Key has already been used for a different request", http.StatusConflict)
+		return
+	}
+
+	if !stored.StatusCode.Valid {
+		http.Error(w, "A request with this Idempotency-Key is still being processed", http.StatusConflict)
+		return
+	}
+
+	if stored.ContentType.String != "" {
+		w.Header().Set("Content-Type", stored.ContentType.String)
+	}
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(int(stored.StatusCode.Int32))
+	w.Write(stored.ResponseBody)
+}
+
+// abandoned tells whether a key has waited for its first response for longer
+// than any request takes
+func abandoned(stored repository.IdempotencyKey) bool {
+	return !stored.StatusCode.Valid && stored.CreatedAt.Valid &&
+		time.Since(stored.CreatedAt.Time) > idempotencyProcessingTimeout
+}
+
+// requestHash identifies a request by its method, path and body
+func requestHash(r *http.Request, body []byte) string {
+	sum := sha256.New()
+	sum.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	sum.Write(body)
+
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+// responseRecorder passes a response through while keeping a copy of it
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(b)
+
+	return r.ResponseWriter.Write(b)
+}
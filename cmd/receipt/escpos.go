@@ -0,0 +1,143 @@
+package receipt
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// ESC/POS commands understood by 58 and 80mm thermal printers
+var (
+	escInit        = []byte{0x1b, 0x40}
+	escAlignLeft   = []byte{0x1b, 0x61, 0x00}
+	escAlignCenter = []byte{0x1b, 0x61, 0x01}
+	escBoldOn      = []byte{0x1b, 0x45, 0x01}
+	escBoldOff     = []byte{0x1b, 0x45, 0x00}
+	escLargeOn     = []byte{0x1d, 0x21, 0x11}
+	escLargeOff    = []byte{0x1d, 0x21, 0x00}
+	escFeedCut     = []byte{0x1d, 0x56, 0x42, 0x03}
+)
+
+type escposWriter struct {
+	out     *bufio.Writer
+	columns int
+	err     error
+}
+
+func (e *escposWriter) write(b []byte) {
+	if e.err == nil {
+		_, e.err = e.out.Write(b)
+	}
+}
+
+func (e *escposWriter) text(s string) {
+	e.write([]byte(ascii(s) + "\n"))
+}
+
+func (e *escposWriter) row(r row) {
+	if r.Rule {
+		e.text(strings.Repeat("-", e.columns))
+		return
+	}
+
+	if r.Center {
+		e.write(escAlignCenter)
+	}
+	if r.Bold {
+		e.write(escBoldOn)
+	}
+	if r.Large {
+		e.write(escLargeOn)
+	}
+
+	if r.Right == "" {
+		e.text(r.Left)
+	} else {
+		e.text(pair(r.Left, r.Right, e.columns))
+	}
+
+	if r.Large {
+		e.write(escLargeOff)
+	}
+	if r.Bold {
+		e.write(escBoldOff)
+	}
+	if r.Center {
+		e.write(escAlignLeft)
+	}
+}
+
+// qr prints a QR code with the printer's own QR code support (GS ( k), which
+// is sharper than printing an image
+func (e *escposWriter) qr(content string, size byte) {
+	data := []byte(content)
+	length := len(data) + 3
+
+	e.write(escAlignCenter)
+	e.write([]byte{0x1d, 0x28, 0x6b, 0x04, 0x00, 0x31, 0x41, 0x32, 0x00})
+	e.write([]byte{0x1d, 0x28, 0x6b, 0x03, 0x00, 0x31, 0x43, size})
+	e.write([]byte{0x1d, 0x28, 0x6b, 0x03, 0x00, 0x31, 0x45, 0x31})
+	e.write([]byte{0x1d, 0x28, 0x6b, byte(length % 256), byte(length / 256), 0x31, 0x50, 0x30})
+	e.write(data)
+	e.write([]byte{0x1d, 0x28, 0x6b, 0x03, 0x00, 0x31, 0x51, 0x30})
+	e.write([]byte("\n"))
+	e.write(escAlignLeft)
+}
+
+func writeESCPOS(w io.Writer, r Receipt) error {
+	e := &escposWriter{out: bufio.NewWriter(w), columns: r.Width.Columns()}
+
+	e.write(escInit)
+
+	for _, row := range r.top() {
+		e.row(row)
+	}
+
+	if r.LookupURL != "" {
+		size := byte(6)
+		if r.Width == Width58 {
+			size = 4
+		}
+
+		e.write([]byte("\n"))
+		e.qr(r.LookupURL, size)
+	}
+
+	for _, row := range r.bottom() {
+		e.row(row)
+	}
+
+	e.write(escFeedCut)
+
+	if e.err != nil {
+		return e.err
+	}
+
+	return e.out.Flush()
+}
+
+// pair puts left and right on one line of columns characters, shortening
+// left when both do not fit
+func pair(left, right string, columns int) string {
+	space := columns - len([]rune(right)) - 1
+	if space < 0 {
+		space = 0
+	}
+
+	runes := []rune(left)
+	if len(runes) > space {
+		runes = runes[:space]
+	}
+
+	return string(runes) + strings.Repeat(" ", max(columns-len(runes)-len([]rune(right)), 1)) + right
+}
+
+// ascii replaces the characters a printer's default code page may not have
+func ascii(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e {
+			return '?'
+		}
+		return r
+	}, s)
+}
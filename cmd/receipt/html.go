@@ -0,0 +1,66 @@
+package receipt
+
+import (
+	"encoding/base64"
+	"html/template"
+	"io"
+)
+
+var htmlTemplate = template.Must(template.New("receipt").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Receipt {{.Number}}</title>
+<style>
+body { margin: 0; }
+.receipt { width: {{.Width}}mm; margin: 0 auto; padding: 3mm; font-family: "Courier New", monospace; font-size: 12px; }
+.row { display: flex; justify-content: space-between; white-space: pre; }
+.center { justify-content: center; text-align: center; }
+.bold { font-weight: bold; }
+.large { font-size: 20px; }
+hr { border: 0; border-top: 1px dashed #000; }
+.qr { text-align: center; margin: 8px 0; }
+.qr img { width: 28mm; height: 28mm; }
+</style>
+</head>
+<body>
+<div class="receipt">
+{{range .Top}}{{template "row" .}}{{end}}
+{{if .QR}}<div class="qr"><a href="{{.LookupURL}}"><img src="{{.QR}}" alt="{{.LookupURL}}"></a></div>{{end}}
+{{range .Bottom}}{{template "row" .}}{{end}}
+</div>
+</body>
+</html>
+{{define "row"}}{{if .Rule}}<hr>
+{{else}}<div class="row{{if .Center}} center{{end}}{{if .Bold}} bold{{end}}{{if .Large}} large{{end}}"><span>{{.Left}}</span>{{if .Right}}<span>{{.Right}}</span>{{end}}</div>
+{{end}}{{end}}`))
+
+// writeHTML renders the receipt as a page that reads like the printed receipt,
+// with the QR code inlined so the page can be sent as an email body
+func writeHTML(w io.Writer, r Receipt) error {
+	data := struct {
+		Number    string
+		Width     int
+		Top       []row
+		Bottom    []row
+		LookupURL string
+		QR        template.URL
+	}{
+		Number:    r.Number,
+		Width:     int(r.Width),
+		Top:       r.top(),
+		Bottom:    r.bottom(),
+		LookupURL: r.LookupURL,
+	}
+
+	if r.LookupURL != "" {
+		code, err := QRCode(r.LookupURL, 256)
+		if err != nil {
+			return err
+		}
+
+		data.QR = template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(code))
+	}
+
+	return htmlTemplate.Execute(w, data)
+}
@@ -0,0 +1,102 @@
+package receipt
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	pdfMargin     = 3.0
+	pdfLineHeight = 4.0
+	pdfQRSize     = 28.0
+)
+
+// writePDF lays the receipt out on a page as wide as the paper roll and as
+// long as the receipt, in a monospaced font sized so a line holds the same
+// number of characters as the printer
+func writePDF(w io.Writer, r Receipt) error {
+	top, bottom := r.top(), r.bottom()
+
+	width := float64(r.Width)
+	height := 2 * pdfMargin
+	for _, row := range append(top, bottom...) {
+		height += row.height()
+	}
+	if r.LookupURL != "" {
+		height += pdfQRSize + 2*pdfLineHeight
+	}
+
+	pdf := fpdf.NewCustom(&fpdf.InitType{
+		OrientationStr: "P",
+		UnitStr:        "mm",
+		Size:           fpdf.SizeType{Wd: width, Ht: height},
+	})
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.AddPage()
+
+	translate := pdf.UnicodeTranslatorFromDescriptor("")
+	inner := width - 2*pdfMargin
+
+	// Courier characters are 0.6 of the font size wide
+	fontSize := inner / float64(r.Width.Columns()) / 0.6 * 72 / 25.4
+
+	writeRows := func(rows []row) {
+		for _, row := range rows {
+			if row.Rule {
+				y := pdf.GetY() + pdfLineHeight/2
+				pdf.Line(pdfMargin, y, width-pdfMargin, y)
+				pdf.Ln(pdfLineHeight)
+				continue
+			}
+
+			style := ""
+			if row.Bold {
+				style = "B"
+			}
+
+			size := fontSize
+			if row.Large {
+				size *= 2
+			}
+			pdf.SetFont("Courier", style, size)
+
+			if row.Center {
+				pdf.CellFormat(inner, row.height(), translate(row.Left), "", 1, "C", false, 0, "")
+				continue
+			}
+
+			pdf.CellFormat(inner, row.height(), translate(row.Left), "", 0, "L", false, 0, "")
+			pdf.SetX(pdfMargin)
+			pdf.CellFormat(inner, row.height(), translate(row.Right), "", 1, "R", false, 0, "")
+		}
+	}
+
+	writeRows(top)
+
+	if r.LookupURL != "" {
+		code, err := QRCode(r.LookupURL, 256)
+		if err != nil {
+			return err
+		}
+
+		options := fpdf.ImageOptions{ImageType: "PNG"}
+		pdf.RegisterImageOptionsReader("qr", options, bytes.NewReader(code))
+		pdf.ImageOptions("qr", (width-pdfQRSize)/2, pdf.GetY()+pdfLineHeight, pdfQRSize, pdfQRSize, false, options, 0, "")
+		pdf.SetY(pdf.GetY() + pdfQRSize + 2*pdfLineHeight)
+	}
+
+	writeRows(bottom)
+
+	return pdf.Output(w)
+}
+
+func (r row) height() float64 {
+	if r.Large {
+		return 2 * pdfLineHeight
+	}
+
+	return pdfLineHeight
+}
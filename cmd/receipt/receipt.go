@@ -0,0 +1,280 @@
+package receipt
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"api/cmd/helper"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/qr"
+)
+
+const (
+	FormatESCPOS = "escpos"
+	FormatPDF    = "pdf"
+	FormatHTML   = "html"
+)
+
+// Width is the paper width of a thermal printer in millimetres
+type Width int
+
+const (
+	Width58 Width = 58
+	Width80 Width = 80
+)
+
+func (w Width) Valid() bool {
+	return w == Width58 || w == Width80
+}
+
+// Columns is the number of characters of the printer's default font that
+// fit on one line
+func (w Width) Columns() int {
+	if w == Width58 {
+		return 32
+	}
+
+	return 48
+}
+
+type Line struct {
+	SKU      string
+	Name     string
+	Quantity int32
+	Price    float64
+	Total    float64
+}
+
+// Receipt is everything printed on a receipt. Prices include tax, so the tax
+// shown is the part of the total that is tax.
+type Receipt struct {
+	Store     string
+	Header    []string
+	Footer    []string
+	Number    string
+	Date      time.Time
+	Cashier   string
+	Lines     []Line
+	Total     float64
+	TaxLabel  string
+	TaxRate   float64
+	Tender    string
+	Tendered  float64
+	LookupURL string
+	Width     Width
+}
+
+func round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// Tax is the tax included in the total
+func (r Receipt) Tax() float64 {
+	if r.TaxRate <= 0 {
+		return 0
+	}
+
+	return round(r.Total - r.Total/(1+r.TaxRate/100))
+}
+
+// Change is the cash handed back, when more than the total was tendered
+func (r Receipt) Change() float64 {
+	if r.Tendered <= r.Total {
+		return 0
+	}
+
+	return round(r.Tendered - r.Total)
+}
+
+// Write renders the receipt as an ESC/POS byte stream, a PDF or an HTML page
+func Write(w io.Writer, r Receipt, format string) error {
+	if !r.Width.Valid() {
+		r.Width = Width80
+	}
+
+	switch format {
+	case FormatESCPOS:
+		return writeESCPOS(w, r)
+	case FormatPDF:
+		return writePDF(w, r)
+	case FormatHTML:
+		return writeHTML(w, r)
+	default:
+		return fmt.Errorf("unsupported receipt format")
+	}
+}
+
+// ContentType returns the MIME type of a receipt format
+func ContentType(format string) string {
+	switch format {
+	case FormatPDF:
+		return "application/pdf"
+	case FormatHTML:
+		return "text/html; charset=utf-8"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// QRCode encodes content as a PNG image of size by size pixels
+func QRCode(content string, size int) ([]byte, error) {
+	code, err := qr.Encode(content, qr.M, qr.Auto)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode QR code: %w", err)
+	}
+
+	code, err = barcode.Scale(code, size, size)
+	if err != nil {
+		return nil, fmt.Errorf("unable to scale QR code: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, code); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// row is one line of a receipt. Every format lays out the same rows, so the
+// printed, PDF and HTML receipts always match.
+type row struct {
+	Left   string
+	Right  string
+	Center bool
+	Bold   bool
+	Large  bool
+	Rule   bool
+}
+
+// top is everything printed above the QR code
+func (r Receipt) top() []row {
+	columns := r.Width.Columns()
+
+	var rows []row
+
+	// Large text is twice as wide, so half as many characters fit
+	for _, text := range wrap(r.Store, columns/2) {
+		rows = append(rows, row{Left: text, Center: true, Bold: true, Large: true})
+	}
+
+	for _, line := range r.Header {
+		for _, text := range wrap(line, columns) {
+			rows = append(rows, row{Left: text, Center: true})
+		}
+	}
+
+	rows = append(rows,
+		row{Rule: true},
+		row{Left: "Order", Right: r.Number},
+		row{Left: "Date", Right: r.Date.UTC().Format("2006-01-02 15:04")},
+	)
+
+	if r.Cashier != "" {
+		rows = append(rows, row{Left: "Cashier", Right: r.Cashier})
+	}
+
+	rows = append(rows, row{Rule: true})
+
+	for _, line := range r.Lines {
+		for _, text := range wrap(line.Name, columns) {
+			rows = append(rows, row{Left: text})
+		}
+
+		rows = append(rows, row{
+			Left:  fmt.Sprintf("  %d x %s", line.Quantity, helper.FormatMoney(line.Price)),
+			Right: helper.FormatMoney(line.Total),
+		})
+	}
+
+	rows = append(rows, row{Rule: true})
+
+	if tax := r.Tax(); tax > 0 {
+		rows = append(rows,
+			row{Left: "Subtotal", Right: helper.FormatMoney(r.Total - tax)},
+			row{Left: fmt.Sprintf("%s %s%%", r.TaxLabel, strconv.FormatFloat(r.TaxRate, 'f', -1, 64)), Right: helper.FormatMoney(tax)},
+		)
+	}
+
+	rows = append(rows, row{Left: "TOTAL", Right: helper.FormatMoney(r.Total), Bold: true})
+
+	tendered := r.Tendered
+	if tendered < r.Total {
+		tendered = r.Total
+	}
+
+	rows = append(rows, row{Left: tenderName(r.Tender), Right: helper.FormatMoney(tendered)})
+
+	if change := r.Change(); change > 0 {
+		rows = append(rows, row{Left: "Change", Right: helper.FormatMoney(change)})
+	}
+
+	return rows
+}
+
+// bottom is everything printed below the QR code
+func (r Receipt) bottom() []row {
+	var rows []row
+
+	for _, line := range r.Footer {
+		for _, text := range wrap(line, r.Width.Columns()) {
+			rows = append(rows, row{Left: text, Center: true})
+		}
+	}
+
+	return rows
+}
+
+func tenderName(tender string) string {
+	switch tender {
+	case "card":
+		return "Card"
+	case "mobile_money":
+		return "Mobile money"
+	default:
+		return "Cash"
+	}
+}
+
+// wrap breaks text into lines of at most columns characters, at spaces where
+// it can
+func wrap(text string, columns int) []string {
+	var lines []string
+	var line string
+
+	for _, word := range strings.Fields(text) {
+		for len([]rune(word)) > columns {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+
+			runes := []rune(word)
+			lines = append(lines, string(runes[:columns]))
+			word = string(runes[columns:])
+		}
+
+		switch {
+		case line == "":
+			line = word
+		case len([]rune(line))+1+len([]rune(word)) <= columns:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+
+	if line != "" {
+		lines = append(lines, line)
+	}
+
+	return lines
+}
@@ -0,0 +1,80 @@
+package revocation
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// missTTL is how long a key found not to be revoked is remembered. A
+// revocation made on another instance takes at most this long to apply here.
+const missTTL = 5 * time.Second
+
+// Cached keeps the answers of a slower store in memory so that checking a
+// token does not go over the network on every request
+type Cached struct {
+	store Store
+	hits  *Memory
+
+	mu     sync.Mutex
+	misses map[string]time.Time
+	swept  time.Time
+}
+
+func NewCached(store Store) *Cached {
+	return &Cached{store: store, hits: NewMemory(), misses: map[string]time.Time{}}
+}
+
+func (c *Cached) Revoke(ctx context.Context, key string, at time.Time, ttl time.Duration) error {
+	if err := c.store.Revoke(ctx, key, at, ttl); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	delete(c.misses, key)
+	c.mu.Unlock()
+
+	return c.hits.Revoke(ctx, key, at, ttl)
+}
+
+func (c *Cached) RevokedAt(ctx context.Context, key string) (time.Time, bool, error) {
+	if at, ok, _ := c.hits.RevokedAt(ctx, key); ok {
+		return at, true, nil
+	}
+
+	now := time.Now()
+
+	c.mu.Lock()
+	until, ok := c.misses[key]
+	c.mu.Unlock()
+
+	if ok && now.Before(until) {
+		return time.Time{}, false, nil
+	}
+
+	at, revoked, err := c.store.RevokedAt(ctx, key)
+	if err != nil {
+		return at, revoked, err
+	}
+
+	if revoked {
+		// Revocations are kept until the tokens they cover expire, which the
+		// store knows and the cache does not, so they are remembered briefly too
+		c.hits.Revoke(ctx, key, at, missTTL)
+		return at, true, nil
+	}
+
+	c.mu.Lock()
+	if now.Sub(c.swept) > missTTL {
+		for k, until := range c.misses {
+			if now.After(until) {
+				delete(c.misses, k)
+			}
+		}
+		c.swept = now
+	}
+	c.misses[key] = now.Add(missTTL)
+	c.mu.Unlock()
+
+	return at, false, nil
+}
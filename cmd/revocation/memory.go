@@ -0,0 +1,53 @@
+package revocation
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Memory keeps revocations in the memory of one instance. They are lost on a
+// restart; refresh tokens are revoked in the database, so a revoked session
+// still ends once its access token expires.
+type Memory struct {
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+type entry struct {
+	at      time.Time
+	expires time.Time
+}
+
+func NewMemory() *Memory {
+	return &Memory{entries: map[string]entry{}}
+}
+
+func (m *Memory) Revoke(ctx context.Context, key string, at time.Time, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+
+	// Revocations are rare, so expired ones are cleared out as new ones come in
+	for k, e := range m.entries {
+		if now.After(e.expires) {
+			delete(m.entries, k)
+		}
+	}
+
+	m.entries[key] = entry{at: at, expires: now.Add(ttl)}
+	return nil
+}
+
+func (m *Memory) RevokedAt(ctx context.Context, key string) (time.Time, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[key]
+	if !ok || time.Now().After(e.expires) {
+		return time.Time{}, false, nil
+	}
+
+	return e.at, true, nil
+}
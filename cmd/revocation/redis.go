@@ -0,0 +1,52 @@
+package revocation
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis keeps revocations in Redis, shared by every instance
+type Redis struct {
+	client *redis.Client
+}
+
+func NewRedis(addr, password string) (*Redis, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("unable to connect to redis: %w", err)
+	}
+
+	return &Redis{client: client}, nil
+}
+
+func (r *Redis) Revoke(ctx context.Context, key string, at time.Time, ttl time.Duration) error {
+	return r.client.Set(ctx, key, at.UnixNano(), ttl).Err()
+}
+
+func (r *Redis) RevokedAt(ctx context.Context, key string) (time.Time, bool, error) {
+	value, err := r.client.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	nanos, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid revocation %s: %w", key, err)
+	}
+
+	return time.Unix(0, nanos), true, nil
+}
@@ -0,0 +1,47 @@
+package revocation
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Store keeps what has been revoked, and when, until the tokens it covers
+// have expired anyway. Keys are made with TokenKey, SessionKey and UserKey.
+type Store interface {
+	Revoke(ctx context.Context, key string, at time.Time, ttl time.Duration) error
+	RevokedAt(ctx context.Context, key string) (time.Time, bool, error)
+}
+
+// TokenKey revokes a single access token by its jti
+func TokenKey(jti string) string {
+	return "revoked:token:" + jti
+}
+
+// SessionKey revokes every access token of a session
+func SessionKey(session string) string {
+	return "revoked:session:" + session
+}
+
+// UserKey revokes every access token of a user issued up to the time of
+// revocation
+func UserKey(userID uint64) string {
+	return fmt.Sprintf("revoked:user:%d", userID)
+}
+
+// New uses Redis when REDIS is set, so revocations reach every instance, with
+// an in-memory cache in front of it. Otherwise revocations are kept in memory.
+func New() (Store, error) {
+	addr := os.Getenv("REDIS")
+	if addr == "" {
+		return NewMemory(), nil
+	}
+
+	store, err := NewRedis(addr, os.Getenv("REDIS_PSWD"))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCached(store), nil
+}
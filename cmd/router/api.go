@@ -3,15 +3,26 @@ package router
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
+	"path"
+	"strconv"
+	"strings"
 	"time"
 
 	mid "api/cmd/middleware"
 
+	"api/cmd/costing"
 	"api/cmd/helper"
+	"api/cmd/revocation"
+	"api/cmd/sms"
+	"api/cmd/storage"
+	"api/cmd/throttle"
+	"api/repository"
 
 	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/chi/v5"
@@ -19,13 +30,19 @@ import (
 )
 
 type API struct {
-	db     *sql.DB
-	auth   *mid.Auth
-	issuer *helper.Issuer
+	db          *sql.DB
+	auth        *mid.Auth
+	issuer      *helper.Issuer
+	blobs       storage.BlobStore
+	costs       *costing.Engine
+	revocations revocation.Store
+	idempotency *mid.Idempotency
+	sms         sms.SMSVerifier
+	attempts    throttle.Store
 }
 
-func New(db *sql.DB, issuer *helper.Issuer) *API {
-	return &API{db: db, issuer: issuer}
+func New(db *sql.DB, issuer *helper.Issuer, blobs storage.BlobStore, costs *costing.Engine, revocations revocation.Store, verifier sms.SMSVerifier, attempts throttle.Store) *API {
+	return &API{db: db, issuer: issuer, blobs: blobs, costs: costs, revocations: revocations, sms: verifier, attempts: attempts}
 }
 
 func (api *API) Serve(ctx context.Context) error {
@@ -34,8 +51,8 @@ func (api *API) Serve(ctx context.Context) error {
 	router.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"https://fixchirp.com"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", mid.IdempotencyHeader, mid.StoreHeader},
+		ExposedHeaders:   []string{"Link", "Idempotent-Replayed"},
 		AllowCredentials: false,
 		MaxAge:           300,
 	}))
@@ -43,12 +60,13 @@ func (api *API) Serve(ctx context.Context) error {
 	router.Use(middleware.Logger)
 	router.Use(middleware.Heartbeat("/"))
 
-	auth, err := mid.NewAuth(os.Getenv("JWT_PUB_CERT_PATH"))
+	auth, err := mid.NewAuth(os.Getenv("JWT_PUB_CERT_PATH"), api.revocations, repository.New(api.db))
 	if err != nil {
 		return err
 	}
 
 	api.auth = auth
+	api.idempotency = mid.NewIdempotency(repository.New(api.db), mid.IdempotencyKeyTTL())
 
 	router.Mount("/admin", api.Routes())
 	router.Mount("/auth", api.AuthRoutes())
@@ -89,13 +107,42 @@ func (api *API) Serve(ctx context.Context) error {
 }
 
 func (s *API) ImagesRoutes(router chi.Router) {
-	router.Get("/*", func(w http.ResponseWriter, r *http.Request) {
-		http.StripPrefix("/images/", http.FileServer(http.Dir(os.Getenv("IMAGES_PATH")))).ServeHTTP(w, r)
-	})
+	router.Get("/*", s.serveBlob(helper.ImageKey))
 }
 
 func (s *API) ThumbnailRoutes(router chi.Router) {
-	router.Get("/*", func(w http.ResponseWriter, r *http.Request) {
-		http.StripPrefix("/thumbnails/", http.FileServer(http.Dir(os.Getenv("THUMBNAILS_PATH")))).ServeHTTP(w, r)
-	})
+	router.Get("/*", s.serveBlob(helper.ThumbnailKey))
+}
+
+// serveBlob streams the blob named by the wildcard part of the URL from the blob store
+func (s *API) serveBlob(key func(string) string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Names are kept to files below the prefix of the route, so the
+		// route cannot be used to read other blobs
+		name := chi.URLParam(r, "*")
+		if name == "" || strings.Contains(name, "..") || path.Clean("/"+name) != "/"+name {
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		}
+
+		blob, err := s.blobs.Get(r.Context(), key(name))
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				http.Error(w, "Not found", http.StatusNotFound)
+			} else {
+				fmt.Println(err)
+				http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			}
+			return
+		}
+		defer blob.Close()
+
+		if blob.ContentType != "" {
+			w.Header().Set("Content-Type", blob.ContentType)
+		}
+		w.Header().Set("Content-Length", strconv.FormatInt(blob.Size, 10))
+		w.Header().Set("Last-Modified", blob.ModTime.UTC().Format(http.TimeFormat))
+
+		io.Copy(w, blob)
+	}
 }
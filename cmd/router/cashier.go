@@ -11,6 +11,9 @@ func (a *API) CashierRoutes() *chi.Mux {
 	router := chi.NewRouter()
 
 	router.Route("/orders", a.CashierInStoreOrdersRoutes)
+	router.Route("/shifts", a.CashierShiftsRoutes)
+	router.Route("/sync", a.CashierSyncRoutes)
+	router.Route("/barcodes", a.CashierBarcodesRoutes)
 	router.Route("/profile", a.CashierProfileRoutes)
 
 	return router
@@ -19,26 +22,77 @@ func (a *API) CashierRoutes() *chi.Mux {
 func (a *API) CashierInStoreOrdersRoutes(router chi.Router) {
 
 	repo := repository.New(a.db)
-	handle := handler.NewOrderHandler(a.db, repo)
+	handle := handler.NewOrderHandler(a.db, repo, a.costs)
+	receipts := handler.NewReceiptHandler(repo)
 
 	router.Group(func(r chi.Router) {
 
 		r.Use(a.auth.AuthJWT)
 
-		r.Post("/", handle.CreateInStoreOrder)
-		r.Get("/", handle.CashierFindInStoreOrders)
-		r.Get("/{orderID}/store/{storeID}", handle.CashierFindInStoreOrder)
+		r.With(a.auth.RequirePermission("orders:create"), a.idempotency.Handle).Post("/", handle.CreateInStoreOrder)
+		r.With(a.auth.RequirePermission("orders:view-own")).Get("/", handle.CashierFindInStoreOrders)
+		r.With(a.auth.RequirePermission("orders:view-own")).Get("/{orderID}/store/{storeID}", handle.CashierFindInStoreOrder)
+		r.With(a.auth.RequirePermission("orders:view-own")).Get("/{orderID}/store/{storeID}/receipt", receipts.CashierReceipt)
+		r.With(a.auth.RequirePermission("receipts:send")).Post("/{orderID}/store/{storeID}/receipt/email", receipts.CashierEmailReceipt)
+	})
+}
+
+func (a *API) CashierShiftsRoutes(router chi.Router) {
+
+	repo := repository.New(a.db)
+	handle := handler.NewShiftHandler(a.db, repo)
+
+	router.Group(func(r chi.Router) {
+
+		r.Use(a.auth.AuthJWT)
+		r.Use(a.auth.RequirePermission("shifts:operate"))
+
+		r.Post("/", handle.Open)
+		r.Get("/current", handle.Current)
+		r.Post("/current/cash", handle.Cash)
+		r.Post("/current/close", handle.Close)
+		r.Get("/{id}", handle.CashierFindOne)
+	})
+}
+
+func (a *API) CashierSyncRoutes(router chi.Router) {
+
+	repo := repository.New(a.db)
+	handle := handler.NewSyncHandler(a.db, repo, a.costs)
+
+	router.Group(func(r chi.Router) {
+
+		r.Use(a.auth.AuthJWT)
+		r.Use(a.auth.RequirePermission("pos:sync"))
+
+		r.Post("/orders", handle.Orders)
+		r.Get("/catalogue", handle.Catalogue)
+	})
+}
+
+func (a *API) CashierBarcodesRoutes(router chi.Router) {
+
+	repo := repository.New(a.db)
+	handle := handler.NewBarcodeHandler(a.db, repo)
+
+	router.Group(func(r chi.Router) {
+
+		r.Use(a.auth.AuthJWT)
+		r.Use(a.auth.RequirePermission("barcodes:view"))
+
+		r.Get("/{code}", handle.CashierLookup)
 	})
 }
 
 func (a *API) CashierProfileRoutes(router chi.Router) {
 
 	repo := repository.New(a.db)
-	handle := handler.NewProfileHandler(repo)
+	handle := handler.NewProfileHandler(a.db, repo, a.revocations, a.attempts, a.sms)
 
 	router.Group(func(r chi.Router) {
 
 		r.Use(a.auth.AuthJWT)
+		r.Use(a.auth.RequirePermission("profile:view"))
 
 		r.Get("/", handle.CashierProfile)
 	})
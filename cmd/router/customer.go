@@ -1,6 +1,9 @@
 package router
 
 import (
+	"net/http"
+
+	"api/cmd/helper"
 	"api/handler"
 	"api/repository"
 
@@ -12,6 +15,8 @@ func (a *API) CustomerRoutes() *chi.Mux {
 
 	router.Route("/orders", a.CustomerOnlineOrdersRoutes)
 	router.Route("/products", a.CustomerProductsRoutes)
+	router.Route("/profile", a.CustomerProfileRoutes)
+	router.Route("/addresses", a.CustomerAddressesRoutes)
 
 	return router
 }
@@ -19,30 +24,74 @@ func (a *API) CustomerRoutes() *chi.Mux {
 func (a *API) CustomerOnlineOrdersRoutes(router chi.Router) {
 
 	repo := repository.New(a.db)
-	handle := handler.NewOrderHandler(a.db, repo)
+	handle := handler.NewOrderHandler(a.db, repo, a.costs)
 
 	router.Get("/{sku}/item", handle.CustomerFindOnlineOrder)
 	router.Put("/{orderID}", handle.CustomerOrderPaid)
 
+	// Checking out can be kept for customers who verified their email or phone
+	checkout := []func(http.Handler) http.Handler{a.auth.RequirePermission("online-orders:create")}
+	if helper.RequireVerifiedEmail() {
+		checkout = append(checkout, a.auth.RequireVerified)
+	}
+	checkout = append(checkout, a.idempotency.Handle)
+
 	router.Group(func(r chi.Router) {
 
 		r.Use(a.auth.AuthJWT)
 
-		r.Post("/", handle.CreateOnlineOrder)
-		r.Get("/", handle.CustomerFindOnlineOrders)
-		r.Get("/{id}", handle.CustomerGetOnlineOrder)
+		r.With(checkout...).Post("/", handle.CreateOnlineOrder)
+		r.With(a.auth.RequirePermission("online-orders:view-own")).Get("/", handle.CustomerFindOnlineOrders)
+		r.With(a.auth.RequirePermission("online-orders:view-own")).Get("/{id}", handle.CustomerGetOnlineOrder)
 	})
 }
 
 func (a *API) CustomerProductsRoutes(router chi.Router) {
 
 	repo := repository.New(a.db)
-	handle := handler.NewProductHandler(repo)
+	handle := handler.NewProductHandler(repo, a.blobs)
 
 	router.Group(func(r chi.Router) {
 
 		r.Use(a.auth.AuthJWT)
+		r.Use(a.auth.RequirePermission("catalogue:view"))
 
 		r.Get("/{sku}", handle.CustomerFindOne)
 	})
 }
+
+func (a *API) CustomerProfileRoutes(router chi.Router) {
+
+	repo := repository.New(a.db)
+	handle := handler.NewProfileHandler(a.db, repo, a.revocations, a.attempts, a.sms)
+
+	router.Group(func(r chi.Router) {
+
+		r.Use(a.auth.AuthJWT)
+		r.Use(a.auth.RequirePermission("account:manage"))
+
+		r.Get("/", handle.CustomerProfile)
+		r.Put("/", handle.CustomerUpdateProfile)
+		r.Put("/password", handle.CustomerChangePassword)
+		r.Delete("/", handle.CustomerDelete)
+	})
+}
+
+func (a *API) CustomerAddressesRoutes(router chi.Router) {
+
+	repo := repository.New(a.db)
+	handle := handler.NewAddressHandler(a.db, repo)
+
+	router.Group(func(r chi.Router) {
+
+		r.Use(a.auth.AuthJWT)
+		r.Use(a.auth.RequirePermission("account:manage"))
+
+		r.Get("/", handle.CustomerFindAll)
+		r.Post("/", handle.Create)
+		r.Get("/{id}", handle.CustomerFindOne)
+		r.Put("/{id}", handle.CustomerUpdate)
+		r.Post("/{id}/default", handle.CustomerSetDefault)
+		r.Delete("/{id}", handle.CustomerDelete)
+	})
+}
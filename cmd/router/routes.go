@@ -1,6 +1,8 @@
 package router
 
 import (
+	"api/cmd/helper"
+	"api/cmd/jobs"
 	"api/handler"
 	"api/repository"
 
@@ -10,13 +12,30 @@ import (
 func (a *API) Routes() *chi.Mux {
 	router := chi.NewRouter()
 
+	// Admin routes can be kept for sessions signed in with a second factor
+	if helper.RequireAdminMFA() {
+		router.Use(a.auth.RequireMFA)
+	}
+
 	router.Route("/categories", a.CategoriesRoutes)
 	router.Route("/products", a.ProductsRoutes)
+	router.Route("/barcodes", a.BarcodesRoutes)
+	router.Route("/labels", a.LabelsRoutes)
 	router.Route("/stores", a.StoresRoutes)
 	router.Route("/purchases", a.PurchasesRoutes)
+	router.Route("/goods-received", a.GoodsReceivedRoutes)
+	router.Route("/suppliers", a.SuppliersRoutes)
+	router.Route("/purchase-orders", a.PurchaseOrdersRoutes)
+	router.Route("/reorder-points", a.ReorderPointsRoutes)
 	router.Route("/orders", a.InStoreOrdersRoutes)
 	router.Route("/cashiers", a.StoreUsersRoutes)
+	router.Route("/users", a.UsersRoutes)
+	router.Route("/roles", a.RolesRoutes)
+	router.Route("/permissions", a.PermissionsRoutes)
+	router.Route("/shifts", a.ShiftsRoutes)
+	router.Route("/costing", a.CostingRoutes)
 	router.Route("/reports", a.ReportsRoutes)
+	router.Route("/images", a.AdminImagesRoutes)
 
 	return router
 }
@@ -26,10 +45,37 @@ func (a *API) AuthRoutes() *chi.Mux {
 
 	repo := repository.New(a.db)
 
-	handle := handler.NewAuthHandler(repo, a.issuer)
+	handle := handler.NewAuthHandler(a.db, repo, a.issuer, &a.auth.Validator, a.revocations, a.sms, a.attempts)
 
 	router.Post("/register", handle.Register)
 	router.Post("/login", handle.Login)
+	router.Post("/refresh", handle.Refresh)
+	router.Post("/logout", handle.Logout)
+	router.Post("/forgot", handle.Forgot)
+	router.Post("/reset", handle.Reset)
+	router.Get("/verify", handle.Verify)
+	router.Post("/verify/resend", handle.ResendVerification)
+
+	router.Route("/phone", func(r chi.Router) {
+		r.Post("/start", handle.PhoneStart)
+		r.Post("/login", handle.PhoneLogin)
+		r.Post("/register", handle.PhoneRegister)
+		r.With(a.auth.AuthJWT).Post("/verify", handle.PhoneVerify)
+	})
+
+	router.Route("/mfa", func(r chi.Router) {
+		r.Post("/verify", handle.MFAVerify)
+
+		r.Group(func(r chi.Router) {
+			r.Use(a.auth.AuthJWT)
+
+			r.Get("/", handle.MFAStatus)
+			r.Post("/enroll", handle.MFAEnroll)
+			r.Post("/confirm", handle.MFAConfirm)
+			r.Post("/recovery-codes", handle.MFARecoveryCodes)
+			r.Delete("/", handle.MFADisable)
+		})
+	})
 
 	return router
 }
@@ -37,34 +83,68 @@ func (a *API) AuthRoutes() *chi.Mux {
 func (a *API) CategoriesRoutes(router chi.Router) {
 
 	repo := repository.New(a.db)
-	handle := handler.NewCategoryHandler(repo)
+	handle := handler.NewCategoryHandler(repo, a.blobs)
 
 	router.Group(func(r chi.Router) {
 
 		r.Use(a.auth.AuthJWT)
 
-		r.Post("/", handle.Create)
-		r.Get("/", handle.AdminFindAll)
-		r.Get("/{id}", handle.AdminFindOne)
-		r.Put("/{id}", handle.AdminUpdate)
-		r.Delete("/{id}", handle.AdminDelete)
+		r.With(a.auth.RequirePermission("categories:manage")).Post("/", handle.Create)
+		r.With(a.auth.RequirePermission("categories:view")).Get("/", handle.AdminFindAll)
+		r.With(a.auth.RequirePermission("categories:view")).Get("/{id}", handle.AdminFindOne)
+		r.With(a.auth.RequirePermission("categories:manage")).Put("/{id}", handle.AdminUpdate)
+		r.With(a.auth.RequirePermission("categories:manage")).Delete("/{id}", handle.AdminDelete)
 	})
 }
 
 func (a *API) ProductsRoutes(router chi.Router) {
 
 	repo := repository.New(a.db)
-	handle := handler.NewProductHandler(repo)
+	handle := handler.NewProductHandler(repo, a.blobs)
+	barcodes := handler.NewBarcodeHandler(a.db, repo)
 
 	router.Group(func(r chi.Router) {
 
 		r.Use(a.auth.AuthJWT)
 
-		r.Post("/", handle.Create)
-		r.Get("/", handle.AdminFindAll)
-		r.Get("/{id}", handle.AdminFindOne)
-		r.Put("/{id}", handle.AdminUpdate)
-		r.Delete("/{id}", handle.AdminDelete)
+		r.With(a.auth.RequirePermission("products:manage")).Post("/", handle.Create)
+		r.With(a.auth.RequirePermission("products:view")).Get("/", handle.AdminFindAll)
+		r.With(a.auth.RequirePermission("products:import")).Post("/import", handle.AdminImport)
+		r.With(a.auth.RequirePermission("products:import")).Get("/import/{id}", handle.AdminFindImport)
+		r.With(a.auth.RequirePermission("products:export")).Get("/export", handle.AdminExport)
+		r.With(a.auth.RequirePermission("products:view")).Get("/{id}", handle.AdminFindOne)
+		r.With(a.auth.RequirePermission("products:manage")).Put("/{id}", handle.AdminUpdate)
+		r.With(a.auth.RequirePermission("products:manage")).Delete("/{id}", handle.AdminDelete)
+		r.With(a.auth.RequirePermission("barcodes:view")).Get("/{id}/barcodes", barcodes.AdminFindAll)
+		r.With(a.auth.RequirePermission("barcodes:manage")).Post("/{id}/barcodes", barcodes.Create)
+		r.With(a.auth.RequirePermission("barcodes:manage")).Post("/{id}/barcodes/generate", barcodes.Generate)
+		r.With(a.auth.RequirePermission("barcodes:manage")).Delete("/{id}/barcodes/{barcodeID}", barcodes.AdminDelete)
+	})
+}
+
+func (a *API) BarcodesRoutes(router chi.Router) {
+
+	repo := repository.New(a.db)
+	handle := handler.NewBarcodeHandler(a.db, repo)
+
+	router.Group(func(r chi.Router) {
+
+		r.Use(a.auth.AuthJWT)
+
+		r.With(a.auth.RequirePermission("barcodes:view")).Get("/{code}", handle.AdminLookup)
+	})
+}
+
+func (a *API) LabelsRoutes(router chi.Router) {
+
+	repo := repository.New(a.db)
+	handle := handler.NewBarcodeHandler(a.db, repo)
+
+	router.Group(func(r chi.Router) {
+
+		r.Use(a.auth.AuthJWT)
+
+		r.With(a.auth.RequirePermission("labels:print")).Post("/", handle.AdminLabels)
 	})
 }
 
@@ -72,71 +152,268 @@ func (a *API) StoresRoutes(router chi.Router) {
 
 	repo := repository.New(a.db)
 	handle := handler.NewStoreHandler(repo)
+	receipts := handler.NewReceiptHandler(repo)
 
 	router.Group(func(r chi.Router) {
 
 		r.Use(a.auth.AuthJWT)
 
-		r.Post("/", handle.Create)
-		r.Get("/", handle.AdminFindAll)
-		r.Get("/{id}", handle.AdminFindOne)
-		r.Put("/{id}", handle.AdminUpdate)
-		r.Delete("/{id}", handle.AdminDelete)
+		r.With(a.auth.RequirePermission("stores:manage")).Post("/", handle.Create)
+		r.With(a.auth.RequirePermission("stores:view")).Get("/", handle.AdminFindAll)
+		r.With(a.auth.RequirePermission("stores:view")).Get("/{id}", handle.AdminFindOne)
+		r.With(a.auth.RequirePermission("stores:manage")).Put("/{id}", handle.AdminUpdate)
+		r.With(a.auth.RequirePermission("stores:manage")).Delete("/{id}", handle.AdminDelete)
+		r.With(a.auth.RequirePermission("stores:view")).Get("/{id}/receipt-template", receipts.AdminFindTemplate)
+		r.With(a.auth.RequirePermission("stores:manage")).Put("/{id}/receipt-template", receipts.AdminUpdateTemplate)
 	})
 }
 
 func (a *API) PurchasesRoutes(router chi.Router) {
 
 	repo := repository.New(a.db)
-	handle := handler.NewPurchaseHandler(repo)
+	handle := handler.NewPurchaseHandler(a.db, repo, a.costs)
+	barcodes := handler.NewBarcodeHandler(a.db, repo)
+
+	router.Group(func(r chi.Router) {
+
+		r.Use(a.auth.AuthJWT)
+
+		r.With(a.auth.RequireStorePermission("purchases:create"), a.idempotency.Handle).Post("/", handle.Create)
+		r.With(a.auth.RequireStorePermission("purchases:view")).Get("/", handle.AdminFindAll)
+		r.With(a.auth.RequireStorePermission("purchases:delete")).Delete("/{id}", handle.AdminDelete)
+		r.With(a.auth.RequirePermission("labels:print")).Get("/{id}/labels", barcodes.AdminPurchaseLabels)
+	})
+}
+
+func (a *API) GoodsReceivedRoutes(router chi.Router) {
+
+	repo := repository.New(a.db)
+	handle := handler.NewGoodsReceivedHandler(a.db, repo, a.costs)
+
+	router.Group(func(r chi.Router) {
+
+		r.Use(a.auth.AuthJWT)
+
+		r.With(a.auth.RequireStorePermission("goods-received:create")).Post("/", handle.Create)
+		r.With(a.auth.RequireStorePermission("goods-received:view")).Get("/", handle.AdminFindAll)
+		r.With(a.auth.RequireStorePermission("goods-received:view")).Get("/{id}", handle.AdminFindOne)
+	})
+}
+
+func (a *API) SuppliersRoutes(router chi.Router) {
+
+	repo := repository.New(a.db)
+	handle := handler.NewSupplierHandler(repo)
+
+	router.Group(func(r chi.Router) {
+
+		r.Use(a.auth.AuthJWT)
+
+		r.With(a.auth.RequirePermission("suppliers:manage")).Post("/", handle.Create)
+		r.With(a.auth.RequirePermission("suppliers:view")).Get("/", handle.AdminFindAll)
+		r.With(a.auth.RequirePermission("suppliers:view")).Get("/{id}", handle.AdminFindOne)
+		r.With(a.auth.RequirePermission("suppliers:manage")).Put("/{id}", handle.AdminUpdate)
+		r.With(a.auth.RequirePermission("suppliers:manage")).Delete("/{id}", handle.AdminDelete)
+	})
+}
+
+func (a *API) PurchaseOrdersRoutes(router chi.Router) {
+
+	repo := repository.New(a.db)
+	handle := handler.NewPurchaseOrderHandler(a.db, repo, a.costs)
 
 	router.Group(func(r chi.Router) {
 
 		r.Use(a.auth.AuthJWT)
 
+		r.With(a.auth.RequirePermission("purchase-orders:manage")).Post("/", handle.Create)
+		r.With(a.auth.RequirePermission("purchase-orders:view")).Get("/", handle.AdminFindAll)
+		r.With(a.auth.RequirePermission("purchase-orders:view")).Get("/{id}", handle.AdminFindOne)
+		r.With(a.auth.RequirePermission("purchase-orders:manage")).Put("/{id}", handle.AdminUpdate)
+		r.With(a.auth.RequirePermission("purchase-orders:manage")).Delete("/{id}", handle.AdminDelete)
+		r.With(a.auth.RequirePermission("purchase-orders:manage")).Post("/{id}/send", handle.Send)
+		r.With(a.auth.RequirePermission("purchase-orders:receive")).Post("/{id}/receive", handle.Receive)
+		r.With(a.auth.RequirePermission("purchase-orders:manage")).Post("/{id}/close", handle.Close)
+	})
+}
+
+func (a *API) ReorderPointsRoutes(router chi.Router) {
+
+	repo := repository.New(a.db)
+	handle := handler.NewReorderPointHandler(repo)
+
+	router.Group(func(r chi.Router) {
+
+		r.Use(a.auth.AuthJWT)
+
+		r.With(a.auth.RequirePermission("reorder-points:manage")).Post("/", handle.Create)
+		r.With(a.auth.RequirePermission("reorder-points:view")).Get("/", handle.AdminFindAll)
+		r.With(a.auth.RequirePermission("reorder-points:manage")).Delete("/{id}", handle.AdminDelete)
+	})
+}
+
+func (a *API) InStoreOrdersRoutes(router chi.Router) {
+
+	repo := repository.New(a.db)
+	handle := handler.NewOrderHandler(a.db, repo, a.costs)
+	receipts := handler.NewReceiptHandler(repo)
+
+	router.Group(func(r chi.Router) {
+
+		r.Use(a.auth.AuthJWT)
+
+		r.With(a.auth.RequirePermission("orders:create")).Post("/", handle.CreateInStoreOrder)
+		r.With(a.auth.RequireStorePermission("orders:view")).Get("/", handle.AdminFindInStoreOrders)
+		r.With(a.auth.RequireStorePermission("orders:view")).Get("/{id}", handle.AdminFindInStoreOrder)
+		r.With(a.auth.RequireStorePermission("orders:view")).Get("/{id}/receipt", receipts.AdminReceipt)
+	})
+}
+
+func (a *API) StoreUsersRoutes(router chi.Router) {
+	repo := repository.New(a.db)
+	handle := handler.NewUserHandler(a.db, repo, a.revocations)
+
+	router.Group(func(r chi.Router) {
+
+		r.Use(a.auth.AuthJWT)
+
+		r.With(a.auth.RequireStorePermission("cashiers:manage")).Post("/", handle.AdminAssignStoreUser)
+		r.With(a.auth.RequireStorePermission("cashiers:view")).Get("/stores/{id}", handle.AdminFindStoreUsers)
+		r.With(a.auth.RequireStorePermission("cashiers:manage")).Delete("/{userID}/stores/{storeID}", handle.AdminDeleteStoreUser)
+	})
+}
+
+func (a *API) UsersRoutes(router chi.Router) {
+	repo := repository.New(a.db)
+	users := handler.NewUserHandler(a.db, repo, a.revocations)
+	sessions := handler.NewSessionHandler(repo, a.revocations)
+	roles := handler.NewRoleHandler(a.db, repo)
+	lockouts := handler.NewLockoutHandler(repo, a.attempts)
+
+	router.Group(func(r chi.Router) {
+
+		r.Use(a.auth.AuthJWT)
+
+		r.With(a.auth.RequirePermission("users:view")).Get("/", users.AdminFindAll)
+		r.With(a.auth.RequirePermission("users:manage")).Post("/", users.Create)
+		r.With(a.auth.RequirePermission("users:view")).Get("/{id}", users.AdminFindOne)
+		r.With(a.auth.RequirePermission("users:manage")).Put("/{id}", users.AdminUpdate)
+		r.With(a.auth.RequirePermission("users:manage")).Post("/{id}/deactivate", users.AdminDeactivate)
+		r.With(a.auth.RequirePermission("users:manage")).Post("/{id}/reactivate", users.AdminReactivate)
+		r.With(a.auth.RequirePermission("sessions:view")).Get("/{id}/sessions", sessions.AdminFindAll)
+		r.With(a.auth.RequirePermission("sessions:revoke")).Delete("/{id}/sessions", sessions.AdminRevokeAll)
+		r.With(a.auth.RequirePermission("sessions:revoke")).Delete("/{id}/sessions/{sessionID}", sessions.AdminRevoke)
+		r.With(a.auth.RequirePermission("roles:manage")).Get("/{id}/roles", roles.AdminFindUserRoles)
+		r.With(a.auth.RequirePermission("roles:manage")).Post("/{id}/roles", roles.AdminAssignUserRole)
+		r.With(a.auth.RequirePermission("roles:manage")).Delete("/{id}/roles/{roleID}", roles.AdminRemoveUserRole)
+		r.With(a.auth.RequirePermission("sessions:view")).Get("/{id}/login-audits", lockouts.AdminFindAudits)
+		r.With(a.auth.RequirePermission("users:unlock")).Get("/{id}/lockout", lockouts.AdminFindOne)
+		r.With(a.auth.RequirePermission("users:unlock")).Delete("/{id}/lockout", lockouts.AdminUnlock)
+	})
+}
+
+func (a *API) RolesRoutes(router chi.Router) {
+	repo := repository.New(a.db)
+	handle := handler.NewRoleHandler(a.db, repo)
+
+	router.Group(func(r chi.Router) {
+
+		r.Use(a.auth.AuthJWT)
+		r.Use(a.auth.RequirePermission("roles:manage"))
+
 		r.Post("/", handle.Create)
 		r.Get("/", handle.AdminFindAll)
+		r.Get("/{id}", handle.AdminFindOne)
+		r.Put("/{id}", handle.AdminUpdate)
 		r.Delete("/{id}", handle.AdminDelete)
 	})
 }
 
-func (a *API) InStoreOrdersRoutes(router chi.Router) {
+func (a *API) PermissionsRoutes(router chi.Router) {
+	repo := repository.New(a.db)
+	handle := handler.NewRoleHandler(a.db, repo)
+
+	router.Group(func(r chi.Router) {
 
+		r.Use(a.auth.AuthJWT)
+		r.Use(a.auth.RequirePermission("roles:manage"))
+
+		r.Get("/", handle.AdminFindPermissions)
+	})
+}
+
+func (a *API) ShiftsRoutes(router chi.Router) {
 	repo := repository.New(a.db)
-	handle := handler.NewOrderHandler(a.db, repo)
+	handle := handler.NewShiftHandler(a.db, repo)
 
 	router.Group(func(r chi.Router) {
 
 		r.Use(a.auth.AuthJWT)
+		r.Use(a.auth.RequireStorePermission("shifts:view"))
 
-		r.Post("/", handle.CreateInStoreOrder)
-		r.Get("/", handle.AdminFindInStoreOrders)
-		r.Get("/{id}", handle.AdminFindInStoreOrder)
+		r.Get("/", handle.AdminFindAll)
+		r.Get("/{id}", handle.AdminFindOne)
 	})
 }
 
-func (a *API) StoreUsersRoutes(router chi.Router) {
+func (a *API) CostingRoutes(router chi.Router) {
 	repo := repository.New(a.db)
-	handle := handler.NewUserHandler(repo)
+	handle := handler.NewCostingHandler(a.db, repo, a.costs)
 
 	router.Group(func(r chi.Router) {
 
 		r.Use(a.auth.AuthJWT)
+		r.Use(a.auth.RequirePermission("costing:rebuild"))
 
-		r.Post("/", handle.AdminAssignStoreUser)
-		r.Get("/stores/{id}", handle.AdminFindStoreUsers)
-		r.Delete("/{userID}/stores/{storeID}", handle.AdminDeleteStoreUser)
+		r.Post("/rebuild", handle.AdminRebuild)
 	})
 }
 
 func (a *API) ReportsRoutes(router chi.Router) {
 	repo := repository.New(a.db)
-	o := handler.NewOrderHandler(a.db, repo)
+	o := handler.NewOrderHandler(a.db, repo, a.costs)
+	s := handler.NewSupplierHandler(repo)
+	po := handler.NewPurchaseOrderHandler(a.db, repo, a.costs)
+	c := handler.NewCostingHandler(a.db, repo, a.costs)
+	rp := handler.NewReorderPointHandler(repo)
+	sales := handler.NewSalesReportHandler(repo)
+
+	router.Group(func(r chi.Router) {
+
+		r.Use(a.auth.AuthJWT)
+
+		// Sales reports are filtered down to the stores the caller manages
+		r.Group(func(r chi.Router) {
+			r.Use(a.auth.RequireStorePermission("reports:view"))
+
+			r.Get("/sales", sales.AdminSales)
+			r.Get("/sales/summary", sales.AdminSummary)
+			r.Get("/sales/top-sellers", sales.AdminTopSellers)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(a.auth.RequirePermission("reports:view"))
+
+			r.Get("/summary", o.AdminReport)
+			r.Get("/suppliers/spend", s.AdminSpendReport)
+			r.Get("/purchase-orders/outstanding", po.AdminOutstandingReport)
+			r.Get("/inventory/valuation", c.AdminValuation)
+			r.Get("/margin", c.AdminMargin)
+			r.Get("/stock/low", rp.AdminLowStock)
+		})
+	})
+}
+
+func (a *API) AdminImagesRoutes(router chi.Router) {
+	repo := repository.New(a.db)
+	handle := handler.NewImageHandler(repo, jobs.NewImageCollector(repo, a.blobs, jobs.ImageGCGrace()))
 
 	router.Group(func(r chi.Router) {
 
 		r.Use(a.auth.AuthJWT)
+		r.Use(a.auth.RequirePermission("images:manage"))
 
-		r.Get("/summary", o.AdminReport)
+		r.Get("/orphans", handle.AdminFindOrphans)
+		r.Delete("/orphans", handle.AdminDeleteOrphans)
 	})
 }
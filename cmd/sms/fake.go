@@ -0,0 +1,57 @@
+package sms
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"log"
+	"math/big"
+	"sync"
+)
+
+// Fake keeps codes in memory and logs them instead of sending them. Tests can
+// read the code sent to a phone with Code.
+type Fake struct {
+	mu    sync.Mutex
+	codes map[string]string
+}
+
+func NewFake() *Fake {
+	return &Fake{codes: map[string]string{}}
+}
+
+func (f *Fake) Send(ctx context.Context, phone string) error {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return err
+	}
+
+	code := fmt.Sprintf("%06d", n.Int64())
+
+	f.mu.Lock()
+	f.codes[phone] = code
+	f.mu.Unlock()
+
+	log.Printf("sms: code for %s is %s", phone, code)
+	return nil
+}
+
+func (f *Fake) Check(ctx context.Context, phone, code string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if sent, ok := f.codes[phone]; !ok || sent != code {
+		return false, nil
+	}
+
+	delete(f.codes, phone)
+	return true, nil
+}
+
+// Code is the last code sent to a phone that has not been used yet
+func (f *Fake) Code(phone string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.codes[phone]
+}
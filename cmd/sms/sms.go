@@ -0,0 +1,40 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+)
+
+// SMSVerifier sends one-time codes to phones and checks the codes users type
+// in. Codes are kept by the verifier, so callers only see whether a code was
+// right.
+type SMSVerifier interface {
+	Send(ctx context.Context, phone string) error
+	Check(ctx context.Context, phone, code string) (bool, error)
+}
+
+// New creates the verifier selected by the SMS_DRIVER env variable. It
+// defaults to Twilio Verify, which needs TWILIO_SID, TWILIO_TOKEN and
+// TWILIO_SERVICE_SID. The fake driver logs codes instead of sending them and
+// is only fit for development.
+func New() (SMSVerifier, error) {
+	switch os.Getenv("SMS_DRIVER") {
+	case "", "twilio":
+		sid := os.Getenv("TWILIO_SID")
+		token := os.Getenv("TWILIO_TOKEN")
+		service := os.Getenv("TWILIO_SERVICE_SID")
+
+		if sid == "" || token == "" || service == "" {
+			return nil, fmt.Errorf("TWILIO_SID, TWILIO_TOKEN and TWILIO_SERVICE_SID must be set, or SMS_DRIVER=fake for development")
+		}
+
+		return NewTwilio(sid, token, service), nil
+	case "fake":
+		log.Println("sms: using the fake driver, codes are logged instead of sent")
+		return NewFake(), nil
+	default:
+		return nil, fmt.Errorf("unsupported sms driver: %s", os.Getenv("SMS_DRIVER"))
+	}
+}
@@ -0,0 +1,95 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const twilioVerifyURL = "https://verify.twilio.com/v2/Services/"
+
+// Twilio sends codes by SMS through a Twilio Verify service, which also
+// expires codes and limits how often one can be checked
+type Twilio struct {
+	sid     string
+	token   string
+	service string
+	client  *http.Client
+}
+
+func NewTwilio(sid, token, service string) *Twilio {
+	return &Twilio{
+		sid:     sid,
+		token:   token,
+		service: service,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (t *Twilio) Send(ctx context.Context, phone string) error {
+	_, err := t.post(ctx, "Verifications", url.Values{
+		"To":      {phone},
+		"Channel": {"sms"},
+	})
+
+	return err
+}
+
+func (t *Twilio) Check(ctx context.Context, phone, code string) (bool, error) {
+	status, err := t.post(ctx, "VerificationCheck", url.Values{
+		"To":   {phone},
+		"Code": {code},
+	})
+	if err == errNoVerification {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return status == "approved", nil
+}
+
+// errNoVerification is returned by Twilio when no code is waiting for the
+// phone, because it expired, was approved or was checked too often
+var errNoVerification = errors.New("twilio: no pending verification")
+
+// post calls the Verify API and returns the status of the verification
+func (t *Twilio) post(ctx context.Context, path string, form url.Values) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, twilioVerifyURL+t.service+"/"+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(t.sid, t.token)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := t.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("twilio: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return "", errNoVerification
+	}
+
+	if res.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return "", fmt.Errorf("twilio: %s responded %d: %s", path, res.StatusCode, body)
+	}
+
+	var verification struct {
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&verification); err != nil {
+		return "", fmt.Errorf("twilio: unable to decode response: %w", err)
+	}
+
+	return verification.Status, nil
+}
@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore keeps uploaded files outside of the API process so that several
+// instances can share them.
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (*Blob, error)
+	Delete(ctx context.Context, key string) error
+	SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+	List(ctx context.Context, prefix string) ([]BlobInfo, error)
+}
+
+// Blob is a stored file. The caller must close it.
+type Blob struct {
+	io.ReadCloser
+	ContentType string
+	Size        int64
+	ModTime     time.Time
+}
+
+// BlobInfo describes a stored file without opening it
+type BlobInfo struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+// New creates the blob store selected by the STORAGE_DRIVER env variable.
+// It defaults to the local filesystem under UPLOADS_PATH.
+func New() (BlobStore, error) {
+	switch os.Getenv("STORAGE_DRIVER") {
+	case "", "local":
+		root := os.Getenv("UPLOADS_PATH")
+		if root == "" {
+			return nil, fmt.Errorf("UPLOADS_PATH is not set")
+		}
+
+		return NewLocal(root, os.Getenv("STORAGE_BASE_URL"))
+	case "s3":
+		useSSL, _ := strconv.ParseBool(os.Getenv("S3_USE_SSL"))
+
+		return NewS3(S3Options{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			UseSSL:    useSSL,
+		})
+	default:
+		return nil, fmt.Errorf("unsupported storage driver: %s", os.Getenv("STORAGE_DRIVER"))
+	}
+}
@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// testBlobStore runs the same checks against any backend
+func testBlobStore(t *testing.T, store BlobStore) {
+	ctx := context.Background()
+	content := "not really a png"
+
+	if err := store.Put(ctx, "images/a.png", strings.NewReader(content), int64(len(content)), "image/png"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	blob, err := store.Get(ctx, "images/a.png")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	body, err := io.ReadAll(blob)
+	blob.Close()
+	if err != nil {
+		t.Fatalf("reading blob: %v", err)
+	}
+
+	if string(body) != content || blob.Size != int64(len(content)) {
+		t.Errorf("Get() = %q of size %d, want %q", body, blob.Size, content)
+	}
+
+	if blob.ContentType != "image/png" {
+		t.Errorf("Get() content type = %q, want image/png", blob.ContentType)
+	}
+
+	if _, err := store.Get(ctx, "images/missing.png"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() of a missing blob error = %v, want ErrNotFound", err)
+	}
+
+	blobs, err := store.List(ctx, "images")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	if len(blobs) != 1 || blobs[0].Key != "images/a.png" {
+		t.Errorf("List() = %+v, want images/a.png only", blobs)
+	}
+
+	if err := store.Delete(ctx, "images/a.png"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	if _, err := store.Get(ctx, "images/a.png"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() after Delete() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestLocal(t *testing.T) {
+	store, err := NewLocal(t.TempDir(), "http://localhost/uploads")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testBlobStore(t, store)
+}
+
+func TestLocalKeysStayBelowRoot(t *testing.T) {
+	root := t.TempDir()
+
+	store, err := NewLocal(root+"/blobs", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(root+"/secret", []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.Get(context.Background(), "images/../../secret"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() outside the root error = %v, want ErrNotFound", err)
+	}
+}
+
+// TestS3 runs against an S3-compatible server such as MinIO, set with
+// S3_TEST_ENDPOINT, S3_TEST_ACCESS_KEY and S3_TEST_SECRET_KEY. A bucket is
+// made for the test and removed after it.
+func TestS3(t *testing.T) {
+	endpoint := os.Getenv("S3_TEST_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("S3_TEST_ENDPOINT is not set")
+	}
+
+	ctx := context.Background()
+	bucket := "blob-store-test"
+
+	store, err := NewS3(S3Options{
+		Endpoint:  endpoint,
+		Bucket:    bucket,
+		AccessKey: os.Getenv("S3_TEST_ACCESS_KEY"),
+		SecretKey: os.Getenv("S3_TEST_SECRET_KEY"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.client.MakeBucket(ctx, bucket, minio.MakeBucketOptions{}); err != nil {
+		t.Fatalf("MakeBucket() error = %v", err)
+	}
+	t.Cleanup(func() {
+		if err := store.client.RemoveBucketWithOptions(ctx, bucket, minio.RemoveBucketOptions{ForceDelete: true}); err != nil {
+			t.Log(err)
+		}
+	})
+
+	testBlobStore(t, store)
+}
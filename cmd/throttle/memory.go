@@ -0,0 +1,96 @@
+package throttle
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Memory keeps counts in the memory of one instance, so each instance
+// counts on its own and counts are lost on a restart
+type Memory struct {
+	mu      sync.Mutex
+	entries map[string]entry
+	swept   time.Time
+}
+
+type entry struct {
+	failures Failures
+	expires  time.Time
+}
+
+func NewMemory() *Memory {
+	return &Memory{entries: map[string]entry{}}
+}
+
+func (m *Memory) Fail(ctx context.Context, key string, window time.Duration) (Failures, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+
+	// Expired counts are cleared out now and then, as keys come from the
+	// outside and could otherwise grow without end
+	if now.Sub(m.swept) > time.Minute {
+		for k, e := range m.entries {
+			if now.After(e.expires) {
+				delete(m.entries, k)
+			}
+		}
+		m.swept = now
+	}
+
+	e, ok := m.entries[key]
+	if !ok || now.After(e.expires) {
+		e = entry{}
+	}
+
+	before := e.failures
+
+	e.failures.Count++
+	e.failures.Last = now
+	e.expires = now.Add(window)
+	m.entries[key] = e
+
+	return before, nil
+}
+
+func (m *Memory) Undo(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[key]
+	if !ok {
+		return nil
+	}
+
+	if e.failures.Count <= 1 {
+		delete(m.entries, key)
+		return nil
+	}
+
+	e.failures.Count--
+	m.entries[key] = e
+
+	return nil
+}
+
+func (m *Memory) Get(ctx context.Context, key string) (Failures, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[key]
+	if !ok || time.Now().After(e.expires) {
+		return Failures{}, nil
+	}
+
+	return e.failures, nil
+}
+
+func (m *Memory) Reset(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, key)
+	return nil
+}
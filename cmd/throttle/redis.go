@@ -0,0 +1,97 @@
+package throttle
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis keeps counts in Redis, shared by every instance. A count is a hash of
+// the number of failures and the time of the last one.
+type Redis struct {
+	client *redis.Client
+}
+
+func NewRedis(addr, password string) (*Redis, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("unable to connect to redis: %w", err)
+	}
+
+	return &Redis{client: client}, nil
+}
+
+func (r *Redis) Fail(ctx context.Context, key string, window time.Duration) (Failures, error) {
+	now := time.Now()
+
+	var before *redis.SliceCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		before = pipe.HMGet(ctx, key, "count", "last")
+		pipe.HIncrBy(ctx, key, "count", 1)
+		pipe.HSet(ctx, key, "last", now.UnixNano())
+		pipe.Expire(ctx, key, window)
+		return nil
+	})
+	if err != nil {
+		return Failures{}, err
+	}
+
+	return parseFailures(key, before.Val())
+}
+
+// undoScript takes one failure back, and the whole count with the last one.
+// A count that expired meanwhile is not brought back.
+var undoScript = redis.NewScript(`
+local count = redis.call("HINCRBY", KEYS[1], "count", -1)
+if count <= 0 then
+	redis.call("DEL", KEYS[1])
+end
+return count
+`)
+
+func (r *Redis) Undo(ctx context.Context, key string) error {
+	return undoScript.Run(ctx, r.client, []string{key}).Err()
+}
+
+func (r *Redis) Get(ctx context.Context, key string) (Failures, error) {
+	values, err := r.client.HMGet(ctx, key, "count", "last").Result()
+	if err != nil {
+		return Failures{}, err
+	}
+
+	return parseFailures(key, values)
+}
+
+func parseFailures(key string, values []interface{}) (Failures, error) {
+	count, _ := values[0].(string)
+	last, _ := values[1].(string)
+	if count == "" || last == "" {
+		return Failures{}, nil
+	}
+
+	n, err := strconv.ParseInt(count, 10, 64)
+	if err != nil {
+		return Failures{}, fmt.Errorf("invalid count %s: %w", key, err)
+	}
+
+	nanos, err := strconv.ParseInt(last, 10, 64)
+	if err != nil {
+		return Failures{}, fmt.Errorf("invalid time %s: %w", key, err)
+	}
+
+	return Failures{Count: n, Last: time.Unix(0, nanos)}, nil
+}
+
+func (r *Redis) Reset(ctx context.Context, key string) error {
+	return r.client.Del(ctx, key).Err()
+}
@@ -0,0 +1,78 @@
+package throttle
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// Failures is how many attempts in a row have failed under a key, and when
+// the last one failed
+type Failures struct {
+	Count int64
+	Last  time.Time
+}
+
+// Store counts failed attempts. A count is forgotten once no attempt has
+// failed for the window it was recorded with, or when it is reset.
+//
+// Fail counts an attempt and returns the failures before it, in one step. An
+// attempt can so be counted before it is checked, and attempts made at the
+// same time each see the ones before them. Undo takes back an attempt that
+// turned out not to fail.
+type Store interface {
+	Fail(ctx context.Context, key string, window time.Duration) (Failures, error)
+	Undo(ctx context.Context, key string) error
+	Get(ctx context.Context, key string) (Failures, error)
+	Reset(ctx context.Context, key string) error
+}
+
+// Policy lets a few attempts fail freely, then makes each further attempt
+// wait twice as long as the one before, up to Max. A key waiting Max is
+// locked out.
+type Policy struct {
+	Free   int64
+	Base   time.Duration
+	Max    time.Duration
+	Window time.Duration
+}
+
+// Delay is how long to wait after count failures
+func (p Policy) Delay(count int64) time.Duration {
+	if count < p.Free {
+		return 0
+	}
+
+	delay := p.Base
+	for i := p.Free; i < count && delay < p.Max; i++ {
+		delay *= 2
+	}
+
+	return min(delay, p.Max)
+}
+
+// LockedUntil is when the next attempt may be made, which is in the past for
+// keys that are not held back
+func (p Policy) LockedUntil(f Failures) time.Time {
+	if f.Count == 0 {
+		return time.Time{}
+	}
+
+	return f.Last.Add(p.Delay(f.Count))
+}
+
+// Locked tells whether count failures lock a key out
+func (p Policy) Locked(count int64) bool {
+	return count > 0 && p.Delay(count) >= p.Max
+}
+
+// New uses Redis when REDIS is set, so every instance sees the same counts.
+// Otherwise counts are kept in memory.
+func New() (Store, error) {
+	addr := os.Getenv("REDIS")
+	if addr == "" {
+		return NewMemory(), nil
+	}
+
+	return NewRedis(addr, os.Getenv("REDIS_PSWD"))
+}
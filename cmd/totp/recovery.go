@@ -0,0 +1,38 @@
+package totp
+
+import (
+	"crypto/rand"
+	"strings"
+)
+
+// RecoveryCodes is how many recovery codes a user is given at a time
+const RecoveryCodes = 10
+
+// NewRecoveryCodes makes single use codes to sign in with when the
+// authenticator app is lost, written as xxxxx-xxxxx
+func NewRecoveryCodes() ([]string, error) {
+	codes := make([]string, RecoveryCodes)
+
+	for i := range codes {
+		b := make([]byte, 7)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+
+		code := strings.ToLower(encoding.EncodeToString(b))[:10]
+		codes[i] = code[:5] + "-" + code[5:]
+	}
+
+	return codes, nil
+}
+
+// NormalizeRecoveryCode lets a recovery code be typed in any case, with or
+// without its dash
+func NormalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	if len(code) != 10 {
+		return code
+	}
+
+	return code[:5] + "-" + code[5:]
+}
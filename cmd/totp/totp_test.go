@@ -0,0 +1,142 @@
+package totp
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 key of the RFC 6238 test vectors, in base32
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+// The RFC 6238 SHA-1 vectors, cut to the last 6 digits of their 8 digit codes
+var rfcVectors = []struct {
+	unix int64
+	code string
+}{
+	{unix: 59, code: "287082"},
+	{unix: 1111111109, code: "081804"},
+	{unix: 1111111111, code: "050471"},
+	{unix: 1234567890, code: "005924"},
+	{unix: 2000000000, code: "279037"},
+	{unix: 20000000000, code: "353130"},
+}
+
+func TestCode(t *testing.T) {
+	for _, tt := range rfcVectors {
+		got, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code() at %d: %v", tt.unix, err)
+		}
+
+		if got != tt.code {
+			t.Errorf("Code() at %d = %s, want %s", tt.unix, got, tt.code)
+		}
+	}
+}
+
+func TestCodeInvalidSecret(t *testing.T) {
+	if _, err := Code("not base32!", 1); err == nil {
+		t.Error("Code() with an invalid secret returned no error")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, tt := range rfcVectors {
+		now := time.Unix(tt.unix, 0)
+
+		step, ok, err := Validate(rfcSecret, tt.code, now)
+		if err != nil {
+			t.Fatalf("Validate() at %d: %v", tt.unix, err)
+		}
+
+		if !ok || step != Step(now) {
+			t.Errorf("Validate() at %d = %d, %v, want %d, true", tt.unix, step, ok, Step(now))
+		}
+	}
+}
+
+func TestValidateSkew(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+
+	tests := []struct {
+		name   string
+		offset int64
+		want   bool
+	}{
+		{name: "previous step", offset: -1, want: true},
+		{name: "next step", offset: 1, want: true},
+		{name: "two steps back", offset: -2, want: false},
+		{name: "two steps ahead", offset: 2, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := Code(rfcSecret, Step(now)+tt.offset)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			step, ok, err := Validate(rfcSecret, code, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if ok != tt.want {
+				t.Fatalf("Validate() = %v, want %v", ok, tt.want)
+			}
+
+			if ok && step != Step(now)+tt.offset {
+				t.Errorf("Validate() step = %d, want %d", step, Step(now)+tt.offset)
+			}
+		})
+	}
+}
+
+func TestValidateFormat(t *testing.T) {
+	now := time.Unix(59, 0)
+
+	tests := []struct {
+		name string
+		code string
+		want bool
+	}{
+		{name: "spaces are ignored", code: "287 082", want: true},
+		{name: "too short", code: "28708", want: false},
+		{name: "eight digits", code: "94287082", want: false},
+		{name: "wrong code", code: "287083", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ok, err := Validate(rfcSecret, tt.code, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if ok != tt.want {
+				t.Errorf("Validate(%q) = %v, want %v", tt.code, ok, tt.want)
+			}
+		})
+	}
+}
+
+// A code stays valid for the steps around it, so it matches the same step
+// each time and callers reject it by the step they kept
+func TestValidateReuse(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+
+	first, ok, err := Validate(rfcSecret, "005924", now)
+	if err != nil || !ok {
+		t.Fatalf("Validate() = %v, %v, want a match", ok, err)
+	}
+
+	again, ok, err := Validate(rfcSecret, "005924", now.Add(period*time.Second))
+	if err != nil || !ok {
+		t.Fatalf("Validate() a step later = %v, %v, want a match", ok, err)
+	}
+
+	if again != first {
+		t.Errorf("Validate() reused code matched step %d, want %d", again, first)
+	}
+}
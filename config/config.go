@@ -3,23 +3,49 @@ package config
 import "syscall"
 
 type Config struct {
-	PORT                 string
-	DB_USER              string
-	DB_PSWD              string
-	DB_HOST              string
-	DB_PORT              string
-	DB_NAME              string
-	JWT_CERT_PATH        string
-	JWT_PUB_CERT_PATH    string
-	REDIS                string
-	REDIS_PSWD           string
-	TWILIO_SID           string
-	TWILIO_TOKEN         string
-	TWILIO_SERVICE_SID   string
-	IMAGES_PATH          string
-	THUMBNAILS_PATH      string
-	PAYCHANGU_SECRET_KEY string
-	PAYCHANGU_PUBLIC_KEY string
+	PORT                   string
+	DB_USER                string
+	DB_PSWD                string
+	DB_HOST                string
+	DB_PORT                string
+	DB_NAME                string
+	JWT_CERT_PATH          string
+	JWT_PUB_CERT_PATH      string
+	REDIS                  string
+	REDIS_PSWD             string
+	SMS_DRIVER             string
+	TWILIO_SID             string
+	TWILIO_TOKEN           string
+	TWILIO_SERVICE_SID     string
+	UPLOADS_PATH           string
+	STORAGE_DRIVER         string
+	STORAGE_BASE_URL       string
+	S3_ENDPOINT            string
+	S3_REGION              string
+	S3_BUCKET              string
+	S3_ACCESS_KEY          string
+	S3_SECRET_KEY          string
+	S3_USE_SSL             string
+	UPLOAD_MAX_BYTES       string
+	UPLOAD_MAX_PIXELS      string
+	IMAGE_GC_INTERVAL      string
+	IMAGE_GC_GRACE         string
+	COSTING_METHOD         string
+	STOCK_CHECK_INTERVAL   string
+	STOCK_ALERT_EMAILS     string
+	STOCK_ALERT_WEBHOOK    string
+	RECEIPT_LOOKUP_URL     string
+	IDEMPOTENCY_KEY_TTL    string
+	ACCESS_TOKEN_TTL       string
+	REFRESH_TOKEN_TTL      string
+	PASSWORD_RESET_TTL     string
+	EMAIL_VERIFICATION_TTL string
+	PASSWORD_RESET_URL     string
+	EMAIL_VERIFICATION_URL string
+	REQUIRE_VERIFIED_EMAIL string
+	REQUIRE_ADMIN_MFA      string
+	PAYCHANGU_SECRET_KEY   string
+	PAYCHANGU_PUBLIC_KEY   string
 }
 
 func Load() *Config {
@@ -0,0 +1,406 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"api/cmd/helper"
+	"api/cmd/throttle"
+	"api/handler/dto"
+	"api/repository"
+
+	"github.com/go-playground/validator"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Password reset and verification links are limited per email and per
+// address, so mailboxes cannot be flooded with them
+var (
+	forgotEmailPolicy = throttle.Policy{Free: 3, Base: time.Minute, Max: time.Hour, Window: time.Hour}
+	forgotIPPolicy    = throttle.Policy{Free: 10, Base: time.Minute, Max: time.Hour, Window: time.Hour}
+)
+
+// actionLink adds a token to the page of the web app, read from the
+// environment variable env, where the user finishes what the token is for
+func actionLink(env string, fallback string, token string) string {
+	base := os.Getenv(env)
+	if base == "" {
+		base = fallback
+	}
+
+	link, err := url.Parse(base)
+	if err != nil {
+		return strings.TrimRight(base, "/") + "?token=" + url.QueryEscape(token)
+	}
+
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	return link.String()
+}
+
+// formatTTL writes how long a link works in words for an email
+func formatTTL(ttl time.Duration) string {
+	if ttl >= 24*time.Hour && ttl%(24*time.Hour) == 0 {
+		days := int(ttl / (24 * time.Hour))
+		if days == 1 {
+			return "1 day"
+		}
+		return fmt.Sprintf("%d days", days)
+	}
+
+	if ttl >= time.Hour && ttl%time.Hour == 0 {
+		hours := int(ttl / time.Hour)
+		if hours == 1 {
+			return "1 hour"
+		}
+		return fmt.Sprintf("%d hours", hours)
+	}
+
+	return fmt.Sprintf("%d minutes", int(ttl.Minutes()))
+}
+
+// sendPasswordReset mails a user a link to choose a new password. Links sent
+// before stop working.
+func (h *AuthHandler) sendPasswordReset(ctx context.Context, user repository.User) error {
+	ttl := helper.PasswordResetTTL()
+
+	token, err := h.newActionToken(ctx, user, helper.PurposePasswordReset, ttl)
+	if err != nil {
+		return err
+	}
+
+	body, err := helper.RenderEmail(helper.EmailPasswordReset, helper.EmailData{
+		Name:    user.Firstname,
+		Link:    actionLink("PASSWORD_RESET_URL", "https://fixchirp.com/reset-password", token),
+		Expires: formatTTL(ttl),
+	})
+	if err != nil {
+		return err
+	}
+
+	return helper.SendEmail(user.Email.String, "Reset your password", body)
+}
+
+// sendVerification mails a user a link that confirms their email address.
+// Links sent before stop working.
+func (h *AuthHandler) sendVerification(ctx context.Context, user repository.User) error {
+	ttl := helper.EmailVerificationTTL()
+
+	token, err := h.newActionToken(ctx, user, helper.PurposeEmailVerification, ttl)
+	if err != nil {
+		return err
+	}
+
+	body, err := helper.RenderEmail(helper.EmailVerification, helper.EmailData{
+		Name:    user.Firstname,
+		Link:    actionLink("EMAIL_VERIFICATION_URL", "https://fixchirp.com/verify-email", token),
+		Expires: formatTTL(ttl),
+	})
+	if err != nil {
+		return err
+	}
+
+	return helper.SendEmail(user.Email.String, "Verify your email", body)
+}
+
+// newActionToken stores a single use token for a user and signs it
+func (h *AuthHandler) newActionToken(ctx context.Context, user repository.User, purpose string, ttl time.Duration) (string, error) {
+	if err := h.repo.RevokeUserTokens(ctx, repository.RevokeUserTokensParams{
+		UserID:  user.ID,
+		Purpose: purpose,
+	}); err != nil {
+		return "", err
+	}
+
+	id := uuid.NewString()
+	expiresAt := time.Now().Add(ttl)
+
+	if err := h.repo.InsertUserToken(ctx, repository.InsertUserTokenParams{
+		ID:        id,
+		UserID:    user.ID,
+		Purpose:   purpose,
+		ExpiresAt: expiresAt,
+	}); err != nil {
+		return "", err
+	}
+
+	return h.issuer.IssueActionToken(user.ID, purpose, id, expiresAt)
+}
+
+// useActionToken checks a token and marks it used, so it cannot be used again
+func useActionToken(ctx context.Context, repo *repository.Queries, validator *helper.Validator, tokenString string, purpose string) (helper.ActionToken, bool, error) {
+	token, err := validator.GetActionToken(tokenString, purpose)
+	if err != nil {
+		return token, false, nil
+	}
+
+	used, err := repo.UseUserToken(ctx, repository.UseUserTokenParams{
+		ID:      token.ID,
+		UserID:  token.UserID,
+		Purpose: purpose,
+	})
+	if err != nil {
+		return token, false, err
+	}
+
+	return token, used == 1, nil
+}
+
+// Forgot mails a password reset link. It answers the same, and as fast,
+// whether or not the email belongs to a user, as the link is sent in the
+// background, so it cannot be used to find out who has an account.
+func (h *AuthHandler) Forgot(w http.ResponseWriter, r *http.Request) {
+	var data dto.EmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(data); err != nil {
+		http.Error(w, validationMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	if !mailAttempt(w, r, h.attempts, "forgot", data.Email) {
+		return
+	}
+
+	go h.forgot(data.Email)
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// mailAttempt counts a request for a link mailed to email against the email
+// and the address it came from, under keys starting with kind. It writes the
+// response when the request has to wait.
+func mailAttempt(w http.ResponseWriter, r *http.Request, attempts throttle.Store, kind, email string) bool {
+	ctx := r.Context()
+
+	byEmail, err := attempts.Fail(ctx, kind+":email:"+strings.ToLower(strings.TrimSpace(email)), forgotEmailPolicy.Window)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return false
+	}
+
+	byAddress, err := attempts.Fail(ctx, kind+":ip:"+clientIP(r), forgotIPPolicy.Window)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return false
+	}
+
+	until := forgotEmailPolicy.LockedUntil(byEmail)
+	if at := forgotIPPolicy.LockedUntil(byAddress); at.After(until) {
+		until = at
+	}
+
+	if wait := time.Until(until); wait > 0 {
+		tooManyRequests(w, "Too many requests, try again later", retryAfter(wait))
+		return false
+	}
+
+	return true
+}
+
+// forgot sends the password reset link of Forgot, if the email has an account
+func (h *AuthHandler) forgot(email string) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	user, err := h.repo.FindUserByEmail(ctx, sql.NullString{String: email, Valid: true})
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			fmt.Println(err)
+		}
+		return
+	}
+
+	if err := h.sendPasswordReset(ctx, user); err != nil {
+		fmt.Println(err)
+	}
+}
+
+// Reset sets a new password with the token of a password reset link. Every
+// session of the user is ended, as whoever knew the old password may be
+// signed in.
+func (h *AuthHandler) Reset(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var data dto.ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(data); err != nil {
+		http.Error(w, validationMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	password, err := bcrypt.GenerateFromPassword([]byte(data.Password), bcrypt.DefaultCost)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	repo := h.repo.WithTx(tx)
+
+	token, ok, err := useActionToken(ctx, repo, h.validator, data.Token, helper.PurposePasswordReset)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if !ok {
+		http.Error(w, "Invalid or expired token", http.StatusBadRequest)
+		return
+	}
+
+	if err := repo.UpdateUserPassword(ctx, repository.UpdateUserPasswordParams{
+		Password: string(password),
+		ID:       token.UserID,
+	}); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	// The link was mailed to the user, so the address is theirs
+	if err := repo.VerifyUserEmail(ctx, token.UserID); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err := revokeUserSessions(ctx, h.repo, h.revocations, token.UserID); err != nil {
+		fmt.Println(err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Verify confirms the email address of a user with the token of a
+// verification link
+func (h *AuthHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	tokenString := r.URL.Query().Get("token")
+	if tokenString == "" {
+		http.Error(w, "Provide token param from URL query", http.StatusBadRequest)
+		return
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	repo := h.repo.WithTx(tx)
+
+	token, ok, err := useActionToken(ctx, repo, h.validator, tokenString, helper.PurposeEmailVerification)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if !ok {
+		http.Error(w, "Invalid or expired token", http.StatusBadRequest)
+		return
+	}
+
+	if err := repo.VerifyUserEmail(ctx, token.UserID); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ResendVerification mails a new verification link to a user who has not
+// verified their email yet. Like Forgot it is limited per email and address,
+// and answers the same, and as fast, for any email.
+func (h *AuthHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	var data dto.EmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(data); err != nil {
+		http.Error(w, validationMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	if !mailAttempt(w, r, h.attempts, "verify", data.Email) {
+		return
+	}
+
+	go h.resendVerification(data.Email)
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// resendVerification sends the link of ResendVerification, if the email has
+// an account that is not verified yet
+func (h *AuthHandler) resendVerification(email string) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	user, err := h.repo.FindUserByEmail(ctx, sql.NullString{String: email, Valid: true})
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			fmt.Println(err)
+		}
+		return
+	}
+
+	if user.EmailVerifiedAt.Valid {
+		return
+	}
+
+	if err := h.sendVerification(ctx, user); err != nil {
+		fmt.Println(err)
+	}
+}
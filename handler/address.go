@@ -0,0 +1,446 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"api/cmd/middleware"
+	"api/handler/dto"
+	"api/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator"
+)
+
+// maxAddresses is how many addresses a customer can keep
+const maxAddresses = 20
+
+type addressHandler struct {
+	db   *sql.DB
+	repo *repository.Queries
+}
+
+func NewAddressHandler(db *sql.DB, repo *repository.Queries) *addressHandler {
+	return &addressHandler{db: db, repo: repo}
+}
+
+func addressResponse(address repository.UserAddress) dto.AddressResponse {
+	response := dto.AddressResponse{
+		ID:        address.ID,
+		Recipient: address.Recipient,
+		Line1:     address.Line1,
+		City:      address.City,
+		Country:   address.Country,
+		Default:   address.IsDefault,
+	}
+
+	if address.Label.Valid {
+		response.Label = &address.Label.String
+	}
+
+	if address.Phone.Valid {
+		response.Phone = &address.Phone.String
+	}
+
+	if address.Line2.Valid {
+		response.Line2 = &address.Line2.String
+	}
+
+	if address.Region.Valid {
+		response.Region = &address.Region.String
+	}
+
+	if address.PostalCode.Valid {
+		response.PostalCode = &address.PostalCode.String
+	}
+
+	return response
+}
+
+// readAddress decodes and validates an address
+func readAddress(w http.ResponseWriter, r *http.Request) (dto.AddressRequest, bool) {
+	var form dto.AddressRequest
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return form, false
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(form); err != nil {
+		http.Error(w, validationMessage(err), http.StatusBadRequest)
+		return form, false
+	}
+
+	form.Country = strings.ToUpper(form.Country)
+
+	return form, true
+}
+
+// findAddress finds an address of the signed in customer, so customers cannot
+// see or change the addresses of others
+func (h *addressHandler) findAddress(w http.ResponseWriter, r *http.Request) (repository.UserAddress, bool) {
+	userID, err := middleware.UserID(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return repository.UserAddress{}, false
+	}
+
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid address ID", http.StatusBadRequest)
+		return repository.UserAddress{}, false
+	}
+
+	address, err := h.repo.FindUserAddress(r.Context(), repository.FindUserAddressParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Address not found", http.StatusNotFound)
+		} else {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return address, false
+	}
+
+	return address, true
+}
+
+// List the addresses of the signed in customer, the default first
+func (h *addressHandler) CustomerFindAll(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.UserID(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	addresses, err := h.repo.FindUserAddresses(r.Context(), userID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	var response = []dto.AddressResponse{}
+
+	for _, address := range addresses {
+		response = append(response, addressResponse(address))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *addressHandler) CustomerFindOne(w http.ResponseWriter, r *http.Request) {
+	address, ok := h.findAddress(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(addressResponse(address))
+}
+
+// Add an address. The first address becomes the default.
+func (h *addressHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.UserID(ctx)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	form, ok := readAddress(w, r)
+	if !ok {
+		return
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	repo := h.repo.WithTx(tx)
+
+	count, err := repo.CountUserAddresses(ctx, userID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if count >= maxAddresses {
+		http.Error(w, fmt.Sprintf("You can keep at most %d addresses", maxAddresses), http.StatusConflict)
+		return
+	}
+
+	isDefault := form.Default || count == 0
+
+	if isDefault {
+		if err := repo.ClearDefaultUserAddress(ctx, userID); err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	id, err := repo.InsertUserAddress(ctx, repository.InsertUserAddressParams{
+		UserID:     userID,
+		Label:      nullString(form.Label),
+		Recipient:  form.Recipient,
+		Phone:      nullString(form.Phone),
+		Line1:      form.Line1,
+		Line2:      nullString(form.Line2),
+		City:       form.City,
+		Region:     nullString(form.Region),
+		PostalCode: nullString(form.PostalCode),
+		Country:    form.Country,
+		IsDefault:  isDefault,
+	})
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to add address", http.StatusInternalServerError)
+		return
+	}
+
+	address, err := repo.FindUserAddress(ctx, repository.FindUserAddressParams{
+		ID:     uint64(id),
+		UserID: userID,
+	})
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(addressResponse(address))
+}
+
+// Update an address. It can be made the default, but the default can only be
+// moved by making another address the default.
+func (h *addressHandler) CustomerUpdate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	address, ok := h.findAddress(w, r)
+	if !ok {
+		return
+	}
+
+	form, ok := readAddress(w, r)
+	if !ok {
+		return
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	repo := h.repo.WithTx(tx)
+
+	if err := repo.UpdateUserAddress(ctx, repository.UpdateUserAddressParams{
+		Label:      nullString(form.Label),
+		Recipient:  form.Recipient,
+		Phone:      nullString(form.Phone),
+		Line1:      form.Line1,
+		Line2:      nullString(form.Line2),
+		City:       form.City,
+		Region:     nullString(form.Region),
+		PostalCode: nullString(form.PostalCode),
+		Country:    form.Country,
+		ID:         address.ID,
+		UserID:     address.UserID,
+	}); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to update address", http.StatusInternalServerError)
+		return
+	}
+
+	if form.Default && !address.IsDefault {
+		if err := setDefaultAddress(ctx, repo, address); err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	address, err = repo.FindUserAddress(ctx, repository.FindUserAddressParams{
+		ID:     address.ID,
+		UserID: address.UserID,
+	})
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(addressResponse(address))
+}
+
+// Make an address the one orders are delivered to when none is picked
+func (h *addressHandler) CustomerSetDefault(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	address, ok := h.findAddress(w, r)
+	if !ok {
+		return
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	if err := setDefaultAddress(ctx, h.repo.WithTx(tx), address); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Remove an address. When it was the default, the newest address left takes
+// its place. Orders delivered to it keep their own copy of it.
+func (h *addressHandler) CustomerDelete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	address, ok := h.findAddress(w, r)
+	if !ok {
+		return
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	repo := h.repo.WithTx(tx)
+
+	if err := repo.DeleteUserAddress(ctx, repository.DeleteUserAddressParams{
+		ID:     address.ID,
+		UserID: address.UserID,
+	}); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to delete address", http.StatusInternalServerError)
+		return
+	}
+
+	if address.IsDefault {
+		if err := repo.PromoteUserAddress(ctx, address.UserID); err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// setDefaultAddress makes an address the only default of its customer
+func setDefaultAddress(ctx context.Context, repo *repository.Queries, address repository.UserAddress) error {
+	if err := repo.ClearDefaultUserAddress(ctx, address.UserID); err != nil {
+		return err
+	}
+
+	return repo.SetDefaultUserAddress(ctx, repository.SetDefaultUserAddressParams{
+		ID:     address.ID,
+		UserID: address.UserID,
+	})
+}
+
+// deliverTo copies the address an online order goes to onto its details, so
+// the order keeps it when the address is changed or removed
+func deliverTo(details *repository.InsertOnlineOrderDetailsParams, address repository.UserAddress) {
+	details.AddressID = sql.NullInt64{Int64: int64(address.ID), Valid: true}
+	details.DeliveryRecipient = sql.NullString{String: address.Recipient, Valid: true}
+	details.DeliveryPhone = address.Phone
+	details.DeliveryLine1 = sql.NullString{String: address.Line1, Valid: true}
+	details.DeliveryLine2 = address.Line2
+	details.DeliveryCity = sql.NullString{String: address.City, Valid: true}
+	details.DeliveryRegion = address.Region
+	details.DeliveryPostalCode = address.PostalCode
+	details.DeliveryCountry = sql.NullString{String: address.Country, Valid: true}
+}
+
+// orderAddress is the address an online order is delivered to, as it was when
+// the order was placed. Orders without one, or whose copy was removed with the
+// account of the customer, have none.
+func orderAddress(details repository.OnlineOrderDetail) *dto.AddressResponse {
+	if !details.DeliveryLine1.Valid {
+		return nil
+	}
+
+	response := dto.AddressResponse{
+		ID:        uint64(details.AddressID.Int64),
+		Recipient: details.DeliveryRecipient.String,
+		Line1:     details.DeliveryLine1.String,
+		City:      details.DeliveryCity.String,
+		Country:   details.DeliveryCountry.String,
+	}
+
+	if details.DeliveryPhone.Valid {
+		response.Phone = &details.DeliveryPhone.String
+	}
+
+	if details.DeliveryLine2.Valid {
+		response.Line2 = &details.DeliveryLine2.String
+	}
+
+	if details.DeliveryRegion.Valid {
+		response.Region = &details.DeliveryRegion.String
+	}
+
+	if details.DeliveryPostalCode.Valid {
+		response.PostalCode = &details.DeliveryPostalCode.String
+	}
+
+	return &response
+}
@@ -2,27 +2,113 @@ package handler
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
 	"net/http"
+	"time"
 
 	"api/cmd/helper"
+	"api/cmd/revocation"
+	"api/cmd/sms"
+	"api/cmd/throttle"
 	"api/handler/dto"
 	"api/repository"
 
 	"github.com/go-playground/validator"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthHandler struct {
-	repo   *repository.Queries
-	issuer *helper.Issuer
+	db          *sql.DB
+	repo        *repository.Queries
+	issuer      *helper.Issuer
+	validator   *helper.Validator
+	revocations revocation.Store
+	verifier    sms.SMSVerifier
+	attempts    throttle.Store
 }
 
-func NewAuthHandler(repo *repository.Queries, issuer *helper.Issuer) *AuthHandler {
-	return &AuthHandler{repo: repo, issuer: issuer}
+func NewAuthHandler(db *sql.DB, repo *repository.Queries, issuer *helper.Issuer, validator *helper.Validator, revocations revocation.Store, verifier sms.SMSVerifier, attempts throttle.Store) *AuthHandler {
+	return &AuthHandler{db: db, repo: repo, issuer: issuer, validator: validator, revocations: revocations, verifier: verifier, attempts: attempts}
+}
+
+// newRefreshToken makes a random refresh token. Only its hash is stored, so a
+// leaked database cannot be used to sign in.
+func newRefreshToken() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// issue signs an access token and stores a new refresh token in the family of
+// a session, returning the ID of the refresh token. The store picked at sign
+// in, and whether a second factor was given, are kept by every token of the
+// session.
+func (h *AuthHandler) issue(ctx context.Context, repo *repository.Queries, user repository.User, family string, store sql.NullInt64, mfa bool, r *http.Request) (dto.LoginResponse, int64, error) {
+	userRoles, err := repo.FindUserRoles(ctx, user.ID)
+	if err != nil {
+		return dto.LoginResponse{}, 0, err
+	}
+
+	// Extract role names
+	roles := make([]string, len(userRoles))
+	for i, role := range userRoles {
+		roles[i] = role.Name
+	}
+
+	refresh, hash, err := newRefreshToken()
+	if err != nil {
+		return dto.LoginResponse{}, 0, err
+	}
+
+	userAgent := r.UserAgent()
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+
+	id, err := repo.InsertRefreshToken(ctx, repository.InsertRefreshTokenParams{
+		UserID:    user.ID,
+		FamilyID:  family,
+		TokenHash: hash,
+		UserAgent: sql.NullString{String: userAgent, Valid: userAgent != ""},
+		ExpiresAt: time.Now().Add(helper.RefreshTokenTTL()),
+		StoreID:   store,
+		Mfa:       mfa,
+	})
+	if err != nil {
+		return dto.LoginResponse{}, 0, err
+	}
+
+	token, err := h.issuer.IssueToken(uint(user.ID), fmt.Sprint(user.Firstname, user.Lastname), roles, family, uint64(store.Int64), mfa)
+	if err != nil {
+		return dto.LoginResponse{}, 0, err
+	}
+
+	response := dto.LoginResponse{
+		Token:        token,
+		ExpiresIn:    int64(h.issuer.TTL().Seconds()),
+		RefreshToken: refresh,
+	}
+
+	return response, id, nil
 }
 
 func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
@@ -62,7 +148,7 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 
 	ctx := context.Background()
 
-	_, err = h.repo.FindUserByEmail(ctx, data.Email)
+	_, err = h.repo.FindUserByEmail(ctx, sql.NullString{String: data.Email, Valid: true})
 	if err == nil {
 		http.Error(w, "User already exists", http.StatusBadRequest)
 		return
@@ -71,7 +157,7 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	userID, err := h.repo.InsertUser(ctx, repository.InsertUserParams{
 		Firstname: data.Firstname,
 		Lastname:  data.Lastname,
-		Email:     data.Email,
+		Email:     sql.NullString{String: data.Email, Valid: true},
 		Password:  string(password),
 	})
 	if err != nil {
@@ -94,6 +180,18 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The account works straight away, the email is verified through the link
+	user, err := h.repo.FindUserByID(ctx, uint64(userID))
+	if err != nil {
+		fmt.Printf("error: %s", err.Error())
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.sendVerification(ctx, user); err != nil {
+		fmt.Println(err)
+	}
+
 	response := dto.RegisterResponse{
 		ID: userID,
 	}
@@ -130,12 +228,148 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	cxt := context.Background()
+	ctx := r.Context()
+
+	user, err := h.repo.FindUserByEmail(ctx, sql.NullString{String: data.Email, Valid: true})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	found := err == nil
+
+	var userID sql.NullInt64
+	if found {
+		userID = sql.NullInt64{Int64: int64(user.ID), Valid: true}
+	}
+
+	wait, err := loginAttempt(ctx, h.repo, h.attempts, r, data.Email, userID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if wait > 0 {
+		tooManyRequests(w, "Too many failed sign ins, try again later", retryAfter(wait))
+		return
+	}
+
+	// Unknown emails and accounts without a password are checked against a
+	// dummy hash, so they take as long to answer as a wrong password
+	hash := []byte(user.Password)
+	if !found || user.Password == "" {
+		hash = dummyHash()
+	}
+
+	err = bcrypt.CompareHashAndPassword(hash, []byte(data.Password))
+	if err != nil || !found || user.Password == "" {
+		loginFailed(ctx, h.repo, r, data.Email, userID)
+		http.Error(w, "Forbiden", http.StatusUnauthorized)
+		return
+	}
+
+	// With two-factor authentication the attempt stays counted until the
+	// second factor is checked, so new challenges cannot be used to keep
+	// guessing codes
+	enrolled, err := h.mfaEnabled(ctx, user.ID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if !enrolled {
+		loginSucceeded(ctx, h.attempts, r, data.Email)
+	}
+
+	h.signIn(w, r, user, data.StoreID, false)
+}
+
+// signIn starts a session for a user whose credentials have been checked. A
+// user working at several stores can pick the one they sign in for. Users
+// with two-factor authentication get a challenge instead, unless mfa tells
+// that their second factor has been checked too.
+func (h *AuthHandler) signIn(w http.ResponseWriter, r *http.Request, user repository.User, storeID *uint64, mfa bool) {
+	ctx := r.Context()
+
+	// Deactivated users keep their records but cannot sign in
+	if user.DeactivatedAt.Valid {
+		http.Error(w, "Account deactivated", http.StatusForbidden)
+		return
+	}
+
+	var store sql.NullInt64
+
+	if storeID != nil {
+		assigned, err := h.repo.CheckStoreUser(ctx, repository.CheckStoreUserParams{
+			StoreID: *storeID,
+			UserID:  user.ID,
+		})
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		if !assigned {
+			http.Error(w, "You are not assigned to this store", http.StatusForbidden)
+			return
+		}
+
+		store = sql.NullInt64{Int64: int64(*storeID), Valid: true}
+	}
+
+	if !mfa {
+		enrolled, err := h.mfaEnabled(ctx, user.ID)
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		if enrolled {
+			h.mfaChallenge(w, r, user)
+			return
+		}
+	}
 
-	user, err := h.repo.FindUserByEmail(cxt, data.Email)
+	// Every sign in starts a new session, a family of refresh tokens
+	response, _, err := h.issue(ctx, h.repo, user, uuid.NewString(), store, mfa, r)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// Refresh swaps a refresh token for a new access token and refresh token. Each
+// refresh token can be used once; using one again means it was stolen, so the
+// whole session is revoked.
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var data dto.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(data); err != nil {
+		http.Error(w, validationMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	stored, err := h.repo.FindRefreshTokenByHash(ctx, hashToken(data.RefreshToken))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			http.Error(w, "Forbiden", http.StatusUnauthorized)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
 		}
 
@@ -144,38 +378,140 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(data.Password))
+	if stored.RevokedAt.Valid || stored.ExpiresAt.Before(time.Now()) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if stored.UsedAt.Valid {
+		h.revokeReused(ctx, stored)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := h.repo.FindUserByID(ctx, stored.UserID)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
 		fmt.Println(err)
-		http.Error(w, "Forbiden", http.StatusUnauthorized)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
 		return
 	}
 
-	userRoles, err := h.repo.FindUserRoles(cxt, user.ID)
+	if user.DeactivatedAt.Valid {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// The session loses its store once the user no longer works there
+	store := stored.StoreID
+	if store.Valid {
+		assigned, err := h.repo.CheckStoreUser(ctx, repository.CheckStoreUserParams{
+			StoreID: uint64(store.Int64),
+			UserID:  user.ID,
+		})
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		if !assigned {
+			store = sql.NullInt64{}
+		}
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
 	if err != nil {
 		fmt.Println(err)
 		http.Error(w, "Something went wrong", http.StatusInternalServerError)
 		return
 	}
+	defer tx.Rollback()
 
-	// Extract role names
-	roles := make([]string, len(userRoles))
-	for i, role := range userRoles {
-		roles[i] = role.Name
+	repo := h.repo.WithTx(tx)
+
+	response, id, err := h.issue(ctx, repo, user, stored.FamilyID, store, stored.Mfa, r)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
 	}
 
-	token, err := h.issuer.IssueToken(uint(user.ID), fmt.Sprint(user.Firstname, user.Lastname), roles)
+	used, err := repo.UseRefreshToken(ctx, repository.UseRefreshTokenParams{
+		ReplacedBy: sql.NullInt64{Int64: id, Valid: true},
+		ID:         stored.ID,
+	})
 	if err != nil {
 		fmt.Println(err)
 		http.Error(w, "Something went wrong", http.StatusInternalServerError)
 		return
 	}
 
-	response := dto.LoginResponse{
-		Token: token,
+	// Another request used the token first
+	if used == 0 {
+		tx.Rollback()
+		h.revokeReused(ctx, stored)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
+
+// revokeReused ends the session of a refresh token that was used twice
+func (h *AuthHandler) revokeReused(ctx context.Context, stored repository.RefreshToken) {
+	log.Printf("refresh token %d of user %d was reused, revoking session %s", stored.ID, stored.UserID, stored.FamilyID)
+
+	if err := revokeSession(ctx, h.repo, h.revocations, stored.FamilyID); err != nil {
+		fmt.Println(err)
+	}
+}
+
+// Logout ends the session of a refresh token. The access tokens of the
+// session stop working as well.
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var data dto.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(data); err != nil {
+		http.Error(w, validationMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	stored, err := h.repo.FindRefreshTokenByHash(ctx, hashToken(data.RefreshToken))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err := revokeSession(ctx, h.repo, h.revocations, stored.FamilyID); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
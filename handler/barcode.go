@@ -0,0 +1,515 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"api/cmd/label"
+	"api/handler/dto"
+	"api/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator"
+)
+
+// maxLabels caps the labels printed in one go
+const maxLabels = 2000
+
+type barcodeHandler struct {
+	db   *sql.DB
+	repo *repository.Queries
+}
+
+func NewBarcodeHandler(db *sql.DB, repo *repository.Queries) *barcodeHandler {
+	return &barcodeHandler{db: db, repo: repo}
+}
+
+func barcodeResponse(b repository.ProductBarcode) dto.BarcodeResponse {
+	return dto.BarcodeResponse{
+		ID:        b.ID,
+		ProductID: b.ProductID,
+		Code:      b.Code,
+		Symbology: string(b.Symbology),
+		Primary:   b.IsPrimary,
+	}
+}
+
+// findProductByCode finds a product by its SKU or, failing that, by one of
+// its barcodes, so a scanned code can be used wherever a SKU is typed
+func findProductByCode(ctx context.Context, repo *repository.Queries, code string) (repository.Product, error) {
+	p, err := repo.FindProductBySKU(ctx, code)
+	if err != sql.ErrNoRows {
+		return p, err
+	}
+
+	return repo.FindProductByBarcode(ctx, strings.TrimSpace(code))
+}
+
+// productLabel is the label of a product, with its primary barcode or, when it
+// has none, its SKU as a Code 128 barcode
+func productLabel(ctx context.Context, repo *repository.Queries, p repository.Product, price *float64) (label.Label, error) {
+	l := label.Label{
+		Name:      p.Name,
+		SKU:       p.Sku,
+		Code:      p.Sku,
+		Symbology: label.SymbologyCode128,
+		Price:     price,
+	}
+
+	barcodes, err := repo.FindProductBarcodes(ctx, p.ID)
+	if err != nil {
+		return l, err
+	}
+
+	if len(barcodes) > 0 {
+		l.Code = barcodes[0].Code
+		l.Symbology = string(barcodes[0].Symbology)
+	}
+
+	return l, nil
+}
+
+func (h *barcodeHandler) findProduct(w http.ResponseWriter, r *http.Request) (repository.Product, bool) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		return repository.Product{}, false
+	}
+
+	p, err := h.repo.FindProduct(r.Context(), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Product not found", http.StatusNotFound)
+		} else {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return p, false
+	}
+
+	return p, true
+}
+
+// add stores a barcode for a product. The first barcode of a product is its
+// primary one, which is the one printed on labels.
+func (h *barcodeHandler) add(ctx context.Context, productID uint64, code, symbology string, primary bool) (repository.ProductBarcode, error) {
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return repository.ProductBarcode{}, err
+	}
+	defer tx.Rollback()
+
+	repo := h.repo.WithTx(tx)
+
+	existing, err := repo.FindProductBarcodes(ctx, productID)
+	if err != nil {
+		return repository.ProductBarcode{}, err
+	}
+
+	primary = primary || len(existing) == 0
+
+	if primary {
+		if err := repo.ClearPrimaryProductBarcode(ctx, productID); err != nil {
+			return repository.ProductBarcode{}, err
+		}
+	}
+
+	id, err := repo.InsertProductBarcode(ctx, repository.InsertProductBarcodeParams{
+		ProductID: productID,
+		Code:      code,
+		Symbology: repository.ProductBarcodesSymbology(symbology),
+		IsPrimary: primary,
+	})
+	if err != nil {
+		return repository.ProductBarcode{}, err
+	}
+
+	b, err := repo.FindProductBarcode(ctx, repository.FindProductBarcodeParams{ID: uint64(id), ProductID: productID})
+	if err != nil {
+		return b, err
+	}
+
+	return b, tx.Commit()
+}
+
+// List the barcodes of a product
+func (h *barcodeHandler) AdminFindAll(w http.ResponseWriter, r *http.Request) {
+	p, ok := h.findProduct(w, r)
+	if !ok {
+		return
+	}
+
+	barcodes, err := h.repo.FindProductBarcodes(r.Context(), p.ID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	var response = []dto.BarcodeResponse{}
+
+	for _, b := range barcodes {
+		response = append(response, barcodeResponse(b))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// Add an EAN-13, UPC-A or Code 128 barcode to a product. Without a symbology
+// it is worked out from the code.
+func (h *barcodeHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p, ok := h.findProduct(w, r)
+	if !ok {
+		return
+	}
+
+	var form dto.CreateBarcodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(form); err != nil {
+		http.Error(w, validationMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	symbology := form.Symbology
+	if symbology == "" {
+		symbology = label.Detect(strings.TrimSpace(form.Code))
+	}
+
+	code, err := label.Normalize(symbology, form.Code)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.repo.FindProductBarcodeByCode(ctx, code)
+	if err == nil {
+		http.Error(w, "Barcode is already in use", http.StatusConflict)
+		return
+	}
+	if err != sql.ErrNoRows {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	b, err := h.add(ctx, p.ID, code, symbology, form.Primary)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to add barcode", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(barcodeResponse(b))
+}
+
+// Generate gives a product an internal EAN-13 barcode made from its ID. It
+// can be called again, the product keeps the same code.
+func (h *barcodeHandler) Generate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p, ok := h.findProduct(w, r)
+	if !ok {
+		return
+	}
+
+	code, err := label.Internal(p.ID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	b, err := h.repo.FindProductBarcodeByCode(ctx, code)
+	switch {
+	case err == nil && b.ProductID == p.ID:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(barcodeResponse(b))
+		return
+	case err == nil:
+		http.Error(w, "Barcode is already in use", http.StatusConflict)
+		return
+	case err != sql.ErrNoRows:
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	b, err = h.add(ctx, p.ID, code, label.SymbologyEAN13, false)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to add barcode", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(barcodeResponse(b))
+}
+
+// Remove a barcode from a product. When it was the primary barcode the next
+// one takes its place.
+func (h *barcodeHandler) AdminDelete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p, ok := h.findProduct(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(chi.URLParam(r, "barcodeID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid barcode ID", http.StatusBadRequest)
+		return
+	}
+
+	b, err := h.repo.FindProductBarcode(ctx, repository.FindProductBarcodeParams{ID: id, ProductID: p.ID})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Barcode not found", http.StatusNotFound)
+		} else {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	repo := h.repo.WithTx(tx)
+
+	if err := repo.DeleteProductBarcode(ctx, b.ID); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to delete barcode", http.StatusInternalServerError)
+		return
+	}
+
+	if b.IsPrimary {
+		remaining, err := repo.FindProductBarcodes(ctx, p.ID)
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		if len(remaining) > 0 {
+			if err := repo.SetPrimaryProductBarcode(ctx, remaining[0].ID); err != nil {
+				fmt.Println(err)
+				http.Error(w, "Something went wrong", http.StatusInternalServerError)
+				return
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AdminLookup finds the product a scanned code belongs to
+func (h *barcodeHandler) AdminLookup(w http.ResponseWriter, r *http.Request) {
+	h.lookup(w, r)
+}
+
+// CashierLookup finds the product a scanned code belongs to
+func (h *barcodeHandler) CashierLookup(w http.ResponseWriter, r *http.Request) {
+	h.lookup(w, r)
+}
+
+// lookup finds a product by barcode or SKU
+func (h *barcodeHandler) lookup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	code := strings.TrimSpace(chi.URLParam(r, "code"))
+
+	response := dto.BarcodeProductResponse{Code: code}
+
+	b, err := h.repo.FindProductBarcodeByCode(ctx, code)
+	switch err {
+	case nil:
+		response.Symbology = string(b.Symbology)
+	case sql.ErrNoRows:
+		response.Symbology = label.SymbologyCode128
+	default:
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	p, err := findProductByCode(ctx, h.repo, code)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Product not found", http.StatusNotFound)
+		} else {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	response.ID = p.ID
+	response.SKU = p.Sku
+	response.Name = p.Name
+	response.Price = nullFloat(p.Price)
+	response.Status = p.Status
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func writeLabels(w http.ResponseWriter, name string, labels []label.Label) {
+	var buf bytes.Buffer
+	if err := label.Write(&buf, labels); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"%s.pdf\"", name))
+	w.Write(buf.Bytes())
+}
+
+// AdminLabels prints label sheets for a selection of products, at their
+// current price
+func (h *barcodeHandler) AdminLabels(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var form dto.LabelsRequest
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(form); err != nil {
+		http.Error(w, validationMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	var total int32
+	for _, item := range form.Items {
+		total += item.Copies
+	}
+
+	if total > maxLabels {
+		http.Error(w, fmt.Sprintf("Too many labels, at most %d can be printed at once", maxLabels), http.StatusBadRequest)
+		return
+	}
+
+	var labels []label.Label
+
+	for _, item := range form.Items {
+		p, err := h.repo.FindProduct(ctx, item.ProductID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				http.Error(w, fmt.Sprintf("Product not found: %d", item.ProductID), http.StatusNotFound)
+			} else {
+				fmt.Println(err)
+				http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		l, err := productLabel(ctx, h.repo, p, nullFloat(p.Price))
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		for i := int32(0); i < item.Copies; i++ {
+			labels = append(labels, l)
+		}
+	}
+
+	writeLabels(w, "labels", labels)
+}
+
+// AdminPurchaseLabels prints a label for every unit of a purchase, at its
+// selling price. ?copies prints a different number of labels.
+func (h *barcodeHandler) AdminPurchaseLabels(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid purchase ID", http.StatusBadRequest)
+		return
+	}
+
+	purchase, err := h.repo.FindPurchase(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Purchase not found", http.StatusNotFound)
+		} else {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	copies := purchase.Quantity
+
+	if value := r.URL.Query().Get("copies"); value != "" {
+		n, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || n < 1 {
+			http.Error(w, "Invalid number of copies", http.StatusBadRequest)
+			return
+		}
+
+		copies = int32(n)
+	}
+
+	if copies > maxLabels {
+		http.Error(w, fmt.Sprintf("Too many labels, at most %d can be printed at once", maxLabels), http.StatusBadRequest)
+		return
+	}
+
+	p, err := h.repo.FindProduct(ctx, purchase.ProductID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	price := purchase.SellingPrice
+
+	l, err := productLabel(ctx, h.repo, p, &price)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	var labels []label.Label
+	for i := int32(0); i < copies; i++ {
+		labels = append(labels, l)
+	}
+
+	writeLabels(w, fmt.Sprintf("labels-purchase-%d", purchase.ID), labels)
+}
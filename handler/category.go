@@ -6,37 +6,27 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"os"
 	"strconv"
-	"time"
 
-	"api/cmd/helper"
-	"api/cmd/middleware"
+	"api/cmd/storage"
 	"api/handler/dto"
 	"api/repository"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator"
-	"github.com/google/uuid"
 	"github.com/gosimple/slug"
 )
 
 type categoryHandler struct {
-	repo *repository.Queries
+	repo  *repository.Queries
+	blobs storage.BlobStore
 }
 
-func NewCategoryHandler(repo *repository.Queries) *categoryHandler {
-	return &categoryHandler{repo: repo}
+func NewCategoryHandler(repo *repository.Queries, blobs storage.BlobStore) *categoryHandler {
+	return &categoryHandler{repo: repo, blobs: blobs}
 }
 
 func (h *categoryHandler) Create(w http.ResponseWriter, r *http.Request) {
-	_, err := middleware.GuardAdmin(r.Context(), h.repo)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		w.Write([]byte("Unauthorised"))
-		return
-	}
-
 	var form dto.CreateCategoryRequest
 
 	form.Name = r.FormValue("name")
@@ -66,7 +56,7 @@ func (h *categoryHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err = h.repo.FindCategoryBySlug(context.Background(), slug.Make(form.Name))
+	_, err := h.repo.FindCategoryBySlug(context.Background(), slug.Make(form.Name))
 	if err == nil {
 		http.Error(w, "Category already exists", http.StatusBadRequest)
 		return
@@ -80,23 +70,15 @@ func (h *categoryHandler) Create(w http.ResponseWriter, r *http.Request) {
 		ShowProducts: form.ShowProducts,
 	}
 	// Get the uploaded file
-	file, handle, err := r.FormFile("image")
+	file, _, err := r.FormFile("image")
 	if err == nil {
-		filename, err := helper.UploadImage(file, handle.Header)
-		if err != nil {
-			fmt.Println(err)
-			http.Error(w, "Something went wrong", http.StatusInternalServerError)
-			return
-		}
-
-		imageID, err := h.repo.InsertImage(context.Background(), *filename)
+		imageID, err := saveImage(r.Context(), h.repo, h.blobs, file)
 		if err != nil {
-			fmt.Println(err)
-			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			imageError(w, err)
 			return
 		}
 
-		c.ImageID = sql.NullInt64{Int64: imageID, Valid: true}
+		c.ImageID = sql.NullInt64{Int64: int64(imageID), Valid: true}
 	} else {
 		c.ImageID = sql.NullInt64{}
 	}
@@ -137,13 +119,6 @@ func (h *categoryHandler) AdminFindAll(w http.ResponseWriter, r *http.Request) {
 
 	ctx := context.Background()
 
-	_, err := middleware.GuardAdmin(r.Context(), h.repo)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		w.Write([]byte("Unauthorised"))
-		return
-	}
-
 	// Parse query parameters for pagination
 	limitStr := r.URL.Query().Get("limit")
 	offsetStr := r.URL.Query().Get("offset")
@@ -209,13 +184,6 @@ func (h *categoryHandler) AdminFindAll(w http.ResponseWriter, r *http.Request) {
 func (h *categoryHandler) AdminFindOne(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
 
-	_, err := middleware.GuardAdmin(r.Context(), h.repo)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		w.Write([]byte("Unauthorised"))
-		return
-	}
-
 	// Get the category ID from URL parameters
 	categoryIDStr := chi.URLParam(r, "id")
 	fmt.Println(categoryIDStr)
@@ -258,13 +226,6 @@ func (h *categoryHandler) AdminFindOne(w http.ResponseWriter, r *http.Request) {
 func (h *categoryHandler) AdminUpdate(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
 
-	_, err := middleware.GuardAdmin(r.Context(), h.repo)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		w.Write([]byte("Unauthorised"))
-		return
-	}
-
 	// Get the category ID from URL parameters
 	categoryIDStr := chi.URLParam(r, "id")
 	fmt.Println(categoryIDStr)
@@ -330,74 +291,15 @@ func (h *categoryHandler) AdminUpdate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get the uploaded file
-	file, handle, err := r.FormFile("image")
+	file, _, err := r.FormFile("image")
 	if err == nil {
-		defer file.Close()
-
-		// Determine the file type
-		fileType := handle.Header.Get("Content-Type")
-		var ext string
-		switch fileType {
-		case "image/jpeg":
-			ext = ".jpg"
-		case "image/png":
-			ext = ".png"
-		default:
-			http.Error(w, "Unsupported file type", http.StatusUnsupportedMediaType)
-			return
-		}
-
-		// Generate a unique file name using UUID and timestamp
-		uuid := uuid.New()
-		timestamp := time.Now().Format("20060102150405")
-		filename := fmt.Sprintf("%s_%s%s", timestamp, uuid.String(), ext)
-
-		uploadPath := os.Getenv("UPLOADS_PATH")
-		if uploadPath == "" {
-			http.Error(w, "Something went wrong", http.StatusInternalServerError)
-			return
-		}
-
-		// Save the uploaded file
-		dst, err := os.Create(fmt.Sprintf("%s/%s", fmt.Sprintf("%s/images", os.Getenv("UPLOADS_PATH")), filename))
-		if err != nil {
-			fmt.Println(err)
-			http.Error(w, "Something went wrong", http.StatusInternalServerError)
-			return
-		}
-
-		defer dst.Close()
-
-		if _, err := dst.ReadFrom(file); err != nil {
-			fmt.Println(err)
-			http.Error(w, "Something went wrong", http.StatusInternalServerError)
-			return
-		}
-
-		// Open the saved file
-		src, err := helper.OpenImage(fmt.Sprintf("%s/%s", fmt.Sprintf("%s/images", os.Getenv("UPLOADS_PATH")), filename), fileType)
+		imageID, err := saveImage(r.Context(), h.repo, h.blobs, file)
 		if err != nil {
-			fmt.Println(err)
-			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			imageError(w, err)
 			return
 		}
 
-		// Create different quality versions
-		err = helper.CreateImageVersions(src, filename, ext, fmt.Sprintf("%s/thumbnails", os.Getenv("UPLOADS_PATH")))
-		if err != nil {
-			fmt.Println(err)
-			http.Error(w, "Something went wrong", http.StatusInternalServerError)
-			return
-		}
-
-		imageID, err := h.repo.InsertImage(context.Background(), filename)
-		if err != nil {
-			fmt.Println(err)
-			http.Error(w, "Something went wrong", http.StatusInternalServerError)
-			return
-		}
-
-		c.ImageID = sql.NullInt64{Int64: imageID, Valid: true}
+		c.ImageID = sql.NullInt64{Int64: int64(imageID), Valid: true}
 	} else {
 		c.ImageID = category.ImageID
 	}
@@ -437,13 +339,6 @@ func (h *categoryHandler) AdminUpdate(w http.ResponseWriter, r *http.Request) {
 func (h *categoryHandler) AdminDelete(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
 
-	_, err := middleware.GuardAdmin(r.Context(), h.repo)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		w.Write([]byte("Unauthorised"))
-		return
-	}
-
 	// Get the category ID from URL parameters
 	categoryIDStr := chi.URLParam(r, "id")
 	fmt.Println(categoryIDStr)
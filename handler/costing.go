@@ -0,0 +1,281 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+
+	"api/cmd/costing"
+	"api/cmd/helper"
+	"api/handler/dto"
+	"api/repository"
+)
+
+type costingHandler struct {
+	db    *sql.DB
+	repo  *repository.Queries
+	costs *costing.Engine
+}
+
+func NewCostingHandler(db *sql.DB, repo *repository.Queries, costs *costing.Engine) *costingHandler {
+	return &costingHandler{db: db, repo: repo, costs: costs}
+}
+
+func marginResponse(name string, units int64, revenue, cogs float64) dto.MarginResponse {
+	response := dto.MarginResponse{
+		Name:    name,
+		Units:   units,
+		Revenue: math.Round(revenue*100) / 100,
+		Cogs:    math.Round(cogs*100) / 100,
+		Margin:  math.Round((revenue-cogs)*100) / 100,
+	}
+
+	if revenue != 0 {
+		response.MarginPercent = math.Round((revenue-cogs)/revenue*10000) / 100
+	}
+
+	return response
+}
+
+// AdminRebuild recomputes stock costs and the cost of every order item from
+// the purchase and sales history
+func (h *costingHandler) AdminRebuild(w http.ResponseWriter, r *http.Request) {
+	result, err := h.costs.Rebuild(r.Context(), h.db)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// AdminValuation values the stock of every store, by default with the
+// configured costing method
+func (h *costingHandler) AdminValuation(w http.ResponseWriter, r *http.Request) {
+	format, err := exportFormat(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	method := costing.Method(r.URL.Query().Get("method"))
+	if method == "" {
+		method = h.costs.Method()
+	}
+
+	if !method.Valid() {
+		http.Error(w, "method should be fifo or weighted_average", http.StatusBadRequest)
+		return
+	}
+
+	var lines = []dto.ValuationResponse{}
+
+	if method == costing.FIFO {
+		rows, err := h.repo.FindFIFOValuation(r.Context())
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		for _, row := range rows {
+			lines = append(lines, dto.ValuationResponse{
+				StoreID:   row.StoreID,
+				Store:     row.Store,
+				ProductID: row.ProductID,
+				SKU:       row.Sku,
+				Name:      row.Name,
+				Quantity:  row.Quantity,
+				Value:     row.Value,
+			})
+		}
+	} else {
+		rows, err := h.repo.FindAverageValuation(r.Context())
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		for _, row := range rows {
+			lines = append(lines, dto.ValuationResponse{
+				StoreID:   row.StoreID,
+				Store:     row.Store,
+				ProductID: row.ProductID,
+				SKU:       row.Sku,
+				Name:      row.Name,
+				Quantity:  row.Quantity,
+				Value:     row.Value,
+			})
+		}
+	}
+
+	var total float64
+	for i := range lines {
+		lines[i].Value = math.Round(lines[i].Value*100) / 100
+		total += lines[i].Value
+	}
+
+	if format != "" {
+		var rows [][]interface{}
+		for _, line := range lines {
+			rows = append(rows, []interface{}{line.Store, line.SKU, line.Name, line.Quantity, helper.Money(line.Value)})
+		}
+
+		writeReport(w, format, "inventory-valuation", []string{"Store", "SKU", "Product", "Quantity", "Value"}, rows)
+		return
+	}
+
+	response := map[string]interface{}{
+		"method": method,
+		"total":  math.Round(total*100) / 100,
+		"data":   lines,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// AdminMargin reports revenue, cost of goods sold and gross margin grouped by
+// product, category, store or period. Order items sold before costing was
+// enabled have no cost and are left out.
+func (h *costingHandler) AdminMargin(w http.ResponseWriter, r *http.Request) {
+	format, err := exportFormat(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	from, to, err := reportRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	group := r.URL.Query().Get("group")
+	if group == "" {
+		group = "product"
+	}
+
+	var lines = []dto.MarginResponse{}
+
+	switch group {
+	case "product":
+		rows, err := h.repo.FindMarginByProduct(r.Context(), repository.FindMarginByProductParams{
+			FromDate: from,
+			ToDate:   to,
+		})
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		for _, row := range rows {
+			line := marginResponse(row.Name, row.Units, row.Revenue, row.Cogs)
+			productID := row.ProductID
+			line.ID = &productID
+			line.SKU = row.Sku
+
+			lines = append(lines, line)
+		}
+	case "category":
+		rows, err := h.repo.FindMarginByCategory(r.Context(), repository.FindMarginByCategoryParams{
+			FromDate: from,
+			ToDate:   to,
+		})
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		for _, row := range rows {
+			line := marginResponse(row.Category, row.Units, row.Revenue, row.Cogs)
+			if row.CategoryID.Valid {
+				categoryID := uint64(row.CategoryID.Int64)
+				line.ID = &categoryID
+			}
+
+			lines = append(lines, line)
+		}
+	case "store":
+		rows, err := h.repo.FindMarginByStore(r.Context(), repository.FindMarginByStoreParams{
+			FromDate: from,
+			ToDate:   to,
+		})
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		for _, row := range rows {
+			line := marginResponse(row.Store, row.Units, row.Revenue, row.Cogs)
+			if row.StoreID.Valid {
+				storeID := uint64(row.StoreID.Int64)
+				line.ID = &storeID
+			}
+
+			lines = append(lines, line)
+		}
+	case "period":
+		layout, err := reportPeriod(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		rows, err := h.repo.FindMarginByPeriod(r.Context(), repository.FindMarginByPeriodParams{
+			Format:   layout,
+			FromDate: from,
+			ToDate:   to,
+		})
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		for _, row := range rows {
+			lines = append(lines, marginResponse(row.Period, row.Units, row.Revenue, row.Cogs))
+		}
+	default:
+		http.Error(w, "group should be product, category, store or period", http.StatusBadRequest)
+		return
+	}
+
+	var units int64
+	var revenue, cogs float64
+
+	for _, line := range lines {
+		units += line.Units
+		revenue += line.Revenue
+		cogs += line.Cogs
+	}
+
+	if format != "" {
+		var rows [][]interface{}
+		for _, line := range lines {
+			rows = append(rows, []interface{}{line.SKU, line.Name, line.Units, helper.Money(line.Revenue), helper.Money(line.Cogs), helper.Money(line.Margin), line.MarginPercent})
+		}
+
+		writeReport(w, format, "margin-by-"+group, []string{"SKU", "Name", "Units", "Revenue", "Cost of sales", "Margin", "Margin %"}, rows)
+		return
+	}
+
+	response := map[string]interface{}{
+		"group":  group,
+		"from":   from.Format(reportDateLayout),
+		"to":     to.AddDate(0, 0, -1).Format(reportDateLayout),
+		"totals": marginResponse("", units, revenue, cogs),
+		"data":   lines,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,28 @@
+package dto
+
+type AddressRequest struct {
+	Label      string `json:"label" validate:"omitempty,max=50"`
+	Recipient  string `json:"recipient" validate:"required,max=100"`
+	Phone      string `json:"phone" validate:"omitempty,e164"`
+	Line1      string `json:"line1" validate:"required,max=255"`
+	Line2      string `json:"line2" validate:"omitempty,max=255"`
+	City       string `json:"city" validate:"required,max=100"`
+	Region     string `json:"region" validate:"omitempty,max=100"`
+	PostalCode string `json:"postal_code" validate:"omitempty,max=20"`
+	Country    string `json:"country" validate:"required,len=2,alpha"`
+	Default    bool   `json:"default"`
+}
+
+type AddressResponse struct {
+	ID         uint64  `json:"id"`
+	Label      *string `json:"label"`
+	Recipient  string  `json:"recipient"`
+	Phone      *string `json:"phone"`
+	Line1      string  `json:"line1"`
+	Line2      *string `json:"line2"`
+	City       string  `json:"city"`
+	Region     *string `json:"region"`
+	PostalCode *string `json:"postal_code"`
+	Country    string  `json:"country"`
+	Default    bool    `json:"default"`
+}
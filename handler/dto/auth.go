@@ -12,12 +12,88 @@ type RegisterResponse struct {
 }
 
 type LoginRequest struct {
-	Email    string `json:"email" validate:"required"`
-	Password string `json:"password" validate:"required"`
+	Email    string  `json:"email" validate:"required"`
+	Password string  `json:"password" validate:"required"`
+	StoreID  *uint64 `json:"store_id"`
 }
 
 type LoginResponse struct {
-	Token string `json:"token"`
+	Token        string `json:"token"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type EmailRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,gte=8"`
+}
+
+type PhoneRequest struct {
+	Phone string `json:"phone" validate:"required,e164"`
+}
+
+type PhoneCodeRequest struct {
+	Phone string `json:"phone" validate:"required,e164"`
+	Code  string `json:"code" validate:"required,max=10"`
+}
+
+type PhoneLoginRequest struct {
+	Phone   string  `json:"phone" validate:"required,e164"`
+	Code    string  `json:"code" validate:"required,max=10"`
+	StoreID *uint64 `json:"store_id"`
+}
+
+type PhoneRegisterRequest struct {
+	Firstname string `json:"firstname" validate:"required,max=50"`
+	Lastname  string `json:"lastname" validate:"required,max=50"`
+	Phone     string `json:"phone" validate:"required,e164"`
+	Code      string `json:"code" validate:"required,max=10"`
+}
+
+type MFAChallengeResponse struct {
+	MFARequired bool   `json:"mfa_required"`
+	MFAToken    string `json:"mfa_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+type MFAVerifyRequest struct {
+	MFAToken     string  `json:"mfa_token" validate:"required"`
+	Code         string  `json:"code" validate:"required_without=RecoveryCode"`
+	RecoveryCode string  `json:"recovery_code"`
+	StoreID      *uint64 `json:"store_id"`
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type MFAReauthRequest struct {
+	Password     string `json:"password"`
+	Code         string `json:"code" validate:"required_without=RecoveryCode"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+type MFAEnrollResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+	QR     string `json:"qr"`
+}
+
+type MFAStatusResponse struct {
+	Enabled           bool  `json:"enabled"`
+	RecoveryCodesLeft int64 `json:"recovery_codes_left"`
+}
+
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
 }
 
 type RoleResponse struct {
@@ -26,6 +102,7 @@ type RoleResponse struct {
 }
 
 type ProfileRespose struct {
-	User  UserResponse  `json:"user"`
-	Store StoreResponse `json:"store"`
+	User   UserResponse        `json:"user"`
+	Store  *StoreResponse      `json:"store"`
+	Stores []UserStoreResponse `json:"stores"`
 }
@@ -0,0 +1,34 @@
+package dto
+
+type CreateBarcodeRequest struct {
+	Code      string `json:"code" validate:"required,max=64"`
+	Symbology string `json:"symbology" validate:"omitempty,oneof=ean13 upca code128"`
+	Primary   bool   `json:"primary"`
+}
+
+type BarcodeResponse struct {
+	ID        uint64 `json:"id"`
+	ProductID uint64 `json:"product_id"`
+	Code      string `json:"code"`
+	Symbology string `json:"symbology"`
+	Primary   bool   `json:"primary"`
+}
+
+type BarcodeProductResponse struct {
+	ID        uint64   `json:"id"`
+	SKU       string   `json:"sku"`
+	Name      string   `json:"name"`
+	Price     *float64 `json:"price"`
+	Status    bool     `json:"status"`
+	Code      string   `json:"code"`
+	Symbology string   `json:"symbology"`
+}
+
+type LabelItem struct {
+	ProductID uint64 `json:"product_id" validate:"required"`
+	Copies    int32  `json:"copies" validate:"required,gte=1,lte=500"`
+}
+
+type LabelsRequest struct {
+	Items []LabelItem `json:"items" validate:"required,min=1,max=200,dive"`
+}
@@ -0,0 +1,22 @@
+package dto
+
+type ValuationResponse struct {
+	StoreID   uint64  `json:"store_id"`
+	Store     string  `json:"store"`
+	ProductID uint64  `json:"product_id"`
+	SKU       string  `json:"sku"`
+	Name      string  `json:"name"`
+	Quantity  int64   `json:"quantity"`
+	Value     float64 `json:"value"`
+}
+
+type MarginResponse struct {
+	ID            *uint64 `json:"id,omitempty"`
+	SKU           string  `json:"sku,omitempty"`
+	Name          string  `json:"name"`
+	Units         int64   `json:"units"`
+	Revenue       float64 `json:"revenue"`
+	Cogs          float64 `json:"cogs"`
+	Margin        float64 `json:"margin"`
+	MarginPercent float64 `json:"margin_percent"`
+}
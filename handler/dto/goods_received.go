@@ -0,0 +1,41 @@
+package dto
+
+type GoodsReceivedLine struct {
+	SKU          string  `json:"sku"`
+	Quantity     int32   `json:"quantity"`
+	OrderPrice   float64 `json:"order_price"`
+	SellingPrice float64 `json:"selling_price"`
+}
+
+type CreateGoodsReceivedRequest struct {
+	Supplier      string              `json:"supplier" validate:"required"`
+	SupplierID    uint64              `json:"supplier_id"`
+	InvoiceNumber string              `json:"invoice_number" validate:"required"`
+	StoreID       uint64              `json:"store_id" validate:"required"`
+	Date          string              `json:"date"`
+	Lines         []GoodsReceivedLine `json:"lines" validate:"required,min=1"`
+}
+
+type GoodsReceivedLineResponse struct {
+	PurchaseID   uint64  `json:"purchase_id"`
+	ProductID    uint64  `json:"product_id"`
+	SKU          string  `json:"sku"`
+	Name         string  `json:"name"`
+	Quantity     int32   `json:"quantity"`
+	OrderPrice   float64 `json:"order_price"`
+	SellingPrice float64 `json:"selling_price"`
+}
+
+type GoodsReceivedResponse struct {
+	ID              uint64                      `json:"id"`
+	Supplier        string                      `json:"supplier"`
+	SupplierID      *uint64                     `json:"supplier_id"`
+	PurchaseOrderID *uint64                     `json:"purchase_order_id"`
+	InvoiceNumber   string                      `json:"invoice_number"`
+	Store           StoreResponse               `json:"store"`
+	Date            string                      `json:"date"`
+	LinesCount      int32                       `json:"lines_count"`
+	TotalQuantity   int32                       `json:"total_quantity"`
+	TotalCost       float64                     `json:"total_cost"`
+	Lines           []GoodsReceivedLineResponse `json:"lines,omitempty"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type ImportReport struct {
+	DryRun bool             `json:"dry_run"`
+	Total  int              `json:"total"`
+	Valid  int              `json:"valid"`
+	Errors []ImportRowError `json:"errors"`
+}
+
+type ImportJobResponse struct {
+	ID         uint64           `json:"id"`
+	Kind       string           `json:"kind"`
+	Status     string           `json:"status"`
+	Total      int32            `json:"total"`
+	Processed  int32            `json:"processed"`
+	Inserted   int32            `json:"inserted"`
+	Updated    int32            `json:"updated"`
+	Errors     []ImportRowError `json:"errors"`
+	CreatedAt  string           `json:"created_at"`
+	FinishedAt *string          `json:"finished_at"`
+}
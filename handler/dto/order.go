@@ -1,15 +1,18 @@
 package dto
 
 type CreateStoreOrderRequest struct {
-	StoreID uint64      `json:"store_id" validate:"required"`
-	Items   []OrderItem `json:"items" validate:"required"`
-	Date    string      `json:"date"`
+	StoreID  uint64      `json:"store_id" validate:"required"`
+	Items    []OrderItem `json:"items" validate:"required"`
+	Tender   string      `json:"tender" validate:"omitempty,oneof=cash card mobile_money"`
+	Tendered float64     `json:"tendered" validate:"gte=0"`
+	Date     string      `json:"date"`
 }
 
 type CreateOnlineOrderRequest struct {
-	StoreID uint64      `json:"store_id" validate:"required"`
-	Items   []OrderItem `json:"items" validate:"required"`
-	Date    string      `json:"date"`
+	StoreID   uint64      `json:"store_id" validate:"required"`
+	AddressID *uint64     `json:"address_id"`
+	Items     []OrderItem `json:"items" validate:"required"`
+	Date      string      `json:"date"`
 }
 
 type OrderItem struct {
@@ -40,9 +43,13 @@ type OnlineOrderResponse struct {
 }
 
 type StoreOrderDetails struct {
-	Store   StoreResponse `json:"store"`
-	Cashier UserResponse  `json:"cashier"`
+	Store    StoreResponse `json:"store"`
+	Cashier  UserResponse  `json:"cashier"`
+	ShiftID  *uint64       `json:"shift_id"`
+	Tender   string        `json:"tender"`
+	Tendered *float64      `json:"tendered"`
 }
 type OnlineOrderDetails struct {
-	Customer UserResponse `json:"customer"`
+	Customer UserResponse     `json:"customer"`
+	Address  *AddressResponse `json:"address"`
 }
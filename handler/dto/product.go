@@ -18,6 +18,7 @@ type ProductResponse struct {
 	CategoryID  int64           `json:"category_id"`
 	Status      bool            `json:"status"`
 	Visibility  bool            `json:"visibility"`
+	Price       *float64        `json:"price"`
 	Images      []ImageResponse `json:"images"`
 }
 
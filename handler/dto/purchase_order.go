@@ -0,0 +1,72 @@
+package dto
+
+type PurchaseOrderItemRequest struct {
+	ProductID    uint64  `json:"product_id" validate:"required"`
+	Quantity     int32   `json:"quantity" validate:"required,gte=1"`
+	OrderPrice   float64 `json:"order_price" validate:"required"`
+	SellingPrice float64 `json:"selling_price" validate:"required"`
+}
+
+type CreatePurchaseOrderRequest struct {
+	SupplierID uint64                     `json:"supplier_id" validate:"required"`
+	StoreID    uint64                     `json:"store_id" validate:"required"`
+	ExpectedAt string                     `json:"expected_at"`
+	Notes      string                     `json:"notes"`
+	Items      []PurchaseOrderItemRequest `json:"items" validate:"required,min=1,dive"`
+}
+
+type ReceivePurchaseOrderLine struct {
+	ItemID       uint64  `json:"item_id" validate:"required"`
+	Quantity     int32   `json:"quantity" validate:"required,gte=1"`
+	SellingPrice float64 `json:"selling_price" validate:"gte=0"`
+}
+
+// ReceivePurchaseOrderRequest receives the listed lines, or everything still
+// outstanding when no lines are given
+type ReceivePurchaseOrderRequest struct {
+	InvoiceNumber string                     `json:"invoice_number" validate:"required"`
+	Date          string                     `json:"date"`
+	Lines         []ReceivePurchaseOrderLine `json:"lines" validate:"dive"`
+}
+
+type PurchaseOrderItemResponse struct {
+	ID           uint64  `json:"id"`
+	ProductID    uint64  `json:"product_id"`
+	SKU          string  `json:"sku"`
+	Name         string  `json:"name"`
+	Quantity     int32   `json:"quantity"`
+	Received     int32   `json:"received"`
+	Outstanding  int32   `json:"outstanding"`
+	OrderPrice   float64 `json:"order_price"`
+	SellingPrice float64 `json:"selling_price"`
+}
+
+type PurchaseOrderResponse struct {
+	ID         uint64                      `json:"id"`
+	Number     string                      `json:"number"`
+	Status     string                      `json:"status"`
+	Supplier   SupplierResponse            `json:"supplier"`
+	Store      StoreResponse               `json:"store"`
+	ExpectedAt *string                     `json:"expected_at"`
+	Notes      string                      `json:"notes"`
+	Total      float64                     `json:"total"`
+	Items      []PurchaseOrderItemResponse `json:"items"`
+	CreatedAt  string                      `json:"created_at"`
+}
+
+type OutstandingPurchaseOrderItem struct {
+	PurchaseOrderID  uint64  `json:"purchase_order_id"`
+	Number           string  `json:"number"`
+	Status           string  `json:"status"`
+	ExpectedAt       *string `json:"expected_at"`
+	Overdue          bool    `json:"overdue"`
+	SupplierID       uint64  `json:"supplier_id"`
+	Supplier         string  `json:"supplier"`
+	ProductID        uint64  `json:"product_id"`
+	SKU              string  `json:"sku"`
+	Name             string  `json:"name"`
+	Ordered          int32   `json:"ordered"`
+	Received         int32   `json:"received"`
+	Outstanding      int32   `json:"outstanding"`
+	OutstandingValue float64 `json:"outstanding_value"`
+}
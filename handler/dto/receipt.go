@@ -0,0 +1,24 @@
+package dto
+
+type ReceiptTemplateRequest struct {
+	Header     string  `json:"header"`
+	Footer     string  `json:"footer"`
+	TaxLabel   string  `json:"tax_label"`
+	TaxRate    float64 `json:"tax_rate" validate:"gte=0,lte=100"`
+	PaperWidth int32   `json:"paper_width" validate:"omitempty,oneof=58 80"`
+	ShowQR     *bool   `json:"show_qr"`
+}
+
+type ReceiptTemplateResponse struct {
+	StoreID    uint64  `json:"store_id"`
+	Header     string  `json:"header"`
+	Footer     string  `json:"footer"`
+	TaxLabel   string  `json:"tax_label"`
+	TaxRate    float64 `json:"tax_rate"`
+	PaperWidth int32   `json:"paper_width"`
+	ShowQR     bool    `json:"show_qr"`
+}
+
+type EmailReceiptRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
@@ -0,0 +1,30 @@
+package dto
+
+type CreateReorderPointRequest struct {
+	ProductID       uint64 `json:"product_id" validate:"required"`
+	StoreID         uint64 `json:"store_id"`
+	ReorderPoint    int32  `json:"reorder_point" validate:"gte=0"`
+	ReorderQuantity int32  `json:"reorder_quantity" validate:"required,gte=1"`
+}
+
+type ReorderPointResponse struct {
+	ID              uint64  `json:"id"`
+	ProductID       uint64  `json:"product_id"`
+	SKU             string  `json:"sku"`
+	Product         string  `json:"product"`
+	StoreID         *uint64 `json:"store_id"`
+	Store           string  `json:"store,omitempty"`
+	ReorderPoint    int32   `json:"reorder_point"`
+	ReorderQuantity int32   `json:"reorder_quantity"`
+}
+
+type LowStockResponse struct {
+	ProductID       uint64 `json:"product_id"`
+	SKU             string `json:"sku"`
+	Name            string `json:"name"`
+	StoreID         uint64 `json:"store_id"`
+	Store           string `json:"store"`
+	Quantity        int64  `json:"quantity"`
+	ReorderPoint    int32  `json:"reorder_point"`
+	ReorderQuantity int32  `json:"reorder_quantity"`
+}
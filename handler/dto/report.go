@@ -6,3 +6,23 @@ type Report struct {
 	Products   int64 `json:"products"`
 	Categories int64 `json:"categories"`
 }
+
+type SalesResponse struct {
+	ID            *uint64 `json:"id,omitempty"`
+	SKU           string  `json:"sku,omitempty"`
+	Name          string  `json:"name"`
+	Orders        int64   `json:"orders"`
+	Units         int64   `json:"units"`
+	Revenue       float64 `json:"revenue"`
+	AverageBasket float64 `json:"average_basket"`
+}
+
+type SalesSummaryResponse struct {
+	From               string  `json:"from"`
+	To                 string  `json:"to"`
+	Orders             int64   `json:"orders"`
+	Units              int64   `json:"units"`
+	Revenue            float64 `json:"revenue"`
+	AverageBasketValue float64 `json:"average_basket_value"`
+	AverageBasketUnits float64 `json:"average_basket_units"`
+}
@@ -0,0 +1,22 @@
+package dto
+
+type PermissionResponse struct {
+	ID          uint64  `json:"id"`
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+}
+
+type RoleRequest struct {
+	Name        string   `json:"name" validate:"required,max=50"`
+	Permissions []string `json:"permissions"`
+}
+
+type RoleDetailResponse struct {
+	ID          uint64   `json:"id"`
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+type AssignUserRoleRequest struct {
+	RoleID uint64 `json:"role_id" validate:"required"`
+}
@@ -0,0 +1,24 @@
+package dto
+
+type SessionResponse struct {
+	ID         string  `json:"id"`
+	UserAgent  *string `json:"user_agent"`
+	StartedAt  *string `json:"started_at"`
+	LastUsedAt *string `json:"last_used_at"`
+	ExpiresAt  string  `json:"expires_at"`
+}
+
+type LockoutResponse struct {
+	Failures    int64   `json:"failures"`
+	Locked      bool    `json:"locked"`
+	LockedUntil *string `json:"locked_until"`
+}
+
+type LoginAuditResponse struct {
+	ID        uint64  `json:"id"`
+	ActorID   *uint64 `json:"actor_id"`
+	Event     string  `json:"event"`
+	IP        *string `json:"ip"`
+	UserAgent *string `json:"user_agent"`
+	CreatedAt *string `json:"created_at"`
+}
@@ -0,0 +1,52 @@
+package dto
+
+type OpenShiftRequest struct {
+	StoreID      uint64  `json:"store_id" validate:"required"`
+	OpeningFloat float64 `json:"opening_float" validate:"gte=0"`
+}
+
+type ShiftCashRequest struct {
+	Type   string  `json:"type" validate:"required,oneof=cash_in cash_out"`
+	Amount float64 `json:"amount" validate:"required,gt=0"`
+	Reason string  `json:"reason"`
+}
+
+type CloseShiftRequest struct {
+	CountedCash *float64 `json:"counted_cash" validate:"required,gte=0"`
+	Notes       string   `json:"notes"`
+}
+
+type ShiftCashMovementResponse struct {
+	ID        uint64  `json:"id"`
+	Type      string  `json:"type"`
+	Amount    float64 `json:"amount"`
+	Reason    string  `json:"reason"`
+	CreatedAt string  `json:"created_at"`
+}
+
+type ShiftTenderResponse struct {
+	Tender string  `json:"tender"`
+	Orders int64   `json:"orders"`
+	Total  float64 `json:"total"`
+}
+
+// ShiftResponse is the cash-up of a shift. ExpectedCash is worked out live
+// while the shift is open and frozen when it is closed.
+type ShiftResponse struct {
+	ID           uint64                      `json:"id"`
+	Status       string                      `json:"status"`
+	Store        StoreResponse               `json:"store"`
+	Cashier      UserResponse                `json:"cashier"`
+	OpeningFloat float64                     `json:"opening_float"`
+	Sales        []ShiftTenderResponse       `json:"sales"`
+	CashSales    float64                     `json:"cash_sales"`
+	CashIn       float64                     `json:"cash_in"`
+	CashOut      float64                     `json:"cash_out"`
+	ExpectedCash float64                     `json:"expected_cash"`
+	CountedCash  *float64                    `json:"counted_cash"`
+	Variance     *float64                    `json:"variance"`
+	Notes        string                      `json:"notes"`
+	Movements    []ShiftCashMovementResponse `json:"movements"`
+	OpenedAt     string                      `json:"opened_at"`
+	ClosedAt     *string                     `json:"closed_at"`
+}
@@ -11,3 +11,9 @@ type StoreResponse struct {
 	Name   string `json:"name"`
 	Status bool   `json:"status"`
 }
+
+type UserStoreResponse struct {
+	ID   uint64 `json:"id"`
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
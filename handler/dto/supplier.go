@@ -0,0 +1,33 @@
+package dto
+
+type CreateSupplierRequest struct {
+	Name         string `json:"name" validate:"required"`
+	ContactName  string `json:"contact_name"`
+	Email        string `json:"email" validate:"omitempty,email"`
+	Phone        string `json:"phone"`
+	Address      string `json:"address"`
+	PaymentTerms string `json:"payment_terms"`
+	LeadTimeDays int32  `json:"lead_time_days" validate:"gte=0"`
+	Status       bool   `json:"status"`
+}
+
+type SupplierResponse struct {
+	ID           uint64 `json:"id"`
+	Slug         string `json:"slug"`
+	Name         string `json:"name"`
+	ContactName  string `json:"contact_name"`
+	Email        string `json:"email"`
+	Phone        string `json:"phone"`
+	Address      string `json:"address"`
+	PaymentTerms string `json:"payment_terms"`
+	LeadTimeDays int32  `json:"lead_time_days"`
+	Status       bool   `json:"status"`
+}
+
+type SupplierSpendResponse struct {
+	SupplierID *uint64 `json:"supplier_id"`
+	Supplier   string  `json:"supplier"`
+	Deliveries int64   `json:"deliveries"`
+	Units      int64   `json:"units"`
+	Spend      float64 `json:"spend"`
+}
@@ -0,0 +1,57 @@
+package dto
+
+type SyncOrderRequest struct {
+	ClientID  string      `json:"client_id" validate:"required,uuid"`
+	Number    string      `json:"number" validate:"max=50"`
+	CreatedAt string      `json:"created_at" validate:"required"`
+	Tender    string      `json:"tender" validate:"omitempty,oneof=cash card mobile_money"`
+	Tendered  float64     `json:"tendered" validate:"gte=0"`
+	Items     []OrderItem `json:"items" validate:"required,min=1,dive"`
+}
+
+type SyncOrdersRequest struct {
+	StoreID uint64             `json:"store_id" validate:"required"`
+	Orders  []SyncOrderRequest `json:"orders" validate:"required,min=1,max=100,dive"`
+}
+
+// SyncConflict is something that did not match the server when an offline
+// order was applied. The order is still recorded, since the sale took place.
+type SyncConflict struct {
+	Type      string `json:"type"`
+	SKU       string `json:"sku,omitempty"`
+	Requested int32  `json:"requested,omitempty"`
+	Available int32  `json:"available,omitempty"`
+	Message   string `json:"message"`
+}
+
+type SyncOrderResult struct {
+	ClientID     string         `json:"client_id"`
+	Status       string         `json:"status"`
+	OrderID      uint64         `json:"order_id,omitempty"`
+	Number       string         `json:"number,omitempty"`
+	ClientNumber string         `json:"client_number,omitempty"`
+	Conflicts    []SyncConflict `json:"conflicts,omitempty"`
+	Error        string         `json:"error,omitempty"`
+}
+
+type SyncProductResponse struct {
+	ID         uint64   `json:"id"`
+	SKU        string   `json:"sku"`
+	Name       string   `json:"name"`
+	CategoryID *uint64  `json:"category_id"`
+	Price      *float64 `json:"price"`
+	Status     bool     `json:"status"`
+	UpdatedAt  string   `json:"updated_at"`
+}
+
+type SyncStockResponse struct {
+	ProductID uint64 `json:"product_id"`
+	SKU       string `json:"sku"`
+	Quantity  int64  `json:"quantity"`
+}
+
+type SyncCatalogueResponse struct {
+	Cursor   string                `json:"cursor"`
+	Products []SyncProductResponse `json:"products"`
+	Stock    []SyncStockResponse   `json:"stock"`
+}
@@ -11,4 +11,55 @@ type UserResponse struct {
 type AssignStoreUserRequest struct {
 	Email   string `json:"email" validate:"required"`
 	StoreID uint64 `json:"store_id" validate:"required"`
+	Role    string `json:"role"`
+}
+
+type CreateUserRequest struct {
+	Firstname string   `json:"firstname" validate:"required,max=255"`
+	Lastname  string   `json:"lastname" validate:"required,max=255"`
+	Email     string   `json:"email" validate:"required,email"`
+	Phone     *string  `json:"phone" validate:"omitempty,e164"`
+	Password  string   `json:"password" validate:"required,gte=8"`
+	Roles     []uint64 `json:"roles"`
+}
+
+type UpdateUserRequest struct {
+	Firstname string  `json:"firstname" validate:"required,max=255"`
+	Lastname  string  `json:"lastname" validate:"required,max=255"`
+	Phone     *string `json:"phone" validate:"omitempty,e164"`
+}
+
+type AdminUserResponse struct {
+	ID              uint64         `json:"id"`
+	Firstname       string         `json:"firstname"`
+	Lastname        string         `json:"lastname"`
+	Email           *string        `json:"email"`
+	Phone           *string        `json:"phone"`
+	EmailVerifiedAt *string        `json:"email_verified_at"`
+	PhoneVerifiedAt *string        `json:"phone_verified_at"`
+	DeactivatedAt   *string        `json:"deactivated_at"`
+	DeletedAt       *string        `json:"deleted_at"`
+	Roles           []RoleResponse `json:"roles"`
+}
+
+type CustomerProfileResponse struct {
+	ID            uint64  `json:"id"`
+	Firstname     string  `json:"firstname"`
+	Lastname      string  `json:"lastname"`
+	Email         *string `json:"email"`
+	Phone         *string `json:"phone"`
+	EmailVerified bool    `json:"email_verified"`
+	PhoneVerified bool    `json:"phone_verified"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	Password        string `json:"password" validate:"required,gte=8"`
+}
+
+type DeleteAccountRequest struct {
+	Password     string `json:"password"`
+	PhoneCode    string `json:"phone_code"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
 }
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"api/cmd/helper"
+)
+
+// exportBatchSize is the number of rows read from the database at a time
+// while a list is exported, so large exports never sit in memory at once
+const exportBatchSize = 500
+
+// exportFormat returns the file format asked for with the format query param,
+// or "" when the response should be JSON
+func exportFormat(r *http.Request) (string, error) {
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "json":
+		return "", nil
+	case helper.FormatCSV, helper.FormatXLSX, helper.FormatPDF:
+		return format, nil
+	default:
+		return "", fmt.Errorf("format should be csv, xlsx or pdf")
+	}
+}
+
+// exportRange reads the optional from and to query params of a list export.
+// Unlike reportRange, a missing bound leaves the range open.
+func exportRange(r *http.Request) (time.Time, time.Time, error) {
+	from := time.Unix(0, 0).UTC()
+	to := time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	if r.URL.Query().Get("from") == "" && r.URL.Query().Get("to") == "" {
+		return from, to, nil
+	}
+
+	reportFrom, reportTo, err := reportRange(r)
+	if err != nil {
+		return from, to, err
+	}
+
+	if r.URL.Query().Get("from") != "" {
+		from = reportFrom
+	}
+	if r.URL.Query().Get("to") != "" {
+		to = reportTo
+	}
+
+	if !from.Before(to) {
+		return from, to, fmt.Errorf("from should be before to")
+	}
+
+	return from, to, nil
+}
+
+// writeExport streams a table as a file download named after name and today's
+// date. rows is called once the header is written and should write every row.
+func writeExport(w http.ResponseWriter, format, name string, columns []string, rows func(table helper.TableWriter) error) {
+	table, err := helper.NewTableWriter(w, format)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", helper.TableContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s-%s.%s\"", name, time.Now().Format("20060102"), format))
+
+	if err := table.WriteHeader(columns); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	if err := rows(table); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	if err := table.Close(); err != nil {
+		fmt.Println(err)
+	}
+}
+
+// writeReport exports the rows of a report, which are already in memory
+func writeReport(w http.ResponseWriter, format, name string, columns []string, rows [][]interface{}) {
+	writeExport(w, format, name, columns, func(table helper.TableWriter) error {
+		for _, row := range rows {
+			if err := table.WriteRow(row); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// nullMoney leaves the cell empty for a missing amount
+func nullMoney(value sql.NullFloat64) interface{} {
+	if !value.Valid {
+		return nil
+	}
+
+	return helper.Money(value.Float64)
+}
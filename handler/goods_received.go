@@ -0,0 +1,594 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"api/cmd/costing"
+	"api/cmd/middleware"
+	"api/handler/dto"
+	"api/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator"
+)
+
+type goodsReceivedHandler struct {
+	db    *sql.DB
+	repo  *repository.Queries
+	costs *costing.Engine
+}
+
+func NewGoodsReceivedHandler(db *sql.DB, repo *repository.Queries, costs *costing.Engine) *goodsReceivedHandler {
+	return &goodsReceivedHandler{db: db, repo: repo, costs: costs}
+}
+
+// readGoodsReceivedForm reads a goods-received note sent either as JSON or as a
+// multipart form with the header fields and a CSV or XLSX "file" of lines.
+// Line numbers of the returned rows are the JSON array position or the
+// spreadsheet row.
+func readGoodsReceivedForm(r *http.Request) (dto.CreateGoodsReceivedRequest, []int, error) {
+	var form dto.CreateGoodsReceivedRequest
+
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+			return form, nil, fmt.Errorf("Invalid request body")
+		}
+
+		rows := make([]int, len(form.Lines))
+		for i := range form.Lines {
+			rows[i] = i + 1
+		}
+
+		return form, rows, nil
+	}
+
+	table, err := readImportTable(r)
+	if err != nil {
+		return form, nil, err
+	}
+
+	form.Supplier = r.FormValue("supplier")
+	form.InvoiceNumber = r.FormValue("invoice_number")
+	form.Date = r.FormValue("date")
+
+	storeID, err := strconv.ParseUint(r.FormValue("store_id"), 10, 64)
+	if err == nil {
+		form.StoreID = storeID
+	}
+
+	supplierID, err := strconv.ParseUint(r.FormValue("supplier_id"), 10, 64)
+	if err == nil {
+		form.SupplierID = supplierID
+	}
+
+	columns, err := tableColumns(table[0], "sku", "quantity", "order_price", "selling_price")
+	if err != nil {
+		return form, nil, err
+	}
+
+	var rows []int
+
+	for i, record := range table[1:] {
+		line := dto.GoodsReceivedLine{SKU: tableCell(record, columns, "sku")}
+
+		// Unparsable numbers are left at zero and reported by validateGoodsReceivedLines
+		quantity, _ := strconv.ParseInt(tableCell(record, columns, "quantity"), 10, 32)
+		line.Quantity = int32(quantity)
+		line.OrderPrice, _ = strconv.ParseFloat(tableCell(record, columns, "order_price"), 64)
+		line.SellingPrice, _ = strconv.ParseFloat(tableCell(record, columns, "selling_price"), 64)
+
+		if line.SKU == "" && quantity == 0 && line.OrderPrice == 0 && line.SellingPrice == 0 {
+			// Skip blank rows left at the end of spreadsheets
+			continue
+		}
+
+		form.Lines = append(form.Lines, line)
+		rows = append(rows, i+2)
+	}
+
+	return form, rows, nil
+}
+
+func validateGoodsReceivedLines(lines []dto.GoodsReceivedLine, rows []int) []dto.ImportRowError {
+	errors := []dto.ImportRowError{}
+
+	for i, line := range lines {
+		if line.SKU == "" {
+			errors = append(errors, dto.ImportRowError{Row: rows[i], Field: "sku", Message: "sku is a required field"})
+		}
+
+		if line.Quantity < 1 {
+			errors = append(errors, dto.ImportRowError{Row: rows[i], Field: "quantity", Message: "quantity should at least be 1"})
+		}
+
+		if line.OrderPrice <= 0 {
+			errors = append(errors, dto.ImportRowError{Row: rows[i], Field: "order_price", Message: "order_price should be greater than 0"})
+		}
+
+		if line.SellingPrice <= 0 {
+			errors = append(errors, dto.ImportRowError{Row: rows[i], Field: "selling_price", Message: "selling_price should be greater than 0"})
+		}
+	}
+
+	return errors
+}
+
+func writeGoodsReceivedErrors(w http.ResponseWriter, lines int, errors []dto.ImportRowError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	json.NewEncoder(w).Encode(dto.ImportReport{
+		Total:  lines,
+		Valid:  0,
+		Errors: errors,
+	})
+}
+
+type receivedLine struct {
+	productID    uint64
+	quantity     int32
+	orderPrice   float64
+	sellingPrice float64
+}
+
+// insertGoodsReceived records a goods-received note with its totals and one
+// purchase per line, it should run inside a transaction
+func insertGoodsReceived(ctx context.Context, repo *repository.Queries, costs *costing.Engine, note repository.InsertGoodsReceivedNoteParams, lines []receivedLine) (int64, error) {
+	var totalCost float64
+
+	note.LinesCount = int32(len(lines))
+	note.TotalQuantity = 0
+
+	for _, line := range lines {
+		note.TotalQuantity += line.quantity
+		totalCost += float64(line.quantity) * line.orderPrice
+	}
+
+	note.TotalCost = math.Round(totalCost*100) / 100
+
+	noteID, err := repo.InsertGoodsReceivedNote(ctx, note)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, line := range lines {
+		purchaseID, err := repo.InsertPurchase(ctx, repository.InsertPurchaseParams{
+			UserID:              note.UserID,
+			ProductID:           line.productID,
+			Quantity:            line.quantity,
+			OrderPrice:          line.orderPrice,
+			SellingPrice:        line.sellingPrice,
+			Date:                note.Date,
+			StoreID:             note.StoreID,
+			GoodsReceivedNoteID: sql.NullInt64{Int64: noteID, Valid: true},
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		err = costs.Receive(ctx, repo, uint64(purchaseID), line.productID, note.StoreID, line.quantity, line.orderPrice)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return noteID, nil
+}
+
+// removeGoodsReceived takes a purchase of a goods-received note out of the
+// note's totals, and out of what its purchase order has received, it should
+// run inside a transaction
+func removeGoodsReceived(ctx context.Context, repo *repository.Queries, purchase repository.Purchase) error {
+	noteID := uint64(purchase.GoodsReceivedNoteID.Int64)
+
+	err := repo.RemoveGoodsReceivedLine(ctx, repository.RemoveGoodsReceivedLineParams{
+		TotalQuantity: purchase.Quantity,
+		TotalCost:     float64(purchase.Quantity) * purchase.OrderPrice,
+		ID:            noteID,
+	})
+	if err != nil {
+		return err
+	}
+
+	note, err := repo.FindGoodsReceivedNote(ctx, noteID)
+	if err != nil {
+		return err
+	}
+
+	if !note.PurchaseOrderID.Valid {
+		return nil
+	}
+
+	po, err := repo.FindPurchaseOrder(ctx, uint64(note.PurchaseOrderID.Int64))
+	if err != nil {
+		return err
+	}
+
+	items, err := repo.FindPurchaseOrderItems(ctx, po.ID)
+	if err != nil {
+		return err
+	}
+
+	// Lines are not linked to items, so the quantity is taken back from the
+	// items of the product, the last ones first
+	quantity := purchase.Quantity
+	for i := len(items) - 1; i >= 0 && quantity > 0; i-- {
+		if items[i].ProductID != purchase.ProductID || items[i].Received == 0 {
+			continue
+		}
+
+		taken := min(quantity, items[i].Received)
+
+		err := repo.ReceivePurchaseOrderItem(ctx, repository.ReceivePurchaseOrderItemParams{
+			Received: -taken,
+			ID:       items[i].ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		items[i].Received -= taken
+		quantity -= taken
+	}
+
+	// A closed purchase order stays closed
+	if po.Status == repository.PurchaseOrdersStatusClosed {
+		return nil
+	}
+
+	var received, outstanding bool
+	for _, item := range items {
+		received = received || item.Received > 0
+		outstanding = outstanding || item.Received < item.Quantity
+	}
+
+	status := repository.PurchaseOrdersStatusReceived
+	if !received {
+		status = repository.PurchaseOrdersStatusSent
+	} else if outstanding {
+		status = repository.PurchaseOrdersStatusPartiallyReceived
+	}
+
+	return repo.UpdatePurchaseOrderStatus(ctx, repository.UpdatePurchaseOrderStatusParams{
+		Status: status,
+		ID:     po.ID,
+	})
+}
+
+// Create records a goods-received note and one purchase per line in a single
+// transaction. Nothing is recorded if any line is invalid.
+func (h *goodsReceivedHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.UserID(ctx)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	form, rows, err := readGoodsReceivedForm(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if form.SupplierID != 0 {
+		// A known supplier takes the place of the supplier name
+		supplier, err := h.repo.FindSupplier(ctx, form.SupplierID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				http.Error(w, "Supplier not found", http.StatusNotFound)
+			} else {
+				fmt.Println(err)
+				http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		form.Supplier = supplier.Name
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(form); err != nil {
+		http.Error(w, validationMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	date := time.Now().UTC()
+	if form.Date != "" {
+		date, err = time.Parse(time.RFC3339, form.Date)
+		if err != nil {
+			http.Error(w, "Error parsing time", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if errors := validateGoodsReceivedLines(form.Lines, rows); len(errors) > 0 {
+		writeGoodsReceivedErrors(w, len(form.Lines), errors)
+		return
+	}
+
+	if !checkStore(w, ctx, form.StoreID) {
+		return
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	repo := h.repo.WithTx(tx)
+
+	store, err := repo.FindStore(ctx, form.StoreID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Store not found", http.StatusNotFound)
+		} else {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	_, err = repo.FindGoodsReceivedNoteByInvoice(ctx, repository.FindGoodsReceivedNoteByInvoiceParams{
+		Supplier:      form.Supplier,
+		InvoiceNumber: form.InvoiceNumber,
+	})
+	if err == nil {
+		http.Error(w, "This invoice has already been received", http.StatusConflict)
+		return
+	} else if err != sql.ErrNoRows {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	products := make(map[string]repository.Product)
+	errors := []dto.ImportRowError{}
+
+	for i, line := range form.Lines {
+		if _, ok := products[line.SKU]; ok {
+			continue
+		}
+
+		p, err := repo.FindProductBySKU(ctx, line.SKU)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				errors = append(errors, dto.ImportRowError{Row: rows[i], Field: "sku", Message: "Product not found"})
+				continue
+			}
+
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		products[line.SKU] = p
+	}
+
+	if len(errors) > 0 {
+		writeGoodsReceivedErrors(w, len(form.Lines), errors)
+		return
+	}
+
+	lines := make([]receivedLine, len(form.Lines))
+	for i, line := range form.Lines {
+		lines[i] = receivedLine{
+			productID:    products[line.SKU].ID,
+			quantity:     line.Quantity,
+			orderPrice:   line.OrderPrice,
+			sellingPrice: line.SellingPrice,
+		}
+	}
+
+	note := repository.InsertGoodsReceivedNoteParams{
+		Supplier:      form.Supplier,
+		InvoiceNumber: form.InvoiceNumber,
+		StoreID:       sql.NullInt64{Int64: int64(store.ID), Valid: true},
+		Date:          date,
+		UserID:        sql.NullInt64{Int64: int64(userID), Valid: true},
+	}
+
+	if form.SupplierID != 0 {
+		note.SupplierID = sql.NullInt64{Int64: int64(form.SupplierID), Valid: true}
+	}
+
+	noteID, err := insertGoodsReceived(ctx, repo, h.costs, note, lines)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to create goods received note", http.StatusInternalServerError)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	response, err := h.response(ctx, uint64(noteID), true)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// response builds the response of a goods-received note, with its lines if asked
+func (h *goodsReceivedHandler) response(ctx context.Context, id uint64, withLines bool) (dto.GoodsReceivedResponse, error) {
+	note, err := h.repo.FindGoodsReceivedNote(ctx, id)
+	if err != nil {
+		return dto.GoodsReceivedResponse{}, err
+	}
+
+	return h.noteResponse(ctx, note, withLines)
+}
+
+func (h *goodsReceivedHandler) noteResponse(ctx context.Context, note repository.GoodsReceivedNote, withLines bool) (dto.GoodsReceivedResponse, error) {
+	response := dto.GoodsReceivedResponse{
+		ID:            note.ID,
+		Supplier:      note.Supplier,
+		InvoiceNumber: note.InvoiceNumber,
+		Date:          note.Date.UTC().Format(time.RFC3339),
+		LinesCount:    note.LinesCount,
+		TotalQuantity: note.TotalQuantity,
+		TotalCost:     note.TotalCost,
+	}
+
+	if note.SupplierID.Valid {
+		supplierID := uint64(note.SupplierID.Int64)
+		response.SupplierID = &supplierID
+	}
+
+	if note.PurchaseOrderID.Valid {
+		purchaseOrderID := uint64(note.PurchaseOrderID.Int64)
+		response.PurchaseOrderID = &purchaseOrderID
+	}
+
+	if note.StoreID.Valid {
+		s, err := h.repo.FindStore(ctx, uint64(note.StoreID.Int64))
+		if err != nil && err != sql.ErrNoRows {
+			return response, err
+		}
+
+		response.Store = dto.StoreResponse{
+			ID:     s.ID,
+			Slug:   s.Slug,
+			Name:   s.Name,
+			Status: s.Status,
+		}
+	}
+
+	if !withLines {
+		return response, nil
+	}
+
+	purchases, err := h.repo.FindPurchasesByGoodsReceivedNote(ctx, sql.NullInt64{Int64: int64(note.ID), Valid: true})
+	if err != nil {
+		return response, err
+	}
+
+	response.Lines = []dto.GoodsReceivedLineResponse{}
+
+	for _, purchase := range purchases {
+		p, err := h.repo.FindProduct(ctx, purchase.ProductID)
+		if err != nil && err != sql.ErrNoRows {
+			return response, err
+		}
+
+		response.Lines = append(response.Lines, dto.GoodsReceivedLineResponse{
+			PurchaseID:   purchase.ID,
+			ProductID:    purchase.ProductID,
+			SKU:          p.Sku,
+			Name:         p.Name,
+			Quantity:     purchase.Quantity,
+			OrderPrice:   purchase.OrderPrice,
+			SellingPrice: purchase.SellingPrice,
+		})
+	}
+
+	return response, nil
+}
+
+// List goods-received notes with pagination
+func (h *goodsReceivedHandler) AdminFindAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil {
+		limit = 20
+	}
+	offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+	if err != nil {
+		offset = 0
+	}
+
+	count, err := h.repo.CountGoodsReceivedNotes(ctx, storeFilter(ctx))
+	if err != nil {
+		http.Error(w, "Failed to count goods received notes", http.StatusInternalServerError)
+		return
+	}
+
+	data, err := h.repo.FindGoodsReceivedNotes(ctx, repository.FindGoodsReceivedNotesParams{
+		Stores: storeFilter(ctx),
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		http.Error(w, "Failed to retrieve goods received notes", http.StatusInternalServerError)
+		return
+	}
+
+	var notes = []dto.GoodsReceivedResponse{}
+
+	for _, note := range data {
+		response, err := h.noteResponse(ctx, note, false)
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		notes = append(notes, response)
+	}
+
+	response := map[string]interface{}{
+		"total":  count,
+		"limit":  limit,
+		"offset": offset,
+		"data":   notes,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// Get a goods-received note with its lines
+func (h *goodsReceivedHandler) AdminFindOne(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid goods received note ID", http.StatusBadRequest)
+		return
+	}
+
+	note, err := h.repo.FindGoodsReceivedNote(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Goods received note not found", http.StatusNotFound)
+		} else {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if !canSeeStore(ctx, note.StoreID) {
+		http.Error(w, "Goods received note not found", http.StatusNotFound)
+		return
+	}
+
+	response, err := h.noteResponse(ctx, note, true)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"api/cmd/helper"
+	"api/cmd/jobs"
+	"api/cmd/storage"
+	"api/repository"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// saveImage validates and stores an uploaded image and returns its images row.
+// An image whose content was uploaded before reuses the existing row, which
+// is touched so the image collector gives it a new grace period before it is
+// used.
+func saveImage(ctx context.Context, repo *repository.Queries, blobs storage.BlobStore, file io.ReadCloser) (uint64, error) {
+	upload, err := helper.ReadImage(file)
+	if err != nil {
+		return 0, err
+	}
+
+	hash := sql.NullString{String: upload.Hash, Valid: true}
+
+	existing, err := repo.FindImageByHash(ctx, hash)
+	if err == nil {
+		existing, err = touchImage(ctx, repo, existing.ID)
+		if err == nil {
+			return existing.ID, nil
+		}
+	}
+
+	// An image collected before it was touched is stored anew
+	if err != sql.ErrNoRows {
+		return 0, err
+	}
+
+	if err := upload.Store(ctx, blobs); err != nil {
+		return 0, err
+	}
+
+	imageID, err := repo.InsertImage(ctx, repository.InsertImageParams{
+		Name: upload.Name,
+		Hash: hash,
+	})
+	if err != nil {
+		// The same image was uploaded at the same time and won. Its row
+		// points at the blob just stored, so the blob is kept.
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+			existing, err := repo.FindImageByHash(ctx, hash)
+			if err != nil {
+				return 0, err
+			}
+
+			return existing.ID, nil
+		}
+
+		upload.Remove(ctx, blobs)
+		return 0, err
+	}
+
+	return uint64(imageID), nil
+}
+
+// touchImage restarts the grace period of an image that is about to be used,
+// so the image collector leaves it alone. The image is read back, as it may
+// have been collected just before.
+func touchImage(ctx context.Context, repo *repository.Queries, id uint64) (repository.Image, error) {
+	if err := repo.TouchImage(ctx, id); err != nil {
+		return repository.Image{}, err
+	}
+
+	return repo.FindImage(ctx, id)
+}
+
+// imageError writes the response for an error returned by saveImage
+func imageError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, helper.ErrUnsupportedImage):
+		http.Error(w, "Unsupported file type", http.StatusUnsupportedMediaType)
+	case errors.Is(err, helper.ErrImageTooLarge):
+		http.Error(w, "Image is too large", http.StatusRequestEntityTooLarge)
+	case errors.Is(err, helper.ErrInvalidImage):
+		http.Error(w, "Invalid image", http.StatusBadRequest)
+	default:
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+	}
+}
+
+type imageHandler struct {
+	repo      *repository.Queries
+	collector *jobs.ImageCollector
+}
+
+func NewImageHandler(repo *repository.Queries, collector *jobs.ImageCollector) *imageHandler {
+	return &imageHandler{repo: repo, collector: collector}
+}
+
+// AdminFindOrphans reports orphaned images without removing them
+func (h *imageHandler) AdminFindOrphans(w http.ResponseWriter, r *http.Request) {
+	report, err := h.collector.Collect(r.Context(), true)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// AdminDeleteOrphans removes orphaned images and reports what was removed
+func (h *imageHandler) AdminDeleteOrphans(w http.ResponseWriter, r *http.Request) {
+	report, err := h.collector.Collect(r.Context(), false)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"api/cmd/helper"
+	"api/handler/dto"
+	"api/repository"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// importProgressEvery is the number of rows processed between progress updates
+const importProgressEvery = 20
+
+// readImportTable reads the uploaded "file" form field as a table with a header row
+func readImportTable(r *http.Request) ([][]string, error) {
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		return nil, fmt.Errorf("Invalid request body")
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		return nil, fmt.Errorf("file is a required field")
+	}
+	defer file.Close()
+
+	format, err := helper.TableFormat(header.Filename)
+	if err != nil {
+		return nil, fmt.Errorf("Only csv and xlsx files are supported")
+	}
+
+	table, err := helper.ReadTable(file, format)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read file")
+	}
+
+	if len(table) < 2 {
+		return nil, fmt.Errorf("The file has no rows")
+	}
+
+	return table, nil
+}
+
+// tableColumns maps the lower-cased header names of a table to their position
+// and checks that the required columns are present
+func tableColumns(header []string, required ...string) (map[string]int, error) {
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for _, name := range required {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%s column is missing", name)
+		}
+	}
+
+	return columns, nil
+}
+
+// tableCell returns the trimmed value of a named column, or "" if the row is short
+func tableCell(row []string, columns map[string]int, name string) string {
+	i, ok := columns[name]
+	if !ok || i >= len(row) {
+		return ""
+	}
+
+	return strings.TrimSpace(row[i])
+}
+
+// parseImportBool reads a yes/no cell. An empty cell is not valid, so the value
+// already stored is kept.
+func parseImportBool(value string) (sql.NullBool, error) {
+	switch strings.ToLower(value) {
+	case "":
+		return sql.NullBool{}, nil
+	case "0", "false", "no", "n":
+		return sql.NullBool{Bool: false, Valid: true}, nil
+	case "1", "true", "yes", "y":
+		return sql.NullBool{Bool: true, Valid: true}, nil
+	default:
+		return sql.NullBool{}, fmt.Errorf("should be true or false")
+	}
+}
+
+func startImportJob(ctx context.Context, repo *repository.Queries, kind string, total int, userID uint64) (repository.ImportJob, error) {
+	id, err := repo.InsertImportJob(ctx, repository.InsertImportJobParams{
+		Kind:   kind,
+		Status: repository.ImportJobsStatusPending,
+		Total:  int32(total),
+		UserID: sql.NullInt64{Int64: int64(userID), Valid: true},
+	})
+	if err != nil {
+		return repository.ImportJob{}, err
+	}
+
+	return repo.FindImportJob(ctx, repository.FindImportJobParams{ID: uint64(id), Kind: kind})
+}
+
+func finishImportJob(ctx context.Context, repo *repository.Queries, id uint64, status repository.ImportJobsStatus, errors []dto.ImportRowError) {
+	data, err := json.Marshal(errors)
+	if err != nil {
+		fmt.Println(err)
+		data = []byte("[]")
+	}
+
+	err = repo.FinishImportJob(ctx, repository.FinishImportJobParams{
+		Status: status,
+		Errors: data,
+		ID:     id,
+	})
+	if err != nil {
+		fmt.Println(err)
+	}
+}
+
+func importJobResponse(job repository.ImportJob) dto.ImportJobResponse {
+	response := dto.ImportJobResponse{
+		ID:        job.ID,
+		Kind:      job.Kind,
+		Status:    string(job.Status),
+		Total:     job.Total,
+		Processed: job.Processed,
+		Inserted:  job.Inserted,
+		Updated:   job.Updated,
+		Errors:    []dto.ImportRowError{},
+		CreatedAt: job.CreatedAt.Time.UTC().Format(time.RFC3339),
+	}
+
+	if len(job.Errors) > 0 {
+		json.Unmarshal(job.Errors, &response.Errors)
+	}
+
+	if job.FinishedAt.Valid {
+		finishedAt := job.FinishedAt.Time.UTC().Format(time.RFC3339)
+		response.FinishedAt = &finishedAt
+	}
+
+	return response
+}
+
+// writeImportJob responds with the import job of the given kind named by the id URL param
+func writeImportJob(w http.ResponseWriter, r *http.Request, repo *repository.Queries, kind string) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid import ID", http.StatusBadRequest)
+		return
+	}
+
+	job, err := repo.FindImportJob(r.Context(), repository.FindImportJobParams{ID: id, Kind: kind})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Import not found", http.StatusNotFound)
+		} else {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(importJobResponse(job))
+}
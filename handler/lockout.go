@@ -0,0 +1,325 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"api/cmd/middleware"
+	"api/cmd/throttle"
+	"api/handler/dto"
+	"api/repository"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Failed sign ins hold back the account they were for and the address they
+// came from. An address gets more attempts, as several people can share one.
+var (
+	accountPolicy = throttle.Policy{Free: 5, Base: time.Second, Max: 15 * time.Minute, Window: time.Hour}
+	ipPolicy      = throttle.Policy{Free: 20, Base: time.Second, Max: 15 * time.Minute, Window: time.Hour}
+)
+
+// dummyHash is compared against when an email has no account or no password,
+// so a sign in takes as long whether or not the account exists
+var dummyHash = sync.OnceValue(func() []byte {
+	hash, err := bcrypt.GenerateFromPassword([]byte("not a password"), bcrypt.DefaultCost)
+	if err != nil {
+		panic(err)
+	}
+	return hash
+})
+
+func accountKey(email string) string {
+	return "login:account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ip string) string {
+	return "login:ip:" + ip
+}
+
+// loginAttempt counts a sign in against the account and the address before
+// the password is checked, so sign ins made at the same time cannot all slip
+// through under the limit. It returns how long the sign in has to wait after
+// the failures before it, and records the lockout this attempt causes. A sign
+// in that succeeds is taken back with loginSucceeded, once its second factor
+// has been checked too when the account has one.
+func loginAttempt(ctx context.Context, repo *repository.Queries, attempts throttle.Store, r *http.Request, email string, user sql.NullInt64) (time.Duration, error) {
+	account, err := attempts.Fail(ctx, accountKey(email), accountPolicy.Window)
+	if err != nil {
+		return 0, err
+	}
+
+	address, err := attempts.Fail(ctx, ipKey(clientIP(r)), ipPolicy.Window)
+	if err != nil {
+		return 0, err
+	}
+
+	// The attempt that first locks the account out is recorded
+	if accountPolicy.Locked(account.Count+1) && !accountPolicy.Locked(account.Count) {
+		if err := repo.InsertLoginAudit(ctx, loginAudit(r, email, user, repository.LoginAuditsEventLocked)); err != nil {
+			fmt.Println(err)
+		}
+	}
+
+	until := accountPolicy.LockedUntil(account)
+	if at := ipPolicy.LockedUntil(address); at.After(until) {
+		until = at
+	}
+
+	return time.Until(until), nil
+}
+
+// loginFailed records a sign in whose password was wrong in the audit log
+func loginFailed(ctx context.Context, repo *repository.Queries, r *http.Request, email string, user sql.NullInt64) {
+	if err := repo.InsertLoginAudit(ctx, loginAudit(r, email, user, repository.LoginAuditsEventFailed)); err != nil {
+		fmt.Println(err)
+	}
+}
+
+// loginSucceeded clears the failures of the account. The address only gets
+// its attempt back, so signing in to one account does not clear the guesses
+// made from it at others.
+func loginSucceeded(ctx context.Context, attempts throttle.Store, r *http.Request, email string) {
+	if err := attempts.Reset(ctx, accountKey(email)); err != nil {
+		fmt.Println(err)
+	}
+
+	if err := attempts.Undo(ctx, ipKey(clientIP(r))); err != nil {
+		fmt.Println(err)
+	}
+}
+
+func loginAudit(r *http.Request, email string, user sql.NullInt64, event repository.LoginAuditsEvent) repository.InsertLoginAuditParams {
+	ip := clientIP(r)
+
+	audit := repository.InsertLoginAuditParams{
+		UserID:    user,
+		Email:     sql.NullString{String: email, Valid: email != ""},
+		Ip:        sql.NullString{String: ip, Valid: ip != ""},
+		UserAgent: sql.NullString{String: r.UserAgent(), Valid: r.UserAgent() != ""},
+		Event:     event,
+	}
+
+	if len(audit.UserAgent.String) > 255 {
+		audit.UserAgent.String = audit.UserAgent.String[:255]
+	}
+
+	return audit
+}
+
+// reauthKey counts the checks of the password and second factor a signed in
+// user makes to confirm a change to their account
+func reauthKey(userID uint64) string {
+	return "reauth:user:" + strconv.FormatUint(userID, 10)
+}
+
+// reauthAttempt counts a check of the password or second factor of a signed in
+// user before it is made, held back like a sign in, so a stolen session cannot
+// be used to guess them. It writes the response when the user has to wait.
+// Checks that pass are taken back with reauthSucceeded.
+func reauthAttempt(w http.ResponseWriter, r *http.Request, attempts throttle.Store, userID uint64) bool {
+	return holdBack(w, r, attempts, reauthKey(userID))
+}
+
+func reauthSucceeded(ctx context.Context, attempts throttle.Store, userID uint64) {
+	if err := attempts.Reset(ctx, reauthKey(userID)); err != nil {
+		fmt.Println(err)
+	}
+}
+
+// mfaKey counts the second factors tried to finish signing in to an account.
+// It is kept per user rather than per challenge, as each sign in brings a new
+// challenge.
+func mfaKey(userID uint64) string {
+	return "mfa:user:" + strconv.FormatUint(userID, 10)
+}
+
+// mfaAttempt counts a second factor tried to finish signing in before it is
+// checked, held back like a sign in. It writes the response when the user has
+// to wait. Codes that pass are taken back with mfaSucceeded.
+func mfaAttempt(w http.ResponseWriter, r *http.Request, attempts throttle.Store, userID uint64) bool {
+	return holdBack(w, r, attempts, mfaKey(userID))
+}
+
+func mfaSucceeded(ctx context.Context, attempts throttle.Store, userID uint64) {
+	if err := attempts.Reset(ctx, mfaKey(userID)); err != nil {
+		fmt.Println(err)
+	}
+}
+
+// holdBack counts an attempt under key and tells whether it may be made now
+// under the account policy
+func holdBack(w http.ResponseWriter, r *http.Request, attempts throttle.Store, key string) bool {
+	failures, err := attempts.Fail(r.Context(), key, accountPolicy.Window)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return false
+	}
+
+	if wait := time.Until(accountPolicy.LockedUntil(failures)); wait > 0 {
+		tooManyRequests(w, "Too many failed attempts, try again later", retryAfter(wait))
+		return false
+	}
+
+	return true
+}
+
+// retryAfter rounds a wait up to whole seconds
+func retryAfter(wait time.Duration) int64 {
+	return int64(math.Ceil(wait.Seconds()))
+}
+
+type lockoutHandler struct {
+	repo     *repository.Queries
+	attempts throttle.Store
+}
+
+func NewLockoutHandler(repo *repository.Queries, attempts throttle.Store) *lockoutHandler {
+	return &lockoutHandler{repo: repo, attempts: attempts}
+}
+
+// Show how many sign ins of a user have failed and until when it is held back
+func (h *lockoutHandler) AdminFindOne(w http.ResponseWriter, r *http.Request) {
+	user, ok := findUser(w, r, h.repo)
+	if !ok {
+		return
+	}
+
+	failures, err := h.attempts.Get(r.Context(), accountKey(user.Email.String))
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	response := dto.LockoutResponse{
+		Failures: failures.Count,
+		Locked:   accountPolicy.Locked(failures.Count),
+	}
+
+	if until := accountPolicy.LockedUntil(failures); until.After(time.Now()) {
+		lockedUntil := until.UTC().Format(time.RFC3339)
+		response.LockedUntil = &lockedUntil
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// Let a user sign in again straight away
+func (h *lockoutHandler) AdminUnlock(w http.ResponseWriter, r *http.Request) {
+	user, ok := findUser(w, r, h.repo)
+	if !ok {
+		return
+	}
+
+	actorID, err := middleware.UserID(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.attempts.Reset(r.Context(), accountKey(user.Email.String)); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to unlock account", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.repo.InsertLoginAudit(r.Context(), repository.InsertLoginAuditParams{
+		UserID:  sql.NullInt64{Int64: int64(user.ID), Valid: true},
+		ActorID: sql.NullInt64{Int64: int64(actorID), Valid: true},
+		Email:   user.Email,
+		Ip:      sql.NullString{String: clientIP(r), Valid: true},
+		Event:   repository.LoginAuditsEventUnlocked,
+	}); err != nil {
+		fmt.Println(err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// List the failed sign ins, lockouts and unlocks of a user
+func (h *lockoutHandler) AdminFindAudits(w http.ResponseWriter, r *http.Request) {
+	user, ok := findUser(w, r, h.repo)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	userID := sql.NullInt64{Int64: int64(user.ID), Valid: true}
+
+	audits, err := h.repo.FindUserLoginAudits(r.Context(), repository.FindUserLoginAuditsParams{
+		UserID: userID,
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	total, err := h.repo.CountUserLoginAudits(r.Context(), userID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	var data = []dto.LoginAuditResponse{}
+
+	for _, a := range audits {
+		audit := dto.LoginAuditResponse{
+			ID:    a.ID,
+			Event: string(a.Event),
+		}
+
+		if a.ActorID.Valid {
+			actorID := uint64(a.ActorID.Int64)
+			audit.ActorID = &actorID
+		}
+
+		if a.Ip.Valid {
+			audit.IP = &a.Ip.String
+		}
+
+		if a.UserAgent.Valid {
+			audit.UserAgent = &a.UserAgent.String
+		}
+
+		if a.CreatedAt.Valid {
+			createdAt := a.CreatedAt.Time.UTC().Format(time.RFC3339)
+			audit.CreatedAt = &createdAt
+		}
+
+		data = append(data, audit)
+	}
+
+	response := map[string]interface{}{
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+		"data":   data,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
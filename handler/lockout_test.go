@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"api/cmd/throttle"
+)
+
+func TestMFAAttempt(t *testing.T) {
+	ctx := context.Background()
+	attempts := throttle.NewMemory()
+
+	try := func(userID uint64) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/auth/mfa/verify", nil)
+
+		if ok := mfaAttempt(w, r, attempts, userID); ok != (w.Code == http.StatusOK) {
+			t.Fatalf("mfaAttempt() = %v with status %d", ok, w.Code)
+		}
+
+		return w
+	}
+
+	// Each wrong code is counted against the user whichever challenge it was
+	// sent with, so new challenges bring no new tries
+	for i := int64(0); i < accountPolicy.Free; i++ {
+		if w := try(1); w.Code != http.StatusOK {
+			t.Fatalf("attempt %d: status %d, want %d", i+1, w.Code, http.StatusOK)
+		}
+	}
+
+	w := try(1)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("attempt past the limit: status %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("attempt past the limit has no Retry-After")
+	}
+
+	if w := try(2); w.Code != http.StatusOK {
+		t.Errorf("other user: status %d, want %d", w.Code, http.StatusOK)
+	}
+
+	mfaSucceeded(ctx, attempts, 1)
+
+	if w := try(1); w.Code != http.StatusOK {
+		t.Errorf("after a code passed: status %d, want %d", w.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,577 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"api/cmd/helper"
+	"api/cmd/middleware"
+	"api/cmd/receipt"
+	"api/cmd/totp"
+	"api/handler/dto"
+	"api/repository"
+
+	"github.com/go-playground/validator"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// mfaIssuer names the account in authenticator apps
+const mfaIssuer = "Fixchirp"
+
+// mfaMaxAttempts is how many codes can be tried against one challenge
+const mfaMaxAttempts = 5
+
+// mfaEnabled tells whether a user has finished enrolling in two-factor
+// authentication
+func (h *AuthHandler) mfaEnabled(ctx context.Context, userID uint64) (bool, error) {
+	mfa, err := h.repo.FindUserMfa(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return mfa.EnabledAt.Valid, nil
+}
+
+// mfaChallenge answers a sign in with a token to send back along with the
+// second factor, in place of a session
+func (h *AuthHandler) mfaChallenge(w http.ResponseWriter, r *http.Request, user repository.User) {
+	token, err := h.newActionToken(r.Context(), user, helper.PurposeMFA, helper.MFAChallengeTTL)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	response := dto.MFAChallengeResponse{
+		MFARequired: true,
+		MFAToken:    token,
+		ExpiresIn:   int64(helper.MFAChallengeTTL.Seconds()),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// checkSecondFactor checks an authenticator code, or else a recovery code. A
+// code is used up once it has been accepted.
+func checkSecondFactor(ctx context.Context, repo *repository.Queries, userID uint64, code, recoveryCode string) (bool, error) {
+	mfa, err := repo.FindUserMfa(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if code != "" {
+		step, ok, err := totp.Validate(mfa.Secret, code, time.Now())
+		if err != nil || !ok {
+			return false, err
+		}
+
+		used, err := repo.UseMfaStep(ctx, repository.UseMfaStepParams{
+			LastStep:   step,
+			UserID:     userID,
+			LastStep_2: step,
+		})
+		if err != nil {
+			return false, err
+		}
+
+		return used == 1, nil
+	}
+
+	if !mfa.EnabledAt.Valid {
+		return false, nil
+	}
+
+	used, err := repo.UseRecoveryCode(ctx, repository.UseRecoveryCodeParams{
+		UserID:   userID,
+		CodeHash: hashToken(totp.NormalizeRecoveryCode(recoveryCode)),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return used == 1, nil
+}
+
+// newRecoveryCodes replaces the recovery codes of a user. Only their hashes
+// are stored, so they are shown this once.
+func newRecoveryCodes(ctx context.Context, repo *repository.Queries, userID uint64) ([]string, error) {
+	codes, err := totp.NewRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := repo.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	for _, code := range codes {
+		if err := repo.InsertRecoveryCode(ctx, repository.InsertRecoveryCodeParams{
+			UserID:   userID,
+			CodeHash: hashToken(code),
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return codes, nil
+}
+
+// mfaUser reads the signed in user
+func (h *AuthHandler) mfaUser(w http.ResponseWriter, r *http.Request) (repository.User, bool) {
+	userID, err := middleware.UserID(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return repository.User{}, false
+	}
+
+	user, err := h.repo.FindUserByID(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		} else {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return user, false
+	}
+
+	return user, true
+}
+
+// reauthenticate asks for the password, when the account has one, and a
+// second factor again before two-factor authentication is changed
+func (h *AuthHandler) reauthenticate(w http.ResponseWriter, r *http.Request, user repository.User) bool {
+	var data dto.MFAReauthRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return false
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(data); err != nil {
+		http.Error(w, validationMessage(err), http.StatusBadRequest)
+		return false
+	}
+
+	if !reauthAttempt(w, r, h.attempts, user.ID) {
+		return false
+	}
+
+	if user.Password != "" {
+		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(data.Password)); err != nil {
+			http.Error(w, "Invalid password", http.StatusUnauthorized)
+			return false
+		}
+	}
+
+	ok, err := checkSecondFactor(r.Context(), h.repo, user.ID, data.Code, data.RecoveryCode)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return false
+	}
+
+	if !ok {
+		http.Error(w, "Invalid code", http.StatusUnauthorized)
+		return false
+	}
+
+	reauthSucceeded(r.Context(), h.attempts, user.ID)
+
+	return true
+}
+
+// MFAVerify finishes a sign in that was answered with a challenge, with a
+// code from the authenticator app or a recovery code
+func (h *AuthHandler) MFAVerify(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var data dto.MFAVerifyRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(data); err != nil {
+		http.Error(w, validationMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	challenge, err := h.validator.GetActionToken(data.MFAToken, helper.PurposeMFA)
+	if err != nil {
+		http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
+		return
+	}
+
+	// Each challenge can be tried a few times, then the password is needed
+	// again
+	attempted, err := h.repo.AttemptUserToken(ctx, repository.AttemptUserTokenParams{
+		ID:       challenge.ID,
+		UserID:   challenge.UserID,
+		Purpose:  helper.PurposeMFA,
+		Attempts: mfaMaxAttempts,
+	})
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if attempted == 0 {
+		http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
+		return
+	}
+
+	// Wrong codes also count against the user, so the limit holds across
+	// challenges
+	if !mfaAttempt(w, r, h.attempts, challenge.UserID) {
+		return
+	}
+
+	ok, err := checkSecondFactor(ctx, h.repo, challenge.UserID, data.Code, data.RecoveryCode)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if !ok {
+		http.Error(w, "Invalid code", http.StatusUnauthorized)
+		return
+	}
+
+	used, err := h.repo.UseUserToken(ctx, repository.UseUserTokenParams{
+		ID:      challenge.ID,
+		UserID:  challenge.UserID,
+		Purpose: helper.PurposeMFA,
+	})
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if used == 0 {
+		http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := h.repo.FindUserByID(ctx, challenge.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		} else {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	mfaSucceeded(ctx, h.attempts, user.ID)
+
+	// The sign in counted against the account is only cleared now
+	if user.Email.Valid {
+		loginSucceeded(ctx, h.attempts, r, user.Email.String)
+	}
+
+	h.signIn(w, r, user, data.StoreID, true)
+}
+
+// MFAStatus shows whether the signed in user has two-factor authentication
+func (h *AuthHandler) MFAStatus(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.mfaUser(w, r)
+	if !ok {
+		return
+	}
+
+	enabled, err := h.mfaEnabled(r.Context(), user.ID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	response := dto.MFAStatusResponse{Enabled: enabled}
+
+	if enabled {
+		response.RecoveryCodesLeft, err = h.repo.CountRecoveryCodes(r.Context(), user.ID)
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// MFAEnroll starts enrolling the signed in user with a new secret, shown as a
+// provisioning URI and as a QR code of it. Two-factor authentication is only
+// turned on once a code from the app is confirmed.
+func (h *AuthHandler) MFAEnroll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := h.mfaUser(w, r)
+	if !ok {
+		return
+	}
+
+	enabled, err := h.mfaEnabled(ctx, user.ID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if enabled {
+		http.Error(w, "Two-factor authentication is already enabled", http.StatusConflict)
+		return
+	}
+
+	secret, err := totp.NewSecret()
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.repo.UpsertUserMfa(ctx, repository.UpsertUserMfaParams{
+		UserID: user.ID,
+		Secret: secret,
+	}); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	account := user.Email.String
+	if !user.Email.Valid {
+		account = user.Phone.String
+	}
+
+	uri := totp.URI(mfaIssuer, account, secret)
+
+	code, err := receipt.QRCode(uri, 256)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	response := dto.MFAEnrollResponse{
+		Secret: secret,
+		URI:    uri,
+		QR:     "data:image/png;base64," + base64.StdEncoding.EncodeToString(code),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// MFAConfirm turns two-factor authentication on with a first code from the
+// app, and hands out the recovery codes
+func (h *AuthHandler) MFAConfirm(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := h.mfaUser(w, r)
+	if !ok {
+		return
+	}
+
+	var data dto.MFACodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(data); err != nil {
+		http.Error(w, validationMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	mfa, err := h.repo.FindUserMfa(ctx, user.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Start enrolling first", http.StatusBadRequest)
+		} else {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if mfa.EnabledAt.Valid {
+		http.Error(w, "Two-factor authentication is already enabled", http.StatusConflict)
+		return
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	repo := h.repo.WithTx(tx)
+
+	ok, err = checkSecondFactor(ctx, repo, user.ID, data.Code, "")
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if !ok {
+		http.Error(w, "Invalid code", http.StatusBadRequest)
+		return
+	}
+
+	if err := repo.EnableUserMfa(ctx, user.ID); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	codes, err := newRecoveryCodes(ctx, repo, user.ID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.MFARecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// MFARecoveryCodes replaces the recovery codes of the signed in user, after
+// they sign in again
+func (h *AuthHandler) MFARecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := h.mfaUser(w, r)
+	if !ok {
+		return
+	}
+
+	enabled, err := h.mfaEnabled(ctx, user.ID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if !enabled {
+		http.Error(w, "Two-factor authentication is not enabled", http.StatusBadRequest)
+		return
+	}
+
+	if !h.reauthenticate(w, r, user) {
+		return
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	codes, err := newRecoveryCodes(ctx, h.repo.WithTx(tx), user.ID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.MFARecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// MFADisable turns two-factor authentication off for the signed in user,
+// after they sign in again
+func (h *AuthHandler) MFADisable(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := h.mfaUser(w, r)
+	if !ok {
+		return
+	}
+
+	enabled, err := h.mfaEnabled(ctx, user.ID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if !enabled {
+		http.Error(w, "Two-factor authentication is not enabled", http.StatusBadRequest)
+		return
+	}
+
+	if !h.reauthenticate(w, r, user) {
+		return
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	repo := h.repo.WithTx(tx)
+
+	if err := repo.DeleteRecoveryCodes(ctx, user.ID); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err := repo.DeleteUserMfa(ctx, user.ID); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
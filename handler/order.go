@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"time"
 
+	"api/cmd/costing"
 	"api/cmd/middleware"
 	"api/handler/dto"
 	"api/repository"
@@ -19,17 +20,18 @@ import (
 )
 
 type orderHandler struct {
-	repo *repository.Queries
-	db   *sql.DB
+	repo  *repository.Queries
+	db    *sql.DB
+	costs *costing.Engine
 }
 
-func NewOrderHandler(db *sql.DB, repo *repository.Queries) *orderHandler {
-	return &orderHandler{db: db, repo: repo}
+func NewOrderHandler(db *sql.DB, repo *repository.Queries, costs *costing.Engine) *orderHandler {
+	return &orderHandler{db: db, repo: repo, costs: costs}
 }
 
 func (h *orderHandler) CreateInStoreOrder(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
-	cashierID, err := middleware.GuardCashier(r.Context(), h.repo)
+	cashierID, err := middleware.UserID(r.Context())
 	if err != nil {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
@@ -53,6 +55,8 @@ func (h *orderHandler) CreateInStoreOrder(w http.ResponseWriter, r *http.Request
 				msg = fmt.Sprintf("%s should at least be greater than %s", err.Field(), err.Param())
 			case "email":
 				msg = fmt.Sprintf("%s provided is invalid", err.Field())
+			case "oneof":
+				msg = fmt.Sprintf("%s should be one of %s", err.Field(), err.Param())
 			}
 		}
 
@@ -60,9 +64,24 @@ func (h *orderHandler) CreateInStoreOrder(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	tender := repository.InStoreOrderDetailsTenderCash
+	if form.Tender != "" {
+		tender = repository.InStoreOrderDetailsTender(form.Tender)
+	}
+
 	total := calculateTotal(form.Items)
 	var number string
 
+	if tender == repository.InStoreOrderDetailsTenderCash && form.Tendered > 0 && form.Tendered < total {
+		http.Error(w, "Amount tendered is less than the order total", http.StatusBadRequest)
+		return
+	}
+
+	var tendered sql.NullFloat64
+	if form.Tendered > 0 {
+		tendered = sql.NullFloat64{Float64: form.Tendered, Valid: true}
+	}
+
 	tx, err := h.db.Begin()
 	if err != nil {
 		http.Error(w, "Something went wrong", http.StatusInternalServerError)
@@ -101,6 +120,22 @@ func (h *orderHandler) CreateInStoreOrder(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	shift, err := repo.FindOpenShiftByCashier(ctx, u.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Open a shift before selling", http.StatusForbidden)
+		} else {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if shift.StoreID != form.StoreID {
+		http.Error(w, "Your open shift is at another store", http.StatusForbidden)
+		return
+	}
+
 	o, err := repo.FindLastCreatedOrder(ctx)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -146,7 +181,7 @@ func (h *orderHandler) CreateInStoreOrder(w http.ResponseWriter, r *http.Request
 	}
 
 	for _, item := range form.Items {
-		p, err := repo.FindProductBySKU(ctx, item.SKU)
+		p, err := findProductByCode(ctx, repo, item.SKU)
 		if err != nil {
 			tx.Rollback()
 			if err == sql.ErrNoRows {
@@ -182,11 +217,19 @@ func (h *orderHandler) CreateInStoreOrder(w http.ResponseWriter, r *http.Request
 			return
 		}
 
+		cogs, err := h.costs.Issue(ctx, repo, p.ID, sql.NullInt64{Int64: int64(s.ID), Valid: true}, item.Quantity)
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
 		err = repo.InsertOrderItem(ctx, repository.InsertOrderItemParams{
 			OrderID:   uint64(orderID),
 			ProductID: p.ID,
 			Quantity:  item.Quantity,
 			Price:     item.Price,
+			Cogs:      sql.NullFloat64{Float64: cogs, Valid: true},
 		})
 		if err != nil {
 			tx.Rollback()
@@ -199,6 +242,9 @@ func (h *orderHandler) CreateInStoreOrder(w http.ResponseWriter, r *http.Request
 		OrderID:   uint64(orderID),
 		CashierID: sql.NullInt64{Int64: int64(cashierID), Valid: true},
 		StoreID:   s.ID,
+		ShiftID:   sql.NullInt64{Int64: int64(shift.ID), Valid: true},
+		Tender:    tender,
+		Tendered:  tendered,
 	})
 	if err != nil {
 		tx.Rollback()
@@ -271,7 +317,7 @@ func (h *orderHandler) CreateInStoreOrder(w http.ResponseWriter, r *http.Request
 		ID:        u.ID,
 		Firstname: u.Firstname,
 		Lastname:  u.Lastname,
-		Email:     u.Email,
+		Email:     u.Email.String,
 	}
 
 	if u.Phone.Valid {
@@ -286,8 +332,11 @@ func (h *orderHandler) CreateInStoreOrder(w http.ResponseWriter, r *http.Request
 		Total:   orderResult.Total,
 		Items:   items,
 		Details: dto.StoreOrderDetails{
-			Store:   store,
-			Cashier: cashier,
+			Store:    store,
+			Cashier:  cashier,
+			ShiftID:  &shift.ID,
+			Tender:   string(tender),
+			Tendered: nullFloat(tendered),
 		},
 		CreatedAt: orderResult.CreatedAt.Time.UTC().Format(time.RFC3339),
 	}
@@ -305,7 +354,7 @@ func (h *orderHandler) CreateInStoreOrder(w http.ResponseWriter, r *http.Request
 
 func (h *orderHandler) CreateOnlineOrder(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
-	customerID, err := middleware.GuardCustomer(r.Context(), h.repo)
+	customerID, err := middleware.UserID(r.Context())
 	if err != nil {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
@@ -385,6 +434,45 @@ func (h *orderHandler) CreateOnlineOrder(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
+	s, err := repo.FindStore(ctx, form.StoreID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Store not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Orders go to the address picked, or else to the default address of the
+	// customer when they have one
+	var delivery repository.UserAddress
+	if form.AddressID != nil {
+		delivery, err = repo.FindUserAddress(ctx, repository.FindUserAddressParams{
+			ID:     *form.AddressID,
+			UserID: customerID,
+		})
+	} else {
+		delivery, err = repo.FindDefaultUserAddress(ctx, customerID)
+	}
+	if err != nil && (form.AddressID != nil || err != sql.ErrNoRows) {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Address not found", http.StatusNotFound)
+		} else {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	details := repository.InsertOnlineOrderDetailsParams{
+		CustomerID: sql.NullInt64{Int64: int64(customerID), Valid: true},
+	}
+
+	if err == nil {
+		deliverTo(&details, delivery)
+	}
+
 	orderID, err := repo.InsertOrder(ctx, repository.InsertOrderParams{
 		Number:  number,
 		Total:   total,
@@ -440,11 +528,19 @@ func (h *orderHandler) CreateOnlineOrder(w http.ResponseWriter, r *http.Request)
 			return
 		}
 
+		cogs, err := h.costs.Issue(ctx, repo, p.ID, sql.NullInt64{Int64: int64(s.ID), Valid: true}, item.Quantity)
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
 		err = repo.InsertOrderItem(ctx, repository.InsertOrderItemParams{
 			OrderID:   uint64(orderID),
 			ProductID: p.ID,
 			Quantity:  item.Quantity,
 			Price:     item.Price,
+			Cogs:      sql.NullFloat64{Float64: cogs, Valid: true},
 		})
 		if err != nil {
 			tx.Rollback()
@@ -453,10 +549,10 @@ func (h *orderHandler) CreateOnlineOrder(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
-	err = repo.InsertOnlineOrderDetails(ctx, repository.InsertOnlineOrderDetailsParams{
-		OrderID:    uint64(orderID),
-		CustomerID: sql.NullInt64{Int64: int64(customerID), Valid: true},
-	})
+	details.OrderID = uint64(orderID)
+	details.StoreID = sql.NullInt64{Int64: int64(s.ID), Valid: true}
+
+	err = repo.InsertOnlineOrderDetails(ctx, details)
 	if err != nil {
 		tx.Rollback()
 		http.Error(w, "Failed to add order details", http.StatusInternalServerError)
@@ -470,6 +566,15 @@ func (h *orderHandler) CreateOnlineOrder(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	od, err := repo.FindOnlineOrderDetails(ctx, orderResult.ID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	address := orderAddress(od)
+
 	orderItems, err := repo.FindOrderItems(ctx, orderResult.ID)
 	if err != nil {
 		tx.Rollback()
@@ -521,7 +626,7 @@ func (h *orderHandler) CreateOnlineOrder(w http.ResponseWriter, r *http.Request)
 		ID:        u.ID,
 		Firstname: u.Firstname,
 		Lastname:  u.Lastname,
-		Email:     u.Email,
+		Email:     u.Email.String,
 	}
 
 	if u.Phone.Valid {
@@ -572,6 +677,7 @@ func (h *orderHandler) CreateOnlineOrder(w http.ResponseWriter, r *http.Request)
 		Items:   items,
 		Details: dto.OnlineOrderDetails{
 			Customer: customer,
+			Address:  address,
 		},
 		CreatedAt: orderResult.CreatedAt.Time.UTC().Format(time.RFC3339),
 	}
@@ -590,12 +696,6 @@ func (h *orderHandler) CreateOnlineOrder(w http.ResponseWriter, r *http.Request)
 
 func (h *orderHandler) AdminFindInStoreOrders(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
-	_, err := middleware.GuardAdmin(r.Context(), h.repo)
-	if err != nil {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
-
 	// Parse query parameters for pagination
 	limitStr := r.URL.Query().Get("limit")
 	offsetStr := r.URL.Query().Get("offset")
@@ -605,6 +705,28 @@ func (h *orderHandler) AdminFindInStoreOrders(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	// Online orders do not belong to one store, so only callers who can see
+	// every store list them
+	if storeIDStr == string(repository.OrdersChannelOnline) {
+		if !middleware.ContextGetStores(r.Context()).All {
+			http.Error(w, "You cannot access online orders", http.StatusForbidden)
+			return
+		}
+	} else if storeID, err := strconv.ParseUint(storeIDStr, 10, 64); err == nil && !checkStore(w, r.Context(), storeID) {
+		return
+	}
+
+	format, err := exportFormat(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if format != "" {
+		h.exportOrders(w, r, format, storeIDStr)
+		return
+	}
+
 	// Convert limit and offset to integers
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil {
@@ -688,6 +810,8 @@ func (h *orderHandler) AdminFindInStoreOrders(w http.ResponseWriter, r *http.Req
 				return
 			}
 
+			address := orderAddress(od)
+
 			//TODO Cashier can be removed
 			u, err := h.repo.FindUserByID(ctx, uint64(od.CustomerID.Int64))
 			if err != nil {
@@ -703,7 +827,7 @@ func (h *orderHandler) AdminFindInStoreOrders(w http.ResponseWriter, r *http.Req
 				ID:        u.ID,
 				Firstname: u.Firstname,
 				Lastname:  u.Lastname,
-				Email:     u.Email,
+				Email:     u.Email.String,
 			}
 
 			if u.Phone.Valid {
@@ -719,6 +843,7 @@ func (h *orderHandler) AdminFindInStoreOrders(w http.ResponseWriter, r *http.Req
 				Items:   items,
 				Details: dto.OnlineOrderDetails{
 					Customer: customer,
+					Address:  address,
 				},
 				CreatedAt: or.CreatedAt.Time.UTC().Format(time.RFC3339),
 			}
@@ -849,7 +974,7 @@ func (h *orderHandler) AdminFindInStoreOrders(w http.ResponseWriter, r *http.Req
 				ID:        u.ID,
 				Firstname: u.Firstname,
 				Lastname:  u.Lastname,
-				Email:     u.Email,
+				Email:     u.Email.String,
 			}
 
 			if u.Phone.Valid {
@@ -864,8 +989,11 @@ func (h *orderHandler) AdminFindInStoreOrders(w http.ResponseWriter, r *http.Req
 				Total:   or.Total,
 				Items:   items,
 				Details: dto.StoreOrderDetails{
-					Store:   store,
-					Cashier: cashier,
+					Store:    store,
+					Cashier:  cashier,
+					ShiftID:  nullID(od.ShiftID),
+					Tender:   string(od.Tender),
+					Tendered: nullFloat(od.Tendered),
 				},
 				CreatedAt: or.CreatedAt.Time.UTC().Format(time.RFC3339),
 			}
@@ -888,12 +1016,6 @@ func (h *orderHandler) AdminFindInStoreOrders(w http.ResponseWriter, r *http.Req
 
 func (h *orderHandler) AdminFindInStoreOrder(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
-	_, err := middleware.GuardAdmin(r.Context(), h.repo)
-	if err != nil {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
-
 	// Get the order ID from URL params
 	orderIDStr := chi.URLParam(r, "id")
 	orderID, err := strconv.ParseUint(orderIDStr, 10, 64)
@@ -974,6 +1096,11 @@ func (h *orderHandler) AdminFindInStoreOrder(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	if !middleware.ContextGetStores(r.Context()).Allows(od.StoreID) {
+		http.Error(w, "Order not found", http.StatusNotFound)
+		return
+	}
+
 	s, err := h.repo.FindStore(ctx, od.StoreID)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -1006,7 +1133,7 @@ func (h *orderHandler) AdminFindInStoreOrder(w http.ResponseWriter, r *http.Requ
 		ID:        u.ID,
 		Firstname: u.Firstname,
 		Lastname:  u.Lastname,
-		Email:     u.Email,
+		Email:     u.Email.String,
 	}
 
 	if u.Phone.Valid {
@@ -1021,8 +1148,11 @@ func (h *orderHandler) AdminFindInStoreOrder(w http.ResponseWriter, r *http.Requ
 		Total:   or.Total,
 		Items:   items,
 		Details: dto.StoreOrderDetails{
-			Store:   store,
-			Cashier: cashier,
+			Store:    store,
+			Cashier:  cashier,
+			ShiftID:  nullID(od.ShiftID),
+			Tender:   string(od.Tender),
+			Tendered: nullFloat(od.Tendered),
 		},
 		CreatedAt: or.CreatedAt.Time.UTC().Format(time.RFC3339),
 	}
@@ -1034,12 +1164,6 @@ func (h *orderHandler) AdminFindInStoreOrder(w http.ResponseWriter, r *http.Requ
 
 func (h *orderHandler) AdminReport(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
-	_, err := middleware.GuardAdmin(r.Context(), h.repo)
-	if err != nil {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
-
 	countCategories, err := h.repo.CountCategories(ctx)
 	if err != nil {
 		http.Error(w, "Something went wrong", http.StatusInternalServerError)
@@ -1052,13 +1176,13 @@ func (h *orderHandler) AdminReport(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	countPurchases, err := h.repo.CountPurchases(ctx)
+	countPurchases, err := h.repo.CountPurchases(ctx, storeFilter(r.Context()))
 	if err != nil {
 		http.Error(w, "Something went wrong", http.StatusInternalServerError)
 		return
 	}
 
-	countSales, err := h.repo.CountOnlineOrders(ctx)
+	countSales, err := h.repo.CountSales(ctx)
 	if err != nil {
 		http.Error(w, "Something went wrong", http.StatusInternalServerError)
 		return
@@ -1079,7 +1203,7 @@ func (h *orderHandler) AdminReport(w http.ResponseWriter, r *http.Request) {
 
 func (h *orderHandler) CashierFindInStoreOrders(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
-	cashierID, err := middleware.GuardCashier(r.Context(), h.repo)
+	cashierID, err := middleware.UserID(r.Context())
 	if err != nil {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
@@ -1232,7 +1356,7 @@ func (h *orderHandler) CashierFindInStoreOrders(w http.ResponseWriter, r *http.R
 			ID:        u.ID,
 			Firstname: u.Firstname,
 			Lastname:  u.Lastname,
-			Email:     u.Email,
+			Email:     u.Email.String,
 		}
 
 		if u.Phone.Valid {
@@ -1247,8 +1371,11 @@ func (h *orderHandler) CashierFindInStoreOrders(w http.ResponseWriter, r *http.R
 			Total:   or.Total,
 			Items:   items,
 			Details: dto.StoreOrderDetails{
-				Store:   store,
-				Cashier: cashier,
+				Store:    store,
+				Cashier:  cashier,
+				ShiftID:  nullID(od.ShiftID),
+				Tender:   string(od.Tender),
+				Tendered: nullFloat(od.Tendered),
 			},
 			CreatedAt: or.CreatedAt.Time.UTC().Format(time.RFC3339),
 		}
@@ -1270,7 +1397,7 @@ func (h *orderHandler) CashierFindInStoreOrders(w http.ResponseWriter, r *http.R
 
 func (h *orderHandler) CashierFindInStoreOrder(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
-	cashierID, err := middleware.GuardCashier(r.Context(), h.repo)
+	cashierID, err := middleware.UserID(r.Context())
 	if err != nil {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
@@ -1413,7 +1540,7 @@ func (h *orderHandler) CashierFindInStoreOrder(w http.ResponseWriter, r *http.Re
 		ID:        u.ID,
 		Firstname: u.Firstname,
 		Lastname:  u.Lastname,
-		Email:     u.Email,
+		Email:     u.Email.String,
 	}
 
 	if u.Phone.Valid {
@@ -1428,8 +1555,11 @@ func (h *orderHandler) CashierFindInStoreOrder(w http.ResponseWriter, r *http.Re
 		Total:   or.Total,
 		Items:   items,
 		Details: dto.StoreOrderDetails{
-			Store:   store,
-			Cashier: cashier,
+			Store:    store,
+			Cashier:  cashier,
+			ShiftID:  nullID(od.ShiftID),
+			Tender:   string(od.Tender),
+			Tendered: nullFloat(od.Tendered),
 		},
 		CreatedAt: or.CreatedAt.Time.UTC().Format(time.RFC3339),
 	}
@@ -1536,6 +1666,8 @@ func (h *orderHandler) CustomerFindOnlineOrder(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	address := orderAddress(od)
+
 	u, err := h.repo.FindUserByID(ctx, uint64(od.CustomerID.Int64))
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -1550,7 +1682,7 @@ func (h *orderHandler) CustomerFindOnlineOrder(w http.ResponseWriter, r *http.Re
 		ID:        u.ID,
 		Firstname: u.Firstname,
 		Lastname:  u.Lastname,
-		Email:     u.Email,
+		Email:     u.Email.String,
 	}
 
 	if u.Phone.Valid {
@@ -1566,6 +1698,7 @@ func (h *orderHandler) CustomerFindOnlineOrder(w http.ResponseWriter, r *http.Re
 		Items:   items,
 		Details: dto.OnlineOrderDetails{
 			Customer: customer,
+			Address:  address,
 		},
 		CreatedAt: or.CreatedAt.Time.UTC().Format(time.RFC3339),
 	}
@@ -1619,12 +1752,6 @@ func (h *orderHandler) CustomerOrderPaid(w http.ResponseWriter, r *http.Request)
 
 func (h *orderHandler) CustomerFindOnlineOrders(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
-	_, err := middleware.GuardCustomer(r.Context(), h.repo)
-	if err != nil {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
-
 	// Parse query parameters for pagination
 	limitStr := r.URL.Query().Get("limit")
 	offsetStr := r.URL.Query().Get("offset")
@@ -1710,6 +1837,8 @@ func (h *orderHandler) CustomerFindOnlineOrders(w http.ResponseWriter, r *http.R
 			return
 		}
 
+		address := orderAddress(od)
+
 		//TODO Cashier can be removed
 		u, err := h.repo.FindUserByID(ctx, uint64(od.CustomerID.Int64))
 		if err != nil {
@@ -1725,7 +1854,7 @@ func (h *orderHandler) CustomerFindOnlineOrders(w http.ResponseWriter, r *http.R
 			ID:        u.ID,
 			Firstname: u.Firstname,
 			Lastname:  u.Lastname,
-			Email:     u.Email,
+			Email:     u.Email.String,
 		}
 
 		if u.Phone.Valid {
@@ -1741,6 +1870,7 @@ func (h *orderHandler) CustomerFindOnlineOrders(w http.ResponseWriter, r *http.R
 			Items:   items,
 			Details: dto.OnlineOrderDetails{
 				Customer: customer,
+				Address:  address,
 			},
 			CreatedAt: or.CreatedAt.Time.UTC().Format(time.RFC3339),
 		}
@@ -1763,12 +1893,6 @@ func (h *orderHandler) CustomerFindOnlineOrders(w http.ResponseWriter, r *http.R
 
 func (h *orderHandler) CustomerGetOnlineOrder(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
-	_, err := middleware.GuardCustomer(r.Context(), h.repo)
-	if err != nil {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
-
 	// Get the order ID from URL params
 	orderIDStr := chi.URLParam(r, "id")
 	orderID, err := strconv.ParseUint(orderIDStr, 10, 64)
@@ -1849,6 +1973,8 @@ func (h *orderHandler) CustomerGetOnlineOrder(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	address := orderAddress(od)
+
 	//TODO Cashier can be removed
 	u, err := h.repo.FindUserByID(ctx, uint64(od.CustomerID.Int64))
 	if err != nil {
@@ -1864,7 +1990,7 @@ func (h *orderHandler) CustomerGetOnlineOrder(w http.ResponseWriter, r *http.Req
 		ID:        u.ID,
 		Firstname: u.Firstname,
 		Lastname:  u.Lastname,
-		Email:     u.Email,
+		Email:     u.Email.String,
 	}
 
 	if u.Phone.Valid {
@@ -1880,6 +2006,7 @@ func (h *orderHandler) CustomerGetOnlineOrder(w http.ResponseWriter, r *http.Req
 		Items:   items,
 		Details: dto.OnlineOrderDetails{
 			Customer: customer,
+			Address:  address,
 		},
 		CreatedAt: or.CreatedAt.Time.UTC().Format(time.RFC3339),
 	}
@@ -1897,6 +2024,14 @@ func calculateTotal(items []dto.OrderItem) float64 {
 	return total
 }
 
+func nullFloat(value sql.NullFloat64) *float64 {
+	if !value.Valid {
+		return nil
+	}
+
+	return &value.Float64
+}
+
 func incrementNumber(num string) (string, error) {
 	// Convert the input string to an integer
 	number, err := strconv.Atoi(num)
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"api/cmd/helper"
+	"api/repository"
+)
+
+// exportOrders streams the in-store orders of a store, or the online orders,
+// as a file. Orders are read in batches, newest first.
+func (h *orderHandler) exportOrders(w http.ResponseWriter, r *http.Request, format, store string) {
+	from, to, err := exportRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if store == string(repository.OrdersChannelOnline) {
+		columns := []string{"Order number", "Date", "Status", "Store", "Customer", "Email", "Units", "Total", "Cost of sales"}
+
+		writeExport(w, format, "online-orders", columns, func(table helper.TableWriter) error {
+			before := uint64(math.MaxInt64)
+
+			for {
+				rows, err := h.repo.FindOnlineOrdersForExport(r.Context(), repository.FindOnlineOrdersForExportParams{
+					FromDate: from,
+					ToDate:   to,
+					BeforeID: before,
+					Limit:    exportBatchSize,
+				})
+				if err != nil {
+					return err
+				}
+
+				for _, row := range rows {
+					err := table.WriteRow([]interface{}{
+						row.Number,
+						row.CreatedAt.Time,
+						string(row.Status),
+						row.Store,
+						row.Customer,
+						row.Email,
+						row.Units,
+						helper.Money(row.Total),
+						nullMoney(row.Cogs),
+					})
+					if err != nil {
+						return err
+					}
+
+					before = row.ID
+				}
+
+				if len(rows) < exportBatchSize {
+					return nil
+				}
+			}
+		})
+		return
+	}
+
+	storeID, err := strconv.ParseUint(store, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid store ID", http.StatusBadRequest)
+		return
+	}
+
+	s, err := h.repo.FindStore(r.Context(), storeID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Store not found", http.StatusNotFound)
+		} else {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	columns := []string{"Order number", "Date", "Status", "Store", "Cashier", "Units", "Total", "Cost of sales"}
+
+	writeExport(w, format, "orders-"+s.Slug, columns, func(table helper.TableWriter) error {
+		before := uint64(math.MaxInt64)
+
+		for {
+			rows, err := h.repo.FindStoreOrdersForExport(r.Context(), repository.FindStoreOrdersForExportParams{
+				StoreID:  s.ID,
+				FromDate: from,
+				ToDate:   to,
+				BeforeID: before,
+				Limit:    exportBatchSize,
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, row := range rows {
+				err := table.WriteRow([]interface{}{
+					row.Number,
+					row.CreatedAt.Time,
+					string(row.Status),
+					row.Store,
+					row.Cashier,
+					row.Units,
+					helper.Money(row.Total),
+					nullMoney(row.Cogs),
+				})
+				if err != nil {
+					return err
+				}
+
+				before = row.ID
+			}
+
+			if len(rows) < exportBatchSize {
+				return nil
+			}
+		}
+	})
+}
@@ -0,0 +1,384 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+
+	"api/cmd/middleware"
+	"api/cmd/sms"
+	"api/handler/dto"
+	"api/repository"
+
+	"github.com/go-playground/validator"
+	"github.com/go-sql-driver/mysql"
+)
+
+// Limits on one-time codes. A phone gets a new code at most once a minute and
+// a few times an hour, so the SMS bill cannot be run up, and each code can be
+// guessed only a few times.
+const (
+	otpResendSeconds = 60
+	otpPhoneLimit    = 5
+	otpIPLimit       = 20
+	otpMaxAttempts   = 5
+)
+
+// clientIP is the address a request came from, without the port
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// tooManyRequests asks the client to wait before trying again
+func tooManyRequests(w http.ResponseWriter, msg string, seconds int64) {
+	w.Header().Set("Retry-After", strconv.FormatInt(seconds, 10))
+	http.Error(w, msg, http.StatusTooManyRequests)
+}
+
+// checkPhoneCode checks the code a user typed in against the last code sent to
+// their phone, counting the attempt first so codes cannot be guessed
+func checkPhoneCode(w http.ResponseWriter, r *http.Request, repo *repository.Queries, verifier sms.SMSVerifier, phone, code string) bool {
+	ctx := r.Context()
+
+	otp, err := repo.FindLatestPhoneOtp(ctx, phone)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Request a code first", http.StatusBadRequest)
+		} else {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return false
+	}
+
+	counted, err := repo.AttemptPhoneOtp(ctx, repository.AttemptPhoneOtpParams{
+		ID:       otp.ID,
+		Attempts: otpMaxAttempts,
+	})
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return false
+	}
+
+	if counted == 0 {
+		if otp.Attempts >= otpMaxAttempts {
+			tooManyRequests(w, "Too many attempts, request a new code", max(otpResendSeconds-otp.Age, 0))
+		} else {
+			http.Error(w, "Code expired, request a new one", http.StatusBadRequest)
+		}
+		return false
+	}
+
+	ok, err := verifier.Check(ctx, phone, code)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return false
+	}
+
+	if !ok {
+		http.Error(w, "Invalid code", http.StatusBadRequest)
+		return false
+	}
+
+	if err := repo.ApprovePhoneOtp(ctx, otp.ID); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return false
+	}
+
+	return true
+}
+
+// PhoneStart texts a one-time code to a phone, to sign in, register or add
+// the phone to an account
+func (h *AuthHandler) PhoneStart(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var data dto.PhoneRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(data); err != nil {
+		http.Error(w, validationMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	// The code is recorded before the limits are checked, so requests made
+	// at the same time each see the ones before them and only one is sent
+	ip := clientIP(r)
+
+	id, err := h.repo.InsertPhoneOtp(ctx, repository.InsertPhoneOtpParams{
+		Phone: data.Phone,
+		Ip:    ip,
+	})
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	otpID := uint64(id)
+
+	// discard takes back a code that is not sent
+	discard := func() {
+		if err := h.repo.DeletePhoneOtp(context.WithoutCancel(ctx), otpID); err != nil {
+			fmt.Println(err)
+		}
+	}
+
+	previous, err := h.repo.FindPreviousPhoneOtp(ctx, repository.FindPreviousPhoneOtpParams{
+		Phone: data.Phone,
+		ID:    otpID,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		discard()
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err == nil && previous.Age < otpResendSeconds {
+		discard()
+		tooManyRequests(w, "Wait before requesting another code", otpResendSeconds-previous.Age)
+		return
+	}
+
+	sent, err := h.repo.CountRecentPhoneOtps(ctx, repository.CountRecentPhoneOtpsParams{
+		Phone: data.Phone,
+		ID:    otpID,
+	})
+	if err != nil {
+		discard()
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	fromIP, err := h.repo.CountRecentIPPhoneOtps(ctx, repository.CountRecentIPPhoneOtpsParams{
+		Ip: ip,
+		ID: otpID,
+	})
+	if err != nil {
+		discard()
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if sent >= otpPhoneLimit || fromIP >= otpIPLimit {
+		discard()
+		tooManyRequests(w, "Too many codes requested, try again later", 3600)
+		return
+	}
+
+	if err := h.verifier.Send(ctx, data.Phone); err != nil {
+		discard()
+		fmt.Println(err)
+		http.Error(w, "Failed to send code", http.StatusInternalServerError)
+		return
+	}
+
+	// Codes are only counted for an hour, older ones are not needed
+	if err := h.repo.DeleteOldPhoneOtps(ctx); err != nil {
+		fmt.Println(err)
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// PhoneLogin signs in with a code texted to the phone of an account
+func (h *AuthHandler) PhoneLogin(w http.ResponseWriter, r *http.Request) {
+	var data dto.PhoneLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(data); err != nil {
+		http.Error(w, validationMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	if !checkPhoneCode(w, r, h.repo, h.verifier, data.Phone, data.Code) {
+		return
+	}
+
+	user, err := h.repo.FindUserByPhone(r.Context(), sql.NullString{String: data.Phone, Valid: true})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "No account uses this phone", http.StatusNotFound)
+		} else {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.signIn(w, r, user, data.StoreID, false)
+}
+
+// PhoneRegister creates a customer account with a phone instead of an email
+// and signs it in. The account has no password; it signs in with codes.
+func (h *AuthHandler) PhoneRegister(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var data dto.PhoneRegisterRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(data); err != nil {
+		http.Error(w, validationMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	phone := sql.NullString{String: data.Phone, Valid: true}
+
+	_, err := h.repo.FindUserByPhone(ctx, phone)
+	if err == nil {
+		http.Error(w, "User already exists", http.StatusBadRequest)
+		return
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if !checkPhoneCode(w, r, h.repo, h.verifier, data.Phone, data.Code) {
+		return
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	repo := h.repo.WithTx(tx)
+
+	userID, err := repo.InsertPhoneUser(ctx, repository.InsertPhoneUserParams{
+		Firstname: data.Firstname,
+		Lastname:  data.Lastname,
+		Phone:     phone,
+	})
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+			http.Error(w, "User already exists", http.StatusBadRequest)
+			return
+		}
+
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	role, err := repo.FindRoleByName(ctx, "customer")
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err := repo.AssignUserRole(ctx, repository.AssignUserRoleParams{UserID: uint64(userID), RoleID: role.ID}); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	user, err := repo.FindUserByID(ctx, uint64(userID))
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	h.signIn(w, r, user, nil, false)
+}
+
+// PhoneVerify adds a phone to the account of the signed in user, once they
+// have typed in the code texted to it
+func (h *AuthHandler) PhoneVerify(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.UserID(ctx)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var data dto.PhoneCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(data); err != nil {
+		http.Error(w, validationMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	phone := sql.NullString{String: data.Phone, Valid: true}
+
+	owner, err := h.repo.FindUserByPhone(ctx, phone)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err == nil && owner.ID != userID {
+		http.Error(w, "Phone is used by another account", http.StatusConflict)
+		return
+	}
+
+	if !checkPhoneCode(w, r, h.repo, h.verifier, data.Phone, data.Code) {
+		return
+	}
+
+	if err := h.repo.UpdateUserPhone(ctx, repository.UpdateUserPhoneParams{
+		Phone: phone,
+		ID:    userID,
+	}); err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+			http.Error(w, "Phone is used by another account", http.StatusConflict)
+			return
+		}
+
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -8,8 +8,7 @@ import (
 	"net/http"
 	"strconv"
 
-	"api/cmd/helper"
-	"api/cmd/middleware"
+	"api/cmd/storage"
 	"api/handler/dto"
 	"api/repository"
 
@@ -19,21 +18,16 @@ import (
 )
 
 type productHandler struct {
-	repo *repository.Queries
+	repo  *repository.Queries
+	blobs storage.BlobStore
 }
 
-func NewProductHandler(repo *repository.Queries) *productHandler {
-	return &productHandler{repo: repo}
+func NewProductHandler(repo *repository.Queries, blobs storage.BlobStore) *productHandler {
+	return &productHandler{repo: repo, blobs: blobs}
 }
 
 // Create a new product
 func (h *productHandler) Create(w http.ResponseWriter, r *http.Request) {
-	_, err := middleware.GuardAdmin(r.Context(), h.repo)
-	if err != nil {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
-
 	var form dto.CreateProductRequest
 
 	form.Name = r.FormValue("name")
@@ -96,6 +90,16 @@ func (h *productHandler) Create(w http.ResponseWriter, r *http.Request) {
 		data.Description = sql.NullString{String: r.FormValue("description"), Valid: true}
 	}
 
+	if r.FormValue("price") != "" {
+		price, err := strconv.ParseFloat(r.FormValue("price"), 64)
+		if err != nil || price < 0 {
+			http.Error(w, "Invalid price", http.StatusBadRequest)
+			return
+		}
+
+		data.Price = sql.NullFloat64{Float64: price, Valid: true}
+	}
+
 	id, err := h.repo.InsertProduct(context.Background(), data)
 	if err != nil {
 		fmt.Println(err)
@@ -111,31 +115,21 @@ func (h *productHandler) Create(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		filename, err := helper.UploadImage(f, file.Header)
-		if err != nil {
-			fmt.Println(err)
-			http.Error(w, "Something went wrong", http.StatusInternalServerError)
-			return
-		}
-
-		imageID, err := h.repo.InsertImage(context.Background(), *filename)
+		imageID, err := saveImage(r.Context(), h.repo, h.blobs, f)
 		if err != nil {
-			fmt.Println(err)
-			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			imageError(w, err)
 			return
 		}
 
 		err = h.repo.AssignProductImage(context.Background(), repository.AssignProductImageParams{
 			ProductID: uint64(id),
-			ImageID:   uint64(imageID),
+			ImageID:   imageID,
 		})
 		if err != nil {
 			fmt.Println(err)
 			http.Error(w, "Something went wrong", http.StatusInternalServerError)
 			return
 		}
-
-		f.Close()
 	}
 
 	product, err := h.repo.FindProduct(context.Background(), uint64(id))
@@ -180,6 +174,10 @@ func (h *productHandler) Create(w http.ResponseWriter, r *http.Request) {
 		response.CategoryID = product.CategoryID.Int64
 	}
 
+	if product.Price.Valid {
+		response.Price = &product.Price.Float64
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(response)
@@ -189,13 +187,6 @@ func (h *productHandler) AdminFindAll(w http.ResponseWriter, r *http.Request) {
 
 	ctx := context.Background()
 
-	_, err := middleware.GuardAdmin(r.Context(), h.repo)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		w.Write([]byte("Unauthorised"))
-		return
-	}
-
 	// Parse query parameters for pagination
 	limitStr := r.URL.Query().Get("limit")
 	offsetStr := r.URL.Query().Get("offset")
@@ -262,6 +253,10 @@ func (h *productHandler) AdminFindAll(w http.ResponseWriter, r *http.Request) {
 			p.CategoryID = product.CategoryID.Int64
 		}
 
+		if product.Price.Valid {
+			p.Price = &product.Price.Float64
+		}
+
 		products = append(products, p)
 	}
 
@@ -280,13 +275,6 @@ func (h *productHandler) AdminFindAll(w http.ResponseWriter, r *http.Request) {
 func (h *productHandler) AdminFindOne(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
 
-	_, err := middleware.GuardAdmin(r.Context(), h.repo)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		w.Write([]byte("Unauthorised"))
-		return
-	}
-
 	// Get the category ID from URL parameters
 	productIDStr := chi.URLParam(r, "id")
 	fmt.Println(productIDStr)
@@ -340,6 +328,10 @@ func (h *productHandler) AdminFindOne(w http.ResponseWriter, r *http.Request) {
 		response.CategoryID = product.CategoryID.Int64
 	}
 
+	if product.Price.Valid {
+		response.Price = &product.Price.Float64
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
@@ -347,13 +339,6 @@ func (h *productHandler) AdminFindOne(w http.ResponseWriter, r *http.Request) {
 func (h *productHandler) AdminUpdate(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
 
-	_, err := middleware.GuardAdmin(r.Context(), h.repo)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		w.Write([]byte("Unauthorised"))
-		return
-	}
-
 	// Get the category ID from URL parameters
 	productIDStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseUint(productIDStr, 10, 64)
@@ -432,6 +417,16 @@ func (h *productHandler) AdminUpdate(w http.ResponseWriter, r *http.Request) {
 		data.Description = sql.NullString{String: r.FormValue("description"), Valid: true}
 	}
 
+	if r.FormValue("price") != "" {
+		price, err := strconv.ParseFloat(r.FormValue("price"), 64)
+		if err != nil || price < 0 {
+			http.Error(w, "Invalid price", http.StatusBadRequest)
+			return
+		}
+
+		data.Price = sql.NullFloat64{Float64: price, Valid: true}
+	}
+
 	// Update category details in the database
 	err = h.repo.UpdateProduct(ctx, data)
 	if err != nil {
@@ -449,31 +444,21 @@ func (h *productHandler) AdminUpdate(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		filename, err := helper.UploadImage(f, file.Header)
-		if err != nil {
-			fmt.Println(err)
-			http.Error(w, "Something went wrong", http.StatusInternalServerError)
-			return
-		}
-
-		imageID, err := h.repo.InsertImage(context.Background(), *filename)
+		imageID, err := saveImage(r.Context(), h.repo, h.blobs, f)
 		if err != nil {
-			fmt.Println(err)
-			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			imageError(w, err)
 			return
 		}
 
 		err = h.repo.AssignProductImage(context.Background(), repository.AssignProductImageParams{
 			ProductID: uint64(id),
-			ImageID:   uint64(imageID),
+			ImageID:   imageID,
 		})
 		if err != nil {
 			fmt.Println(err)
 			http.Error(w, "Something went wrong", http.StatusInternalServerError)
 			return
 		}
-
-		f.Close()
 	}
 
 	result, err := h.repo.FindProduct(context.Background(), product.ID)
@@ -518,6 +503,10 @@ func (h *productHandler) AdminUpdate(w http.ResponseWriter, r *http.Request) {
 		response.CategoryID = result.CategoryID.Int64
 	}
 
+	if result.Price.Valid {
+		response.Price = &result.Price.Float64
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
@@ -525,13 +514,6 @@ func (h *productHandler) AdminUpdate(w http.ResponseWriter, r *http.Request) {
 func (h *productHandler) AdminDelete(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
 
-	_, err := middleware.GuardAdmin(r.Context(), h.repo)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		w.Write([]byte("Unauthorised"))
-		return
-	}
-
 	// Get the category ID from URL parameters
 	productIDStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseUint(productIDStr, 10, 64)
@@ -566,13 +548,6 @@ func (h *productHandler) AdminDelete(w http.ResponseWriter, r *http.Request) {
 func (h *productHandler) CustomerFindOne(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
 
-	_, err := middleware.GuardCustomer(r.Context(), h.repo)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		w.Write([]byte("Unauthorised"))
-		return
-	}
-
 	// Get the category ID from URL parameters
 	sku := chi.URLParam(r, "sku")
 
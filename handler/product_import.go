@@ -0,0 +1,466 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"api/cmd/helper"
+	"api/cmd/middleware"
+	"api/handler/dto"
+	"api/repository"
+
+	"github.com/gosimple/slug"
+)
+
+const productImportKind = "products"
+
+var productColumns = []string{"name", "sku", "description", "category", "status", "visibility", "price", "images"}
+
+type productImportRow struct {
+	row         int
+	name        string
+	sku         string
+	description string
+	category    sql.NullInt64
+	status      sql.NullBool
+	visibility  sql.NullBool
+	price       sql.NullFloat64
+	images      []string
+}
+
+// parseProductRows validates every row of an import table. Rows with errors are
+// left out of the returned rows.
+func (h *productHandler) parseProductRows(ctx context.Context, columns map[string]int, table [][]string) ([]productImportRow, []dto.ImportRowError, error) {
+	var err error
+	var rows []productImportRow
+	errors := []dto.ImportRowError{}
+
+	skus := make(map[string]int)
+	slugs := make(map[string]int)
+	categories := make(map[string]sql.NullInt64)
+
+	for i, record := range table[1:] {
+		// Row numbers match the spreadsheet, the header being row 1
+		line := i + 2
+		valid := true
+
+		fail := func(field, message string) {
+			errors = append(errors, dto.ImportRowError{Row: line, Field: field, Message: message})
+			valid = false
+		}
+
+		row := productImportRow{
+			row:         line,
+			name:        tableCell(record, columns, "name"),
+			sku:         tableCell(record, columns, "sku"),
+			description: tableCell(record, columns, "description"),
+		}
+
+		if row.name == "" {
+			fail("name", "name is a required field")
+		}
+
+		if row.sku == "" {
+			fail("sku", "sku is a required field")
+		} else if first, ok := skus[row.sku]; ok {
+			fail("sku", fmt.Sprintf("sku is repeated from row %d", first))
+		} else {
+			skus[row.sku] = line
+		}
+
+		if row.name != "" {
+			productSlug := slug.Make(row.name)
+
+			if first, ok := slugs[productSlug]; ok {
+				fail("name", fmt.Sprintf("name is repeated from row %d", first))
+			} else {
+				slugs[productSlug] = line
+
+				existing, err := h.repo.FindProductBySlug(ctx, productSlug)
+				if err == nil && existing.Sku != row.sku {
+					fail("name", "Product with this name already exists")
+				} else if err != nil && err != sql.ErrNoRows {
+					return nil, nil, err
+				}
+			}
+		}
+
+		if row.status, err = parseImportBool(tableCell(record, columns, "status")); err != nil {
+			fail("status", "status "+err.Error())
+		}
+
+		if row.visibility, err = parseImportBool(tableCell(record, columns, "visibility")); err != nil {
+			fail("visibility", "visibility "+err.Error())
+		}
+
+		if value := tableCell(record, columns, "price"); value != "" {
+			price, err := strconv.ParseFloat(value, 64)
+			if err != nil || price < 0 {
+				fail("price", "price should be a positive number")
+			} else {
+				row.price = sql.NullFloat64{Float64: price, Valid: true}
+			}
+		}
+
+		if value := tableCell(record, columns, "category"); value != "" {
+			category, ok := categories[value]
+			if !ok {
+				found, err := h.repo.FindCategoryBySlug(ctx, value)
+				if err != nil && err != sql.ErrNoRows {
+					return nil, nil, err
+				}
+
+				category = sql.NullInt64{Int64: int64(found.ID), Valid: err == nil}
+				categories[value] = category
+			}
+
+			if !category.Valid {
+				fail("category", "Category not found")
+			}
+			row.category = category
+		}
+
+		for _, image := range strings.Split(tableCell(record, columns, "images"), "|") {
+			image = strings.TrimSpace(image)
+			if image == "" {
+				continue
+			}
+
+			if !isImageURL(image) {
+				_, err := h.repo.FindImageByName(ctx, image)
+				if err == sql.ErrNoRows {
+					fail("images", fmt.Sprintf("Image %s not found", image))
+					continue
+				} else if err != nil {
+					return nil, nil, err
+				}
+			}
+
+			row.images = append(row.images, image)
+		}
+
+		if valid {
+			rows = append(rows, row)
+		}
+	}
+
+	return rows, errors, nil
+}
+
+func isImageURL(image string) bool {
+	return strings.HasPrefix(image, "http://") || strings.HasPrefix(image, "https://")
+}
+
+// AdminImport creates or updates products, matched by SKU, from a CSV or XLSX file.
+// With dry_run=true the file is only validated, otherwise it is imported in the
+// background and the import job is returned.
+func (h *productHandler) AdminImport(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.UserID(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+
+	table, err := readImportTable(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	columns, err := tableColumns(table[0], "name", "sku")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rows, errors, err := h.parseProductRows(r.Context(), columns, table)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	report := dto.ImportReport{
+		DryRun: dryRun,
+		Total:  len(table) - 1,
+		Valid:  len(rows),
+		Errors: errors,
+	}
+
+	if dryRun || len(rows) == 0 {
+		status := http.StatusOK
+		if !dryRun {
+			status = http.StatusUnprocessableEntity
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(report)
+		return
+	}
+
+	job, err := startImportJob(r.Context(), h.repo, productImportKind, len(rows), userID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	go h.runProductImport(job.ID, rows, errors)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(importJobResponse(job))
+}
+
+func (h *productHandler) runProductImport(jobID uint64, rows []productImportRow, errors []dto.ImportRowError) {
+	ctx := context.Background()
+
+	status := repository.ImportJobsStatusCompleted
+	defer func() {
+		if p := recover(); p != nil {
+			log.Printf("product import %d failed: %v", jobID, p)
+			status = repository.ImportJobsStatusFailed
+		}
+
+		finishImportJob(ctx, h.repo, jobID, status, errors)
+	}()
+
+	err := h.repo.UpdateImportJobStatus(ctx, repository.UpdateImportJobStatusParams{
+		Status: repository.ImportJobsStatusRunning,
+		ID:     jobID,
+	})
+	if err != nil {
+		log.Printf("product import %d failed: %v", jobID, err)
+		status = repository.ImportJobsStatusFailed
+		return
+	}
+
+	var processed, inserted, updated int32
+
+	for _, row := range rows {
+		created, warnings, err := h.importProduct(ctx, row)
+		if err != nil {
+			errors = append(errors, dto.ImportRowError{Row: row.row, Message: err.Error()})
+		} else if created {
+			inserted++
+		} else {
+			updated++
+		}
+
+		for _, warning := range warnings {
+			errors = append(errors, dto.ImportRowError{Row: row.row, Field: "images", Message: warning})
+		}
+
+		processed++
+
+		if processed%importProgressEvery == 0 || int(processed) == len(rows) {
+			err := h.repo.UpdateImportJobProgress(ctx, repository.UpdateImportJobProgressParams{
+				Processed: processed,
+				Inserted:  inserted,
+				Updated:   updated,
+				ID:        jobID,
+			})
+			if err != nil {
+				log.Printf("product import %d: %v", jobID, err)
+			}
+		}
+	}
+}
+
+// importedProductUpdate changes an existing product with the cells a row has.
+// Columns left out of the file, or left empty, keep what is stored.
+func importedProductUpdate(existing repository.Product, row productImportRow) repository.UpdateProductParams {
+	update := repository.UpdateProductParams{
+		Name:        row.name,
+		Slug:        slug.Make(row.name),
+		Description: existing.Description,
+		Sku:         row.sku,
+		CategoryID:  existing.CategoryID,
+		Status:      existing.Status,
+		Visibility:  existing.Visibility,
+		Price:       existing.Price,
+		ID:          existing.ID,
+	}
+
+	if row.description != "" {
+		update.Description = sql.NullString{String: row.description, Valid: true}
+	}
+
+	if row.category.Valid {
+		update.CategoryID = row.category
+	}
+
+	if row.status.Valid {
+		update.Status = row.status.Bool
+	}
+
+	if row.visibility.Valid {
+		update.Visibility = row.visibility.Bool
+	}
+
+	if row.price.Valid {
+		update.Price = row.price
+	}
+
+	return update
+}
+
+// importProduct writes a single row and reports whether a new product was
+// created. Images that cannot be added do not undo the product, they are
+// reported as warnings.
+func (h *productHandler) importProduct(ctx context.Context, row productImportRow) (bool, []string, error) {
+	description := sql.NullString{String: row.description, Valid: row.description != ""}
+
+	var productID uint64
+	created := false
+
+	existing, err := h.repo.FindProductBySKU(ctx, row.sku)
+	switch err {
+	case nil:
+		productID = existing.ID
+
+		err = h.repo.UpdateProduct(ctx, importedProductUpdate(existing, row))
+		if err != nil {
+			return false, nil, fmt.Errorf("Unable to update product")
+		}
+	case sql.ErrNoRows:
+		id, err := h.repo.InsertProduct(ctx, repository.InsertProductParams{
+			Slug:        slug.Make(row.name),
+			Name:        row.name,
+			Description: description,
+			Sku:         row.sku,
+			CategoryID:  row.category,
+			Status:      row.status.Bool,
+			Visibility:  row.visibility.Bool,
+			Price:       row.price,
+		})
+		if err != nil {
+			return false, nil, fmt.Errorf("Unable to create product")
+		}
+
+		productID = uint64(id)
+		created = true
+	default:
+		return false, nil, fmt.Errorf("Unable to find product")
+	}
+
+	var warnings []string
+
+	for _, image := range row.images {
+		imageID, err := h.importImage(ctx, image)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("Product saved without image %s: %v", image, err))
+			continue
+		}
+
+		err = h.repo.AssignProductImage(ctx, repository.AssignProductImageParams{
+			ProductID: productID,
+			ImageID:   imageID,
+		})
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("Product saved without image %s", image))
+		}
+	}
+
+	return created, warnings, nil
+}
+
+// importImage returns the images row of an existing filename, or downloads and saves a URL
+func (h *productHandler) importImage(ctx context.Context, image string) (uint64, error) {
+	if !isImageURL(image) {
+		found, err := h.repo.FindImageByName(ctx, image)
+		if err != nil {
+			return 0, err
+		}
+
+		found, err = touchImage(ctx, h.repo, found.ID)
+		if err != nil {
+			return 0, err
+		}
+
+		return found.ID, nil
+	}
+
+	body, err := helper.FetchImage(ctx, image)
+	if err != nil {
+		return 0, err
+	}
+
+	return saveImage(ctx, h.repo, h.blobs, body)
+}
+
+// AdminFindImport returns the progress of a product import
+func (h *productHandler) AdminFindImport(w http.ResponseWriter, r *http.Request) {
+	writeImportJob(w, r, h.repo, productImportKind)
+}
+
+// AdminExport streams every product as CSV or XLSX in the import format
+func (h *productHandler) AdminExport(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = helper.FormatCSV
+	}
+
+	if format != helper.FormatCSV && format != helper.FormatXLSX {
+		http.Error(w, "Invalid format", http.StatusBadRequest)
+		return
+	}
+
+	products, err := h.repo.FindProductsForExport(r.Context())
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", helper.TableContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"products-%s.%s\"", time.Now().Format("20060102"), format))
+
+	table, err := helper.NewTableWriter(w, format)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err := table.WriteHeader(productColumns); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	for _, product := range products {
+		var price interface{}
+		if product.Price.Valid {
+			price = product.Price.Float64
+		}
+
+		err := table.WriteRow([]interface{}{
+			product.Name,
+			product.Sku,
+			product.Description.String,
+			product.CategorySlug.String,
+			product.Status,
+			product.Visibility,
+			price,
+			product.Images.String,
+		})
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+
+	if err := table.Close(); err != nil {
+		fmt.Println(err)
+	}
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"database/sql"
+	"testing"
+
+	"api/repository"
+)
+
+func TestImportedProductUpdate(t *testing.T) {
+	existing := repository.Product{
+		ID:          7,
+		Slug:        "blue-mug",
+		Name:        "Blue mug",
+		Description: sql.NullString{String: "A blue mug", Valid: true},
+		Sku:         "MUG-1",
+		CategoryID:  sql.NullInt64{Int64: 3, Valid: true},
+		Status:      true,
+		Visibility:  true,
+		Price:       sql.NullFloat64{Float64: 4.5, Valid: true},
+	}
+
+	tests := []struct {
+		name string
+		row  productImportRow
+		want repository.UpdateProductParams
+	}{
+		{
+			name: "only name and sku keep the rest",
+			row:  productImportRow{name: "Big blue mug", sku: "MUG-1"},
+			want: repository.UpdateProductParams{
+				Name:        "Big blue mug",
+				Slug:        "big-blue-mug",
+				Description: existing.Description,
+				Sku:         "MUG-1",
+				CategoryID:  existing.CategoryID,
+				Status:      true,
+				Visibility:  true,
+				Price:       existing.Price,
+				ID:          7,
+			},
+		},
+		{
+			name: "supplied cells override",
+			row: productImportRow{
+				name:        "Blue mug",
+				sku:         "MUG-1",
+				description: "Now bigger",
+				category:    sql.NullInt64{Int64: 9, Valid: true},
+				status:      sql.NullBool{Bool: false, Valid: true},
+				visibility:  sql.NullBool{Bool: false, Valid: true},
+				price:       sql.NullFloat64{Float64: 6, Valid: true},
+			},
+			want: repository.UpdateProductParams{
+				Name:        "Blue mug",
+				Slug:        "blue-mug",
+				Description: sql.NullString{String: "Now bigger", Valid: true},
+				Sku:         "MUG-1",
+				CategoryID:  sql.NullInt64{Int64: 9, Valid: true},
+				Status:      false,
+				Visibility:  false,
+				Price:       sql.NullFloat64{Float64: 6, Valid: true},
+				ID:          7,
+			},
+		},
+		{
+			name: "status alone leaves visibility",
+			row: productImportRow{
+				name:   "Blue mug",
+				sku:    "MUG-1",
+				status: sql.NullBool{Bool: false, Valid: true},
+			},
+			want: repository.UpdateProductParams{
+				Name:        "Blue mug",
+				Slug:        "blue-mug",
+				Description: existing.Description,
+				Sku:         "MUG-1",
+				CategoryID:  existing.CategoryID,
+				Status:      false,
+				Visibility:  true,
+				Price:       existing.Price,
+				ID:          7,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := importedProductUpdate(existing, tt.row); got != tt.want {
+				t.Errorf("importedProductUpdate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseImportBool(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    sql.NullBool
+		wantErr bool
+	}{
+		{value: "", want: sql.NullBool{}},
+		{value: "yes", want: sql.NullBool{Bool: true, Valid: true}},
+		{value: "FALSE", want: sql.NullBool{Bool: false, Valid: true}},
+		{value: "0", want: sql.NullBool{Bool: false, Valid: true}},
+		{value: "maybe", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseImportBool(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseImportBool(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("parseImportBool(%q) = %+v, want %+v", tt.value, got, tt.want)
+		}
+	}
+}
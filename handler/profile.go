@@ -4,26 +4,38 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
 	"api/cmd/middleware"
+	"api/cmd/revocation"
+	"api/cmd/sms"
+	"api/cmd/throttle"
 	"api/handler/dto"
 	"api/repository"
+
+	"github.com/go-playground/validator"
+	"github.com/go-sql-driver/mysql"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type profileHandler struct {
-	repo *repository.Queries
+	db          *sql.DB
+	repo        *repository.Queries
+	revocations revocation.Store
+	attempts    throttle.Store
+	verifier    sms.SMSVerifier
 }
 
-func NewProfileHandler(repo *repository.Queries) *profileHandler {
-	return &profileHandler{repo: repo}
+func NewProfileHandler(db *sql.DB, repo *repository.Queries, revocations revocation.Store, attempts throttle.Store, verifier sms.SMSVerifier) *profileHandler {
+	return &profileHandler{db: db, repo: repo, revocations: revocations, attempts: attempts, verifier: verifier}
 }
 
 func (h *profileHandler) CashierProfile(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
 
-	id, err := middleware.GuardCashier(r.Context(), h.repo)
+	id, err := middleware.UserID(r.Context())
 	if err != nil {
 		w.WriteHeader(http.StatusUnauthorized)
 		w.Write([]byte("Unauthorized"))
@@ -41,14 +53,21 @@ func (h *profileHandler) CashierProfile(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	store, err := h.repo.FindUserStore(ctx, user.ID)
+	stores, err := h.repo.FindUserStores(ctx, user.ID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			http.Error(w, "You are not assigned to any store", http.StatusNotFound)
-		} else {
-			fmt.Println(err)
-			http.Error(w, "Something went wrong", http.StatusInternalServerError)
-		}
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if len(stores) == 0 {
+		http.Error(w, "You are not assigned to any store", http.StatusNotFound)
+		return
+	}
+
+	selected, picked, err := middleware.SelectedStore(r)
+	if err != nil {
+		http.Error(w, "Invalid store ID", http.StatusBadRequest)
 		return
 	}
 
@@ -57,17 +76,350 @@ func (h *profileHandler) CashierProfile(w http.ResponseWriter, r *http.Request)
 			ID:        user.ID,
 			Firstname: user.Firstname,
 			Lastname:  user.Lastname,
-			Email:     user.Email,
+			Email:     user.Email.String,
 			//Phone:     user.Phone.String,
 		},
-		Store: dto.StoreResponse{
-			ID:     store.ID,
-			Slug:   store.Slug,
-			Name:   store.Name,
-			Status: store.Status,
-		},
+		Stores: []dto.UserStoreResponse{},
+	}
+
+	for _, store := range stores {
+		response.Stores = append(response.Stores, dto.UserStoreResponse{
+			ID:   store.ID,
+			Name: store.Name,
+			Role: store.Role,
+		})
+
+		// The store is only known when one was picked or there is no choice
+		if (picked && store.ID == selected) || (!picked && len(stores) == 1) {
+			response.Store = &dto.StoreResponse{
+				ID:     store.ID,
+				Slug:   store.Slug,
+				Name:   store.Name,
+				Status: store.Status,
+			}
+		}
+	}
+
+	if picked && response.Store == nil {
+		http.Error(w, "You are not assigned to this store", http.StatusForbidden)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
+
+// currentUser finds the signed in user
+func (h *profileHandler) currentUser(w http.ResponseWriter, r *http.Request) (repository.User, bool) {
+	id, err := middleware.UserID(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return repository.User{}, false
+	}
+
+	user, err := h.repo.FindUserByID(r.Context(), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		} else {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return user, false
+	}
+
+	return user, true
+}
+
+func customerProfileResponse(user repository.User) dto.CustomerProfileResponse {
+	response := dto.CustomerProfileResponse{
+		ID:            user.ID,
+		Firstname:     user.Firstname,
+		Lastname:      user.Lastname,
+		EmailVerified: user.EmailVerifiedAt.Valid,
+		PhoneVerified: user.PhoneVerifiedAt.Valid,
+	}
+
+	if user.Email.Valid {
+		response.Email = &user.Email.String
+	}
+
+	if user.Phone.Valid {
+		response.Phone = &user.Phone.String
+	}
+
+	return response
+}
+
+func (h *profileHandler) CustomerProfile(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(customerProfileResponse(user))
+}
+
+// CustomerUpdateProfile changes the name and phone of the signed in customer.
+// A new phone has to be verified again.
+func (h *profileHandler) CustomerUpdateProfile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	var form dto.UpdateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(form); err != nil {
+		http.Error(w, validationMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	var phone sql.NullString
+	if form.Phone != nil {
+		phone = sql.NullString{String: *form.Phone, Valid: true}
+	}
+
+	// Customers who registered with their phone sign in with it
+	if !phone.Valid && !user.Email.Valid {
+		http.Error(w, "Phone is required for accounts without an email", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.UpdateUser(ctx, repository.UpdateUserParams{
+		Firstname: form.Firstname,
+		Lastname:  form.Lastname,
+		Phone:     phone,
+		ID:        user.ID,
+	}); err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+			http.Error(w, "Phone is used by another account", http.StatusConflict)
+			return
+		}
+
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	user, err := h.repo.FindUserByID(ctx, user.ID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(customerProfileResponse(user))
+}
+
+// CustomerChangePassword sets a new password once the current one is given.
+// Accounts registered with a phone have none yet, so they can set one
+// straight away. Every session is ended, like after a password reset.
+func (h *profileHandler) CustomerChangePassword(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	var form dto.ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(form); err != nil {
+		http.Error(w, validationMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	if !reauthAttempt(w, r, h.attempts, user.ID) {
+		return
+	}
+
+	if user.Password != "" {
+		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(form.CurrentPassword)); err != nil {
+			http.Error(w, "Invalid password", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	reauthSucceeded(ctx, h.attempts, user.ID)
+
+	password, err := bcrypt.GenerateFromPassword([]byte(form.Password), bcrypt.DefaultCost)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.repo.UpdateUserPassword(ctx, repository.UpdateUserPasswordParams{
+		Password: string(password),
+		ID:       user.ID,
+	}); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err := revokeUserSessions(ctx, h.repo, h.revocations, user.ID); err != nil {
+		fmt.Println(err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CustomerDelete deletes the account of the signed in customer. The user is
+// kept so their orders stay whole for the books, but everything that tells who
+// they are is erased: name, email, phone, password, addresses, two-factor
+// secrets and the addresses they signed in from. It cannot be undone.
+func (h *profileHandler) CustomerDelete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	var form dto.DeleteAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if !reauthAttempt(w, r, h.attempts, user.ID) {
+		return
+	}
+
+	// Accounts without a password confirm with a code texted to their phone,
+	// so a session alone is not enough to delete them
+	switch {
+	case user.Password != "":
+		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(form.Password)); err != nil {
+			http.Error(w, "Invalid password", http.StatusUnauthorized)
+			return
+		}
+	case user.Phone.Valid:
+		if form.PhoneCode == "" {
+			http.Error(w, "Confirm with a code sent to your phone", http.StatusBadRequest)
+			return
+		}
+
+		if !checkPhoneCode(w, r, h.repo, h.verifier, user.Phone.String, form.PhoneCode) {
+			return
+		}
+	default:
+		http.Error(w, "Unable to confirm the account", http.StatusForbidden)
+		return
+	}
+
+	mfa, err := h.repo.FindUserMfa(ctx, user.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err == nil && mfa.EnabledAt.Valid {
+		ok, err := checkSecondFactor(ctx, h.repo, user.ID, form.Code, form.RecoveryCode)
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		if !ok {
+			http.Error(w, "Invalid code", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	reauthSucceeded(ctx, h.attempts, user.ID)
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	repo := h.repo.WithTx(tx)
+
+	if user.Phone.Valid {
+		if err := repo.DeletePhoneOtps(ctx, user.Phone.String); err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// Orders still on their way keep where they go, the rest forget it
+	if err := repo.AnonymiseOnlineOrderAddresses(ctx, sql.NullInt64{Int64: int64(user.ID), Valid: true}); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err := repo.DeleteUserAddresses(ctx, user.ID); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err := repo.DeleteRecoveryCodes(ctx, user.ID); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err := repo.DeleteUserMfa(ctx, user.ID); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	// Sessions are deleted, not only revoked, as they keep the browser
+	// each was started from
+	if err := repo.DeleteUserRefreshTokens(ctx, user.ID); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err := repo.AnonymiseUserLoginAudits(ctx, sql.NullInt64{Int64: int64(user.ID), Valid: true}); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err := repo.AnonymiseUser(ctx, user.ID); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err := revokeUserSessions(ctx, h.repo, h.revocations, user.ID); err != nil {
+		fmt.Println(err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -4,11 +4,15 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"strconv"
 	"time"
 
+	"api/cmd/costing"
+	"api/cmd/helper"
 	"api/cmd/middleware"
 	"api/handler/dto"
 	"api/repository"
@@ -18,18 +22,20 @@ import (
 )
 
 type purchaseHandler struct {
-	repo *repository.Queries
+	db    *sql.DB
+	repo  *repository.Queries
+	costs *costing.Engine
 }
 
-func NewPurchaseHandler(repo *repository.Queries) *purchaseHandler {
-	return &purchaseHandler{repo: repo}
+func NewPurchaseHandler(db *sql.DB, repo *repository.Queries, costs *costing.Engine) *purchaseHandler {
+	return &purchaseHandler{db: db, repo: repo, costs: costs}
 }
 
 // Create a new purchase
 func (h *purchaseHandler) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
 
-	userID, err := middleware.GuardAdmin(r.Context(), h.repo)
+	userID, err := middleware.UserID(r.Context())
 	if err != nil {
 		w.WriteHeader(http.StatusUnauthorized)
 		w.Write([]byte("Unauthorized"))
@@ -61,6 +67,10 @@ func (h *purchaseHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !checkStore(w, r.Context(), form.StoreID) {
+		return
+	}
+
 	p, err := h.repo.FindProduct(ctx, form.ProductID)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -107,13 +117,36 @@ func (h *purchaseHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 	data.StoreID = sql.NullInt64{Int64: int64(s.ID), Valid: true}
 
-	purchaseID, err := h.repo.InsertPurchase(ctx, data)
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	repo := h.repo.WithTx(tx)
+
+	purchaseID, err := repo.InsertPurchase(ctx, data)
 	if err != nil {
 		fmt.Println(err)
 		http.Error(w, "Failed to create purchase", http.StatusInternalServerError)
 		return
 	}
 
+	err = h.costs.Receive(ctx, repo, uint64(purchaseID), p.ID, data.StoreID, data.Quantity, data.OrderPrice)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to create purchase", http.StatusInternalServerError)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
 	purchase, err := h.repo.FindPurchase(ctx, uint64(purchaseID))
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -174,10 +207,14 @@ func (h *purchaseHandler) Create(w http.ResponseWriter, r *http.Request) {
 func (h *purchaseHandler) AdminFindAll(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
 
-	_, err := middleware.GuardAdmin(r.Context(), h.repo)
+	format, err := exportFormat(r)
 	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		w.Write([]byte("Unauthorized"))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if format != "" {
+		h.export(w, r, format)
 		return
 	}
 
@@ -193,13 +230,14 @@ func (h *purchaseHandler) AdminFindAll(w http.ResponseWriter, r *http.Request) {
 		offset = 0
 	}
 
-	count, err := h.repo.CountPurchases(ctx)
+	count, err := h.repo.CountPurchases(ctx, storeFilter(r.Context()))
 	if err != nil {
 		http.Error(w, "Failed to count purchases", http.StatusInternalServerError)
 		return
 	}
 
 	data, err := h.repo.FindPurchases(ctx, repository.FindPurchasesParams{
+		Stores: storeFilter(r.Context()),
 		Limit:  int32(limit),
 		Offset: int32(offset),
 	})
@@ -285,20 +323,23 @@ func (h *purchaseHandler) AdminDelete(w http.ResponseWriter, r *http.Request) {
 
 	ctx := context.Background()
 
-	_, err := middleware.GuardAdmin(r.Context(), h.repo)
+	purchaseID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
 	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		w.Write([]byte("Unauthorized"))
+		http.Error(w, "Invalid purchase ID", http.StatusBadRequest)
 		return
 	}
 
-	purchaseID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	tx, err := h.db.BeginTx(ctx, nil)
 	if err != nil {
-		http.Error(w, "Invalid purchase ID", http.StatusBadRequest)
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
 		return
 	}
+	defer tx.Rollback()
+
+	repo := h.repo.WithTx(tx)
 
-	_, err = h.repo.FindPurchase(ctx, purchaseID)
+	purchase, err := repo.LockCostLayer(ctx, purchaseID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			http.Error(w, "Purchase not found", http.StatusNotFound)
@@ -309,11 +350,95 @@ func (h *purchaseHandler) AdminDelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.repo.DeletePurchase(ctx, purchaseID)
+	if !canSeeStore(r.Context(), purchase.StoreID) {
+		http.Error(w, "Purchase not found", http.StatusNotFound)
+		return
+	}
+
+	// The stock it added goes again, unless some of it was sold already
+	if err := h.costs.Remove(ctx, repo, purchase); err != nil {
+		if errors.Is(err, costing.ErrLayerConsumed) {
+			http.Error(w, "Purchase has been partly sold and cannot be deleted", http.StatusConflict)
+		} else {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// A purchase of a goods-received note is also taken out of the note and
+	// its purchase order
+	if purchase.GoodsReceivedNoteID.Valid {
+		if err := removeGoodsReceived(ctx, repo, purchase); err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	err = repo.DeletePurchase(ctx, purchaseID)
 	if err != nil {
 		http.Error(w, "Failed to delete purchase", http.StatusInternalServerError)
 		return
 	}
 
+	if err := tx.Commit(); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// export streams purchases as a file, newest first, in batches
+func (h *purchaseHandler) export(w http.ResponseWriter, r *http.Request, format string) {
+	from, to, err := exportRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	columns := []string{"Date", "SKU", "Product", "Store", "Supplier", "Invoice number", "Quantity", "Unit cost", "Selling price", "Total cost"}
+
+	writeExport(w, format, "purchases", columns, func(table helper.TableWriter) error {
+		before := uint64(math.MaxInt64)
+
+		for {
+			rows, err := h.repo.FindPurchasesForExport(r.Context(), repository.FindPurchasesForExportParams{
+				FromDate: from,
+				ToDate:   to,
+				BeforeID: before,
+				Stores:   storeFilter(r.Context()),
+				Limit:    exportBatchSize,
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, row := range rows {
+				err := table.WriteRow([]interface{}{
+					row.Date,
+					row.Sku,
+					row.Name,
+					row.Store,
+					row.Supplier,
+					row.InvoiceNumber,
+					row.Quantity,
+					helper.Money(row.OrderPrice),
+					helper.Money(row.SellingPrice),
+					helper.Money(float64(row.Quantity) * row.OrderPrice),
+				})
+				if err != nil {
+					return err
+				}
+
+				before = row.ID
+			}
+
+			if len(rows) < exportBatchSize {
+				return nil
+			}
+		}
+	})
+}
@@ -0,0 +1,775 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"api/cmd/costing"
+	"api/cmd/helper"
+	"api/cmd/middleware"
+	"api/handler/dto"
+	"api/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator"
+)
+
+type purchaseOrderHandler struct {
+	db    *sql.DB
+	repo  *repository.Queries
+	costs *costing.Engine
+}
+
+func NewPurchaseOrderHandler(db *sql.DB, repo *repository.Queries, costs *costing.Engine) *purchaseOrderHandler {
+	return &purchaseOrderHandler{db: db, repo: repo, costs: costs}
+}
+
+// readPurchaseOrderForm decodes and validates a purchase order and parses its expected date
+func readPurchaseOrderForm(r *http.Request) (dto.CreatePurchaseOrderRequest, sql.NullTime, string) {
+	var form dto.CreatePurchaseOrderRequest
+	var expectedAt sql.NullTime
+
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		return form, expectedAt, "Invalid request body"
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(form); err != nil {
+		return form, expectedAt, validationMessage(err)
+	}
+
+	if form.ExpectedAt != "" {
+		date, err := time.Parse(time.RFC3339, form.ExpectedAt)
+		if err != nil {
+			return form, expectedAt, "Error parsing time"
+		}
+
+		expectedAt = sql.NullTime{Time: date, Valid: true}
+	}
+
+	return form, expectedAt, ""
+}
+
+// insertPurchaseOrderItems checks the supplier, store and products of a purchase
+// order and writes its items. It returns a message for the client, if any.
+func insertPurchaseOrderItems(ctx context.Context, repo *repository.Queries, purchaseOrderID uint64, items []dto.PurchaseOrderItemRequest) (string, error) {
+	seen := make(map[uint64]bool, len(items))
+
+	for _, item := range items {
+		if seen[item.ProductID] {
+			return fmt.Sprintf("Product %d is listed more than once", item.ProductID), nil
+		}
+		seen[item.ProductID] = true
+
+		_, err := repo.FindProduct(ctx, item.ProductID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Sprintf("Product %d not found", item.ProductID), nil
+			}
+			return "", err
+		}
+
+		err = repo.InsertPurchaseOrderItem(ctx, repository.InsertPurchaseOrderItemParams{
+			PurchaseOrderID: purchaseOrderID,
+			ProductID:       item.ProductID,
+			Quantity:        item.Quantity,
+			OrderPrice:      item.OrderPrice,
+			SellingPrice:    item.SellingPrice,
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return "", nil
+}
+
+func checkPurchaseOrderParties(ctx context.Context, repo *repository.Queries, supplierID, storeID uint64) (string, error) {
+	_, err := repo.FindSupplier(ctx, supplierID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "Supplier not found", nil
+		}
+		return "", err
+	}
+
+	_, err = repo.FindStore(ctx, storeID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "Store not found", nil
+		}
+		return "", err
+	}
+
+	return "", nil
+}
+
+func (h *purchaseOrderHandler) response(ctx context.Context, po repository.PurchaseOrder) (dto.PurchaseOrderResponse, error) {
+	response := dto.PurchaseOrderResponse{
+		ID:        po.ID,
+		Number:    po.Number,
+		Status:    string(po.Status),
+		Notes:     po.Notes.String,
+		Items:     []dto.PurchaseOrderItemResponse{},
+		CreatedAt: po.CreatedAt.Time.UTC().Format(time.RFC3339),
+	}
+
+	if po.ExpectedAt.Valid {
+		expectedAt := po.ExpectedAt.Time.UTC().Format(time.RFC3339)
+		response.ExpectedAt = &expectedAt
+	}
+
+	supplier, err := h.repo.FindSupplier(ctx, po.SupplierID)
+	if err != nil {
+		return response, err
+	}
+	response.Supplier = supplierResponse(supplier)
+
+	if po.StoreID.Valid {
+		s, err := h.repo.FindStore(ctx, uint64(po.StoreID.Int64))
+		if err != nil && err != sql.ErrNoRows {
+			return response, err
+		}
+
+		response.Store = dto.StoreResponse{
+			ID:     s.ID,
+			Slug:   s.Slug,
+			Name:   s.Name,
+			Status: s.Status,
+		}
+	}
+
+	items, err := h.repo.FindPurchaseOrderItems(ctx, po.ID)
+	if err != nil {
+		return response, err
+	}
+
+	for _, item := range items {
+		p, err := h.repo.FindProduct(ctx, item.ProductID)
+		if err != nil && err != sql.ErrNoRows {
+			return response, err
+		}
+
+		outstanding := item.Quantity - item.Received
+		if outstanding < 0 {
+			outstanding = 0
+		}
+
+		response.Total += float64(item.Quantity) * item.OrderPrice
+		response.Items = append(response.Items, dto.PurchaseOrderItemResponse{
+			ID:           item.ID,
+			ProductID:    item.ProductID,
+			SKU:          p.Sku,
+			Name:         p.Name,
+			Quantity:     item.Quantity,
+			Received:     item.Received,
+			Outstanding:  outstanding,
+			OrderPrice:   item.OrderPrice,
+			SellingPrice: item.SellingPrice,
+		})
+	}
+
+	return response, nil
+}
+
+func (h *purchaseOrderHandler) writeResponse(w http.ResponseWriter, ctx context.Context, id uint64, status int) {
+	po, err := h.repo.FindPurchaseOrder(ctx, id)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	response, err := h.response(ctx, po)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+// findPurchaseOrder loads the purchase order named by the id URL param and
+// writes the error response if it cannot
+func findPurchaseOrder(w http.ResponseWriter, r *http.Request, repo *repository.Queries) (repository.PurchaseOrder, bool) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid purchase order ID", http.StatusBadRequest)
+		return repository.PurchaseOrder{}, false
+	}
+
+	po, err := repo.FindPurchaseOrder(r.Context(), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Purchase order not found", http.StatusNotFound)
+		} else {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return po, false
+	}
+
+	return po, true
+}
+
+// Create a draft purchase order
+func (h *purchaseOrderHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.UserID(ctx)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	form, expectedAt, msg := readPurchaseOrderForm(r)
+	if msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	repo := h.repo.WithTx(tx)
+
+	msg, err = checkPurchaseOrderParties(ctx, repo, form.SupplierID, form.StoreID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+	if msg != "" {
+		http.Error(w, msg, http.StatusNotFound)
+		return
+	}
+
+	var number string
+
+	last, err := repo.FindLastCreatedPurchaseOrder(ctx)
+	if err == sql.ErrNoRows {
+		number, err = incrementNumber("00000")
+	} else if err == nil {
+		number, err = incrementNumber(last.Number)
+	}
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to create purchase order number", http.StatusInternalServerError)
+		return
+	}
+
+	id, err := repo.InsertPurchaseOrder(ctx, repository.InsertPurchaseOrderParams{
+		Number:     number,
+		SupplierID: form.SupplierID,
+		StoreID:    sql.NullInt64{Int64: int64(form.StoreID), Valid: true},
+		Status:     repository.PurchaseOrdersStatusDraft,
+		ExpectedAt: expectedAt,
+		Notes:      nullString(form.Notes),
+		UserID:     sql.NullInt64{Int64: int64(userID), Valid: true},
+	})
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to create purchase order", http.StatusInternalServerError)
+		return
+	}
+
+	msg, err = insertPurchaseOrderItems(ctx, repo, uint64(id), form.Items)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to create purchase order", http.StatusInternalServerError)
+		return
+	}
+	if msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeResponse(w, ctx, uint64(id), http.StatusCreated)
+}
+
+// List purchase orders with pagination
+func (h *purchaseOrderHandler) AdminFindAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil {
+		limit = 20
+	}
+	offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+	if err != nil {
+		offset = 0
+	}
+
+	count, err := h.repo.CountPurchaseOrders(ctx)
+	if err != nil {
+		http.Error(w, "Failed to count purchase orders", http.StatusInternalServerError)
+		return
+	}
+
+	data, err := h.repo.FindPurchaseOrders(ctx, repository.FindPurchaseOrdersParams{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		http.Error(w, "Failed to retrieve purchase orders", http.StatusInternalServerError)
+		return
+	}
+
+	var orders = []dto.PurchaseOrderResponse{}
+
+	for _, po := range data {
+		response, err := h.response(ctx, po)
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		orders = append(orders, response)
+	}
+
+	response := map[string]interface{}{
+		"total":  count,
+		"limit":  limit,
+		"offset": offset,
+		"data":   orders,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// Get a purchase order with its items
+func (h *purchaseOrderHandler) AdminFindOne(w http.ResponseWriter, r *http.Request) {
+	po, ok := findPurchaseOrder(w, r, h.repo)
+	if !ok {
+		return
+	}
+
+	h.writeResponse(w, r.Context(), po.ID, http.StatusOK)
+}
+
+// Update a draft purchase order, its items are replaced
+func (h *purchaseOrderHandler) AdminUpdate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	po, ok := findPurchaseOrder(w, r, h.repo)
+	if !ok {
+		return
+	}
+
+	if po.Status != repository.PurchaseOrdersStatusDraft {
+		http.Error(w, "Only draft purchase orders can be changed", http.StatusConflict)
+		return
+	}
+
+	form, expectedAt, msg := readPurchaseOrderForm(r)
+	if msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	repo := h.repo.WithTx(tx)
+
+	msg, err = checkPurchaseOrderParties(ctx, repo, form.SupplierID, form.StoreID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+	if msg != "" {
+		http.Error(w, msg, http.StatusNotFound)
+		return
+	}
+
+	err = repo.UpdatePurchaseOrder(ctx, repository.UpdatePurchaseOrderParams{
+		SupplierID: form.SupplierID,
+		StoreID:    sql.NullInt64{Int64: int64(form.StoreID), Valid: true},
+		ExpectedAt: expectedAt,
+		Notes:      nullString(form.Notes),
+		ID:         po.ID,
+	})
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Error updating purchase order", http.StatusInternalServerError)
+		return
+	}
+
+	if err := repo.DeletePurchaseOrderItems(ctx, po.ID); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Error updating purchase order", http.StatusInternalServerError)
+		return
+	}
+
+	msg, err = insertPurchaseOrderItems(ctx, repo, po.ID, form.Items)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Error updating purchase order", http.StatusInternalServerError)
+		return
+	}
+	if msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeResponse(w, ctx, po.ID, http.StatusOK)
+}
+
+// Delete a draft purchase order
+func (h *purchaseOrderHandler) AdminDelete(w http.ResponseWriter, r *http.Request) {
+	po, ok := findPurchaseOrder(w, r, h.repo)
+	if !ok {
+		return
+	}
+
+	if po.Status != repository.PurchaseOrdersStatusDraft {
+		http.Error(w, "Only draft purchase orders can be deleted", http.StatusConflict)
+		return
+	}
+
+	if err := h.repo.DeletePurchaseOrder(r.Context(), po.ID); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Error deleting purchase order", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Send marks a draft purchase order as sent to the supplier
+func (h *purchaseOrderHandler) Send(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, repository.PurchaseOrdersStatusSent, repository.PurchaseOrdersStatusDraft)
+}
+
+// Close marks a purchase order as closed, whatever is still outstanding will not be received
+func (h *purchaseOrderHandler) Close(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, repository.PurchaseOrdersStatusClosed,
+		repository.PurchaseOrdersStatusSent,
+		repository.PurchaseOrdersStatusPartiallyReceived,
+		repository.PurchaseOrdersStatusReceived,
+	)
+}
+
+func (h *purchaseOrderHandler) transition(w http.ResponseWriter, r *http.Request, to repository.PurchaseOrdersStatus, from ...repository.PurchaseOrdersStatus) {
+	po, ok := findPurchaseOrder(w, r, h.repo)
+	if !ok {
+		return
+	}
+
+	allowed := false
+	for _, status := range from {
+		if po.Status == status {
+			allowed = true
+		}
+	}
+
+	if !allowed {
+		http.Error(w, fmt.Sprintf("A %s purchase order cannot be %s", po.Status, to), http.StatusConflict)
+		return
+	}
+
+	err := h.repo.UpdatePurchaseOrderStatus(r.Context(), repository.UpdatePurchaseOrderStatusParams{
+		Status: to,
+		ID:     po.ID,
+	})
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Error updating purchase order", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeResponse(w, r.Context(), po.ID, http.StatusOK)
+}
+
+// Receive records goods delivered against a purchase order. It creates a
+// goods-received note with a purchase per line and moves the order to
+// partially received or received.
+func (h *purchaseOrderHandler) Receive(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, err := middleware.UserID(ctx)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var form dto.ReceivePurchaseOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(form); err != nil {
+		http.Error(w, validationMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	date := time.Now().UTC()
+	if form.Date != "" {
+		date, err = time.Parse(time.RFC3339, form.Date)
+		if err != nil {
+			http.Error(w, "Error parsing time", http.StatusBadRequest)
+			return
+		}
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	repo := h.repo.WithTx(tx)
+
+	po, ok := findPurchaseOrder(w, r, repo)
+	if !ok {
+		return
+	}
+
+	if po.Status != repository.PurchaseOrdersStatusSent && po.Status != repository.PurchaseOrdersStatusPartiallyReceived {
+		http.Error(w, fmt.Sprintf("A %s purchase order cannot be received", po.Status), http.StatusConflict)
+		return
+	}
+
+	supplier, err := repo.FindSupplier(ctx, po.SupplierID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	_, err = repo.FindGoodsReceivedNoteByInvoice(ctx, repository.FindGoodsReceivedNoteByInvoiceParams{
+		Supplier:      supplier.Name,
+		InvoiceNumber: form.InvoiceNumber,
+	})
+	if err == nil {
+		http.Error(w, "This invoice has already been received", http.StatusConflict)
+		return
+	} else if err != sql.ErrNoRows {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	items, err := repo.FindPurchaseOrderItems(ctx, po.ID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	byID := make(map[uint64]repository.PurchaseOrderItem, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+
+	if len(form.Lines) == 0 {
+		for _, item := range items {
+			if item.Received < item.Quantity {
+				form.Lines = append(form.Lines, dto.ReceivePurchaseOrderLine{
+					ItemID:   item.ID,
+					Quantity: item.Quantity - item.Received,
+				})
+			}
+		}
+
+		if len(form.Lines) == 0 {
+			http.Error(w, "Nothing is outstanding on this purchase order", http.StatusConflict)
+			return
+		}
+	}
+
+	var lines []receivedLine
+	errors := []dto.ImportRowError{}
+
+	for i, line := range form.Lines {
+		item, ok := byID[line.ItemID]
+		if !ok {
+			errors = append(errors, dto.ImportRowError{Row: i + 1, Field: "item_id", Message: "Item is not on this purchase order"})
+			continue
+		}
+
+		if outstanding := item.Quantity - item.Received; line.Quantity > outstanding {
+			errors = append(errors, dto.ImportRowError{Row: i + 1, Field: "quantity", Message: fmt.Sprintf("Only %d outstanding", outstanding)})
+			continue
+		}
+
+		// Count the line against the item so that repeated lines cannot over-receive
+		item.Received += line.Quantity
+		byID[item.ID] = item
+
+		sellingPrice := item.SellingPrice
+		if line.SellingPrice > 0 {
+			sellingPrice = line.SellingPrice
+		}
+
+		lines = append(lines, receivedLine{
+			productID:    item.ProductID,
+			quantity:     line.Quantity,
+			orderPrice:   item.OrderPrice,
+			sellingPrice: sellingPrice,
+		})
+
+		err := repo.ReceivePurchaseOrderItem(ctx, repository.ReceivePurchaseOrderItemParams{
+			Received: line.Quantity,
+			ID:       item.ID,
+		})
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if len(errors) > 0 {
+		writeGoodsReceivedErrors(w, len(form.Lines), errors)
+		return
+	}
+
+	_, err = insertGoodsReceived(ctx, repo, h.costs, repository.InsertGoodsReceivedNoteParams{
+		Supplier:        supplier.Name,
+		InvoiceNumber:   form.InvoiceNumber,
+		StoreID:         po.StoreID,
+		Date:            date,
+		UserID:          sql.NullInt64{Int64: int64(userID), Valid: true},
+		SupplierID:      sql.NullInt64{Int64: int64(supplier.ID), Valid: true},
+		PurchaseOrderID: sql.NullInt64{Int64: int64(po.ID), Valid: true},
+	}, lines)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to create goods received note", http.StatusInternalServerError)
+		return
+	}
+
+	status := repository.PurchaseOrdersStatusReceived
+	for _, item := range byID {
+		if item.Received < item.Quantity {
+			status = repository.PurchaseOrdersStatusPartiallyReceived
+			break
+		}
+	}
+
+	err = repo.UpdatePurchaseOrderStatus(ctx, repository.UpdatePurchaseOrderStatusParams{
+		Status: status,
+		ID:     po.ID,
+	})
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Error updating purchase order", http.StatusInternalServerError)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeResponse(w, ctx, po.ID, http.StatusOK)
+}
+
+// AdminOutstandingReport lists every item still to be delivered on sent or
+// partially received purchase orders, soonest expected first
+func (h *purchaseOrderHandler) AdminOutstandingReport(w http.ResponseWriter, r *http.Request) {
+	format, err := exportFormat(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rows, err := h.repo.FindOutstandingPurchaseOrderItems(r.Context())
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+
+	var items = []dto.OutstandingPurchaseOrderItem{}
+	var total float64
+
+	for _, row := range rows {
+		outstanding := row.Quantity - row.Received
+
+		item := dto.OutstandingPurchaseOrderItem{
+			PurchaseOrderID:  row.PurchaseOrderID,
+			Number:           row.Number,
+			Status:           string(row.Status),
+			SupplierID:       row.SupplierID,
+			Supplier:         row.Supplier,
+			ProductID:        row.ProductID,
+			SKU:              row.Sku,
+			Name:             row.Name,
+			Ordered:          row.Quantity,
+			Received:         row.Received,
+			Outstanding:      outstanding,
+			OutstandingValue: float64(outstanding) * row.OrderPrice,
+		}
+
+		if row.ExpectedAt.Valid {
+			expectedAt := row.ExpectedAt.Time.UTC().Format(time.RFC3339)
+			item.ExpectedAt = &expectedAt
+			item.Overdue = row.ExpectedAt.Time.Before(now)
+		}
+
+		total += item.OutstandingValue
+		items = append(items, item)
+	}
+
+	if format != "" {
+		var rows [][]interface{}
+		for _, item := range items {
+			var expectedAt interface{}
+			if item.ExpectedAt != nil {
+				expectedAt = *item.ExpectedAt
+			}
+
+			rows = append(rows, []interface{}{item.Number, item.Status, item.Supplier, item.SKU, item.Name, expectedAt, item.Ordered, item.Received, item.Outstanding, helper.Money(item.OutstandingValue)})
+		}
+
+		writeReport(w, format, "outstanding-purchase-orders", []string{"Purchase order", "Status", "Supplier", "SKU", "Product", "Expected", "Ordered", "Received", "Outstanding", "Outstanding value"}, rows)
+		return
+	}
+
+	response := map[string]interface{}{
+		"total": total,
+		"data":  items,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
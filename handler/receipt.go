@@ -0,0 +1,459 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"api/cmd/helper"
+	"api/cmd/middleware"
+	"api/cmd/receipt"
+	"api/handler/dto"
+	"api/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator"
+)
+
+type receiptHandler struct {
+	repo *repository.Queries
+}
+
+func NewReceiptHandler(repo *repository.Queries) *receiptHandler {
+	return &receiptHandler{repo: repo}
+}
+
+// defaultReceiptTemplate is used for stores that have not set up their receipts
+func defaultReceiptTemplate(storeID uint64) repository.ReceiptTemplate {
+	return repository.ReceiptTemplate{
+		StoreID:    storeID,
+		TaxLabel:   "VAT",
+		PaperWidth: int32(receipt.Width80),
+		ShowQr:     true,
+	}
+}
+
+func (h *receiptHandler) template(ctx context.Context, storeID uint64) (repository.ReceiptTemplate, error) {
+	template, err := h.repo.FindReceiptTemplate(ctx, storeID)
+	if err == sql.ErrNoRows {
+		return defaultReceiptTemplate(storeID), nil
+	}
+
+	return template, err
+}
+
+// receiptLookupURL links to the order lookup page for an order, or is empty
+// when RECEIPT_LOOKUP_URL is not set
+func receiptLookupURL(number string) string {
+	base := os.Getenv("RECEIPT_LOOKUP_URL")
+	if base == "" {
+		return ""
+	}
+
+	return strings.TrimRight(base, "/") + "/" + url.PathEscape(number)
+}
+
+// receiptLines splits the non-empty lines of a template header or footer
+func receiptLines(text sql.NullString) []string {
+	var lines []string
+
+	for _, line := range strings.Split(text.String, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+// receiptFormat returns the receipt format asked for with the format query
+// param, HTML by default
+func receiptFormat(r *http.Request) (string, error) {
+	switch format := r.URL.Query().Get("format"); format {
+	case "":
+		return receipt.FormatHTML, nil
+	case receipt.FormatESCPOS, receipt.FormatPDF, receipt.FormatHTML:
+		return format, nil
+	default:
+		return "", fmt.Errorf("format should be escpos, pdf or html")
+	}
+}
+
+// build collects everything printed on the receipt of an in-store order
+func (h *receiptHandler) build(ctx context.Context, order repository.Order, details repository.InStoreOrderDetail) (receipt.Receipt, error) {
+	store, err := h.repo.FindStore(ctx, details.StoreID)
+	if err != nil {
+		return receipt.Receipt{}, err
+	}
+
+	template, err := h.template(ctx, store.ID)
+	if err != nil {
+		return receipt.Receipt{}, err
+	}
+
+	items, err := h.repo.FindOrderItems(ctx, order.ID)
+	if err != nil {
+		return receipt.Receipt{}, err
+	}
+
+	rec := receipt.Receipt{
+		Store:    store.Name,
+		Header:   receiptLines(template.Header),
+		Footer:   receiptLines(template.Footer),
+		Number:   order.Number,
+		Date:     order.CreatedAt.Time,
+		Total:    order.Total,
+		TaxLabel: template.TaxLabel,
+		TaxRate:  template.TaxRate,
+		Tender:   string(details.Tender),
+		Tendered: details.Tendered.Float64,
+		Width:    receipt.Width(template.PaperWidth),
+	}
+
+	if template.ShowQr {
+		rec.LookupURL = receiptLookupURL(order.Number)
+	}
+
+	if details.CashierID.Valid {
+		cashier, err := h.repo.FindUserByID(ctx, uint64(details.CashierID.Int64))
+		if err != nil && err != sql.ErrNoRows {
+			return receipt.Receipt{}, err
+		}
+
+		rec.Cashier = cashier.Firstname
+	}
+
+	for _, item := range items {
+		product, err := h.repo.FindProduct(ctx, item.ProductID)
+		if err != nil {
+			return receipt.Receipt{}, err
+		}
+
+		total := item.Total.Float64
+		if !item.Total.Valid {
+			total = float64(item.Quantity) * item.Price
+		}
+
+		rec.Lines = append(rec.Lines, receipt.Line{
+			SKU:      product.Sku,
+			Name:     product.Name,
+			Quantity: item.Quantity,
+			Price:    item.Price,
+			Total:    total,
+		})
+	}
+
+	return rec, nil
+}
+
+// findReceipt loads the receipt of the order, writing the error response when
+// it cannot. storeID limits the lookup to the orders of one store when set.
+func (h *receiptHandler) findReceipt(w http.ResponseWriter, r *http.Request, orderID uint64, storeID sql.NullInt64) (receipt.Receipt, bool) {
+	ctx := r.Context()
+
+	order, err := h.repo.FindOrderWithChannel(ctx, repository.FindOrderWithChannelParams{
+		ID:      orderID,
+		Channel: repository.OrdersChannelInStore,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Order not found", http.StatusNotFound)
+		} else {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return receipt.Receipt{}, false
+	}
+
+	details, err := h.repo.FindStoreOrderDetails(ctx, order.ID)
+	if err == nil && storeID.Valid && details.StoreID != uint64(storeID.Int64) {
+		err = sql.ErrNoRows
+	}
+	if err == nil && !middleware.ContextGetStores(ctx).Allows(details.StoreID) {
+		err = sql.ErrNoRows
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Order not found", http.StatusNotFound)
+		} else {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return receipt.Receipt{}, false
+	}
+
+	rec, err := h.build(ctx, order, details)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return receipt.Receipt{}, false
+	}
+
+	// The printer in use may differ from the store's usual paper width
+	if value := r.URL.Query().Get("width"); value != "" {
+		width, err := strconv.Atoi(value)
+		if err != nil || !receipt.Width(width).Valid() {
+			http.Error(w, "width should be 58 or 80", http.StatusBadRequest)
+			return receipt.Receipt{}, false
+		}
+
+		rec.Width = receipt.Width(width)
+	}
+
+	return rec, true
+}
+
+func writeReceipt(w http.ResponseWriter, rec receipt.Receipt, format string) {
+	var buf bytes.Buffer
+	if err := receipt.Write(&buf, rec, format); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", receipt.ContentType(format))
+	if format != receipt.FormatHTML {
+		w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"receipt-%s.%s\"", rec.Number, format))
+	}
+
+	buf.WriteTo(w)
+}
+
+// cashierOrder reads the order and store in the URL and checks that the
+// cashier works at the store
+func (h *receiptHandler) cashierOrder(w http.ResponseWriter, r *http.Request) (uint64, sql.NullInt64, bool) {
+	ctx := r.Context()
+
+	cashierID, err := middleware.UserID(ctx)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return 0, sql.NullInt64{}, false
+	}
+
+	orderID, err := strconv.ParseUint(chi.URLParam(r, "orderID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		return 0, sql.NullInt64{}, false
+	}
+
+	storeID, err := strconv.ParseUint(chi.URLParam(r, "storeID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid store ID", http.StatusBadRequest)
+		return 0, sql.NullInt64{}, false
+	}
+
+	allowed, err := h.repo.CheckStoreUser(ctx, repository.CheckStoreUserParams{
+		StoreID: storeID,
+		UserID:  cashierID,
+	})
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return 0, sql.NullInt64{}, false
+	}
+
+	if !allowed {
+		http.Error(w, "Not allowed to perform this task", http.StatusForbidden)
+		return 0, sql.NullInt64{}, false
+	}
+
+	return orderID, sql.NullInt64{Int64: int64(storeID), Valid: true}, true
+}
+
+// CashierReceipt prints the receipt of an order of the cashier's store
+func (h *receiptHandler) CashierReceipt(w http.ResponseWriter, r *http.Request) {
+	orderID, storeID, ok := h.cashierOrder(w, r)
+	if !ok {
+		return
+	}
+
+	format, err := receiptFormat(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rec, ok := h.findReceipt(w, r, orderID, storeID)
+	if !ok {
+		return
+	}
+
+	writeReceipt(w, rec, format)
+}
+
+// CashierEmailReceipt emails the HTML receipt of an order to a customer
+func (h *receiptHandler) CashierEmailReceipt(w http.ResponseWriter, r *http.Request) {
+	orderID, storeID, ok := h.cashierOrder(w, r)
+	if !ok {
+		return
+	}
+
+	var form dto.EmailReceiptRequest
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(form); err != nil {
+		http.Error(w, validationMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	rec, ok := h.findReceipt(w, r, orderID, storeID)
+	if !ok {
+		return
+	}
+
+	var body bytes.Buffer
+	if err := receipt.Write(&body, rec, receipt.FormatHTML); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err := helper.SendEmail(form.Email, fmt.Sprintf("Your receipt for order %s", rec.Number), body.String()); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to send receipt", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AdminReceipt prints the receipt of any in-store order
+func (h *receiptHandler) AdminReceipt(w http.ResponseWriter, r *http.Request) {
+	format, err := receiptFormat(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	orderID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		return
+	}
+
+	rec, ok := h.findReceipt(w, r, orderID, sql.NullInt64{})
+	if !ok {
+		return
+	}
+
+	writeReceipt(w, rec, format)
+}
+
+func receiptTemplateResponse(template repository.ReceiptTemplate) dto.ReceiptTemplateResponse {
+	return dto.ReceiptTemplateResponse{
+		StoreID:    template.StoreID,
+		Header:     template.Header.String,
+		Footer:     template.Footer.String,
+		TaxLabel:   template.TaxLabel,
+		TaxRate:    template.TaxRate,
+		PaperWidth: template.PaperWidth,
+		ShowQR:     template.ShowQr,
+	}
+}
+
+// findStore reads the store in the URL, writing the error response when it
+// does not exist
+func (h *receiptHandler) findStore(w http.ResponseWriter, r *http.Request) (repository.Store, bool) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid store ID", http.StatusBadRequest)
+		return repository.Store{}, false
+	}
+
+	store, err := h.repo.FindStore(r.Context(), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Store not found", http.StatusNotFound)
+		} else {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return repository.Store{}, false
+	}
+
+	return store, true
+}
+
+// AdminFindTemplate shows the receipt template of a store
+func (h *receiptHandler) AdminFindTemplate(w http.ResponseWriter, r *http.Request) {
+	store, ok := h.findStore(w, r)
+	if !ok {
+		return
+	}
+
+	template, err := h.template(r.Context(), store.ID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(receiptTemplateResponse(template))
+}
+
+// AdminUpdateTemplate sets the receipt template of a store
+func (h *receiptHandler) AdminUpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	store, ok := h.findStore(w, r)
+	if !ok {
+		return
+	}
+
+	var form dto.ReceiptTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(form); err != nil {
+		http.Error(w, validationMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	template := defaultReceiptTemplate(store.ID)
+	template.Header = sql.NullString{String: form.Header, Valid: form.Header != ""}
+	template.Footer = sql.NullString{String: form.Footer, Valid: form.Footer != ""}
+	template.TaxRate = form.TaxRate
+
+	if form.TaxLabel != "" {
+		template.TaxLabel = form.TaxLabel
+	}
+	if form.PaperWidth != 0 {
+		template.PaperWidth = form.PaperWidth
+	}
+	if form.ShowQR != nil {
+		template.ShowQr = *form.ShowQR
+	}
+
+	err := h.repo.UpsertReceiptTemplate(ctx, repository.UpsertReceiptTemplateParams{
+		StoreID:    template.StoreID,
+		Header:     template.Header,
+		Footer:     template.Footer,
+		TaxLabel:   template.TaxLabel,
+		TaxRate:    template.TaxRate,
+		PaperWidth: template.PaperWidth,
+		ShowQr:     template.ShowQr,
+	})
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to update receipt template", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(receiptTemplateResponse(template))
+}
@@ -0,0 +1,302 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"api/handler/dto"
+	"api/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator"
+)
+
+type reorderPointHandler struct {
+	repo *repository.Queries
+}
+
+func NewReorderPointHandler(repo *repository.Queries) *reorderPointHandler {
+	return &reorderPointHandler{repo: repo}
+}
+
+func (h *reorderPointHandler) response(ctx context.Context, point repository.ReorderPoint) (dto.ReorderPointResponse, error) {
+	response := dto.ReorderPointResponse{
+		ID:              point.ID,
+		ProductID:       point.ProductID,
+		ReorderPoint:    point.ReorderPoint,
+		ReorderQuantity: point.ReorderQuantity,
+	}
+
+	p, err := h.repo.FindProduct(ctx, point.ProductID)
+	if err != nil {
+		return response, err
+	}
+
+	response.SKU = p.Sku
+	response.Product = p.Name
+
+	if point.StoreID.Valid {
+		s, err := h.repo.FindStore(ctx, uint64(point.StoreID.Int64))
+		if err != nil {
+			return response, err
+		}
+
+		response.StoreID = &s.ID
+		response.Store = s.Name
+	}
+
+	return response, nil
+}
+
+// Create sets the reorder point of a product, for one store or, without a
+// store, as the default for every store. An existing reorder point for the
+// same product and store is replaced.
+func (h *reorderPointHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+
+	var form dto.CreateReorderPointRequest
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(form); err != nil {
+		http.Error(w, validationMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	p, err := h.repo.FindProduct(ctx, form.ProductID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Product not found", http.StatusNotFound)
+		} else {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	var storeID sql.NullInt64
+
+	if form.StoreID != 0 {
+		s, err := h.repo.FindStore(ctx, form.StoreID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				http.Error(w, "Store not found", http.StatusNotFound)
+			} else {
+				fmt.Println(err)
+				http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		storeID = sql.NullInt64{Int64: int64(s.ID), Valid: true}
+	}
+
+	status := http.StatusCreated
+
+	point, err := h.repo.FindReorderPointByProductStore(ctx, repository.FindReorderPointByProductStoreParams{
+		ProductID: p.ID,
+		StoreID:   storeID,
+	})
+	switch err {
+	case nil:
+		err = h.repo.UpdateReorderPoint(ctx, repository.UpdateReorderPointParams{
+			ReorderPoint:    form.ReorderPoint,
+			ReorderQuantity: form.ReorderQuantity,
+			ID:              point.ID,
+		})
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Error updating reorder point", http.StatusInternalServerError)
+			return
+		}
+
+		status = http.StatusOK
+	case sql.ErrNoRows:
+		id, err := h.repo.InsertReorderPoint(ctx, repository.InsertReorderPointParams{
+			ProductID:       p.ID,
+			StoreID:         storeID,
+			ReorderPoint:    form.ReorderPoint,
+			ReorderQuantity: form.ReorderQuantity,
+		})
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Failed to create reorder point", http.StatusInternalServerError)
+			return
+		}
+
+		point.ID = uint64(id)
+	default:
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	point, err = h.repo.FindReorderPoint(ctx, point.ID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	response, err := h.response(ctx, point)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+// List reorder points with pagination
+func (h *reorderPointHandler) AdminFindAll(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil {
+		limit = 20
+	}
+	offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+	if err != nil {
+		offset = 0
+	}
+
+	count, err := h.repo.CountReorderPoints(ctx)
+	if err != nil {
+		http.Error(w, "Failed to count reorder points", http.StatusInternalServerError)
+		return
+	}
+
+	data, err := h.repo.FindReorderPoints(ctx, repository.FindReorderPointsParams{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		http.Error(w, "Failed to retrieve reorder points", http.StatusInternalServerError)
+		return
+	}
+
+	var points = []dto.ReorderPointResponse{}
+
+	for _, point := range data {
+		response, err := h.response(ctx, point)
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		points = append(points, response)
+	}
+
+	response := map[string]interface{}{
+		"total":  count,
+		"limit":  limit,
+		"offset": offset,
+		"data":   points,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// Delete a reorder point
+func (h *reorderPointHandler) AdminDelete(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid reorder point ID", http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.repo.FindReorderPoint(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Reorder point not found", http.StatusNotFound)
+		} else {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if err := h.repo.DeleteReorderPoint(ctx, id); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to delete reorder point", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AdminLowStock lists the items at or below their reorder point, optionally
+// for a single store
+func (h *reorderPointHandler) AdminLowStock(w http.ResponseWriter, r *http.Request) {
+	format, err := exportFormat(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var storeID sql.NullInt64
+
+	if value := r.URL.Query().Get("store_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid store ID", http.StatusBadRequest)
+			return
+		}
+
+		storeID = sql.NullInt64{Int64: id, Valid: true}
+	}
+
+	rows, err := h.repo.FindLowStock(r.Context(), storeID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	var items = []dto.LowStockResponse{}
+
+	for _, row := range rows {
+		items = append(items, dto.LowStockResponse{
+			ProductID:       row.ProductID,
+			SKU:             row.Sku,
+			Name:            row.Name,
+			StoreID:         row.StoreID,
+			Store:           row.Store,
+			Quantity:        row.Quantity,
+			ReorderPoint:    row.ReorderPoint,
+			ReorderQuantity: row.ReorderQuantity,
+		})
+	}
+
+	if format != "" {
+		var rows [][]interface{}
+		for _, item := range items {
+			rows = append(rows, []interface{}{item.Store, item.SKU, item.Name, item.Quantity, item.ReorderPoint, item.ReorderQuantity})
+		}
+
+		writeReport(w, format, "low-stock", []string{"Store", "SKU", "Product", "Quantity", "Reorder point", "Reorder quantity"}, rows)
+		return
+	}
+
+	response := map[string]interface{}{
+		"total": len(items),
+		"data":  items,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
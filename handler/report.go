@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const reportDateLayout = "2006-01-02"
+
+// reportPeriods maps the period query param to a MySQL DATE_FORMAT format
+var reportPeriods = map[string]string{
+	"day":   "%Y-%m-%d",
+	"week":  "%x-W%v",
+	"month": "%Y-%m",
+}
+
+// reportRange reads the from and to query params as dates. The range includes
+// the whole of the to day and defaults to the last 30 days.
+func reportRange(r *http.Request) (time.Time, time.Time, error) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	from := today.AddDate(0, 0, -30)
+	to := today.AddDate(0, 0, 1)
+
+	if value := r.URL.Query().Get("from"); value != "" {
+		date, err := time.Parse(reportDateLayout, value)
+		if err != nil {
+			return from, to, fmt.Errorf("from should be a date like 2024-01-31")
+		}
+		from = date
+	}
+
+	if value := r.URL.Query().Get("to"); value != "" {
+		date, err := time.Parse(reportDateLayout, value)
+		if err != nil {
+			return from, to, fmt.Errorf("to should be a date like 2024-01-31")
+		}
+		to = date.AddDate(0, 0, 1)
+	}
+
+	if !from.Before(to) {
+		return from, to, fmt.Errorf("from should be before to")
+	}
+
+	return from, to, nil
+}
+
+// reportPeriod returns the DATE_FORMAT format of the period query param, which
+// defaults to month
+func reportPeriod(r *http.Request) (string, error) {
+	period := r.URL.Query().Get("period")
+	if period == "" {
+		period = "month"
+	}
+
+	format, ok := reportPeriods[period]
+	if !ok {
+		return "", fmt.Errorf("period should be day, week or month")
+	}
+
+	return format, nil
+}
@@ -0,0 +1,506 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"api/cmd/middleware"
+	"api/handler/dto"
+	"api/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator"
+)
+
+// builtinRoles are looked up by name when users register or join a store, so
+// they cannot be renamed or deleted
+var builtinRoles = []string{"admin", "customer", "cashier", "manager"}
+
+// adminPermission is the permission the admin role has to keep, so that roles
+// can always be managed
+const adminPermission = "roles:manage"
+
+type roleHandler struct {
+	db   *sql.DB
+	repo *repository.Queries
+}
+
+func NewRoleHandler(db *sql.DB, repo *repository.Queries) *roleHandler {
+	return &roleHandler{db: db, repo: repo}
+}
+
+func (h *roleHandler) findRole(w http.ResponseWriter, r *http.Request, param string) (repository.Role, bool) {
+	id, err := strconv.ParseUint(chi.URLParam(r, param), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid role ID", http.StatusBadRequest)
+		return repository.Role{}, false
+	}
+
+	role, err := h.repo.FindRole(r.Context(), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Role not found", http.StatusNotFound)
+		} else {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return role, false
+	}
+
+	return role, true
+}
+
+func (h *roleHandler) roleResponse(ctx context.Context, role repository.Role) (dto.RoleDetailResponse, error) {
+	permissions, err := h.repo.FindRolePermissions(ctx, role.ID)
+	if err != nil {
+		return dto.RoleDetailResponse{}, err
+	}
+
+	response := dto.RoleDetailResponse{
+		ID:          role.ID,
+		Name:        role.Name,
+		Permissions: []string{},
+	}
+
+	for _, p := range permissions {
+		response.Permissions = append(response.Permissions, p.Name)
+	}
+
+	return response, nil
+}
+
+// readRole decodes and validates a role, and resolves its permissions to IDs
+func (h *roleHandler) readRole(w http.ResponseWriter, r *http.Request) (dto.RoleRequest, []uint64, bool) {
+	var form dto.RoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return form, nil, false
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(form); err != nil {
+		http.Error(w, validationMessage(err), http.StatusBadRequest)
+		return form, nil, false
+	}
+
+	permissions, err := h.repo.FindPermissions(r.Context())
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return form, nil, false
+	}
+
+	known := map[string]uint64{}
+	for _, p := range permissions {
+		known[p.Name] = p.ID
+	}
+
+	var ids []uint64
+	for _, name := range form.Permissions {
+		id, ok := known[name]
+		if !ok {
+			http.Error(w, fmt.Sprintf("Unknown permission: %s", name), http.StatusBadRequest)
+			return form, nil, false
+		}
+
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+
+	return form, ids, true
+}
+
+// setPermissions replaces the permissions of a role
+func setPermissions(ctx context.Context, repo *repository.Queries, roleID uint64, permissionIDs []uint64) error {
+	if err := repo.DeleteRolePermissions(ctx, roleID); err != nil {
+		return err
+	}
+
+	for _, id := range permissionIDs {
+		err := repo.InsertRolePermission(ctx, repository.InsertRolePermissionParams{
+			RoleID:       roleID,
+			PermissionID: id,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// List every permission that can be granted to a role
+func (h *roleHandler) AdminFindPermissions(w http.ResponseWriter, r *http.Request) {
+	permissions, err := h.repo.FindPermissions(r.Context())
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	var response = []dto.PermissionResponse{}
+
+	for _, p := range permissions {
+		permission := dto.PermissionResponse{ID: p.ID, Name: p.Name}
+		if p.Description.Valid {
+			permission.Description = &p.Description.String
+		}
+
+		response = append(response, permission)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *roleHandler) AdminFindAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	roles, err := h.repo.FindRoles(ctx)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	var response = []dto.RoleDetailResponse{}
+
+	for _, role := range roles {
+		item, err := h.roleResponse(ctx, role)
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		response = append(response, item)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *roleHandler) AdminFindOne(w http.ResponseWriter, r *http.Request) {
+	role, ok := h.findRole(w, r, "id")
+	if !ok {
+		return
+	}
+
+	response, err := h.roleResponse(r.Context(), role)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *roleHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	form, permissionIDs, ok := h.readRole(w, r)
+	if !ok {
+		return
+	}
+
+	_, err := h.repo.FindRoleByName(ctx, form.Name)
+	if err == nil {
+		http.Error(w, "Role already exists", http.StatusConflict)
+		return
+	} else if err != sql.ErrNoRows {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	repo := h.repo.WithTx(tx)
+
+	id, err := repo.InsertRole(ctx, form.Name)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to create role", http.StatusInternalServerError)
+		return
+	}
+
+	if err := setPermissions(ctx, repo, uint64(id), permissionIDs); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to create role", http.StatusInternalServerError)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to create role", http.StatusInternalServerError)
+		return
+	}
+
+	role, err := h.repo.FindRole(ctx, uint64(id))
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	response, err := h.roleResponse(ctx, role)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// AdminUpdate renames a role and replaces its permissions
+func (h *roleHandler) AdminUpdate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	role, ok := h.findRole(w, r, "id")
+	if !ok {
+		return
+	}
+
+	form, permissionIDs, ok := h.readRole(w, r)
+	if !ok {
+		return
+	}
+
+	if form.Name != role.Name {
+		if slices.Contains(builtinRoles, role.Name) {
+			http.Error(w, "Built-in roles cannot be renamed", http.StatusConflict)
+			return
+		}
+
+		_, err := h.repo.FindRoleByName(ctx, form.Name)
+		if err == nil {
+			http.Error(w, "Role already exists", http.StatusConflict)
+			return
+		} else if err != sql.ErrNoRows {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if role.Name == "admin" && !slices.Contains(form.Permissions, adminPermission) {
+		http.Error(w, fmt.Sprintf("The admin role must keep %s", adminPermission), http.StatusConflict)
+		return
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	repo := h.repo.WithTx(tx)
+
+	err = repo.UpdateRole(ctx, repository.UpdateRoleParams{Name: form.Name, ID: role.ID})
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to update role", http.StatusInternalServerError)
+		return
+	}
+
+	if err := setPermissions(ctx, repo, role.ID, permissionIDs); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to update role", http.StatusInternalServerError)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to update role", http.StatusInternalServerError)
+		return
+	}
+
+	role.Name = form.Name
+
+	response, err := h.roleResponse(ctx, role)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *roleHandler) AdminDelete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	role, ok := h.findRole(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if slices.Contains(builtinRoles, role.Name) {
+		http.Error(w, "Built-in roles cannot be deleted", http.StatusConflict)
+		return
+	}
+
+	users, err := h.repo.CountRoleUsers(ctx, role.ID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if users > 0 {
+		http.Error(w, "Role is still assigned to users", http.StatusConflict)
+		return
+	}
+
+	if err := h.repo.DeleteRole(ctx, role.ID); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to delete role", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// List the roles of a user
+func (h *roleHandler) AdminFindUserRoles(w http.ResponseWriter, r *http.Request) {
+	user, ok := findUser(w, r, h.repo)
+	if !ok {
+		return
+	}
+
+	roles, err := h.repo.FindUserRoles(r.Context(), user.ID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	var response = []dto.RoleResponse{}
+
+	for _, role := range roles {
+		response = append(response, dto.RoleResponse{ID: role.ID, Name: role.Name})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// Give a user a role
+func (h *roleHandler) AdminAssignUserRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := findUser(w, r, h.repo)
+	if !ok {
+		return
+	}
+
+	var form dto.AssignUserRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(form); err != nil {
+		http.Error(w, validationMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	role, err := h.repo.FindRole(ctx, form.RoleID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Role not found", http.StatusNotFound)
+		} else {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	hasRole, err := h.repo.CheckUserRole(ctx, repository.CheckUserRoleParams{
+		UserID: user.ID,
+		RoleID: role.ID,
+	})
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if hasRole {
+		http.Error(w, "User already has this role", http.StatusConflict)
+		return
+	}
+
+	err = h.repo.AssignUserRole(ctx, repository.AssignUserRoleParams{UserID: user.ID, RoleID: role.ID})
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to assign role", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Take a role away from a user
+func (h *roleHandler) AdminRemoveUserRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := findUser(w, r, h.repo)
+	if !ok {
+		return
+	}
+
+	role, ok := h.findRole(w, r, "roleID")
+	if !ok {
+		return
+	}
+
+	adminID, err := middleware.UserID(ctx)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if user.ID == adminID && role.Name == "admin" {
+		http.Error(w, "You cannot remove your own admin role", http.StatusConflict)
+		return
+	}
+
+	removed, err := h.repo.DeleteUserRole(ctx, repository.DeleteUserRoleParams{
+		UserID: user.ID,
+		RoleID: role.ID,
+	})
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to remove role", http.StatusInternalServerError)
+		return
+	}
+
+	if removed == 0 {
+		http.Error(w, "User does not have this role", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,290 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"api/cmd/helper"
+	"api/handler/dto"
+	"api/repository"
+)
+
+type salesReportHandler struct {
+	repo *repository.Queries
+}
+
+func NewSalesReportHandler(repo *repository.Queries) *salesReportHandler {
+	return &salesReportHandler{repo: repo}
+}
+
+func salesResponse(name string, orders, units int64, revenue float64) dto.SalesResponse {
+	response := dto.SalesResponse{
+		Name:    name,
+		Orders:  orders,
+		Units:   units,
+		Revenue: math.Round(revenue*100) / 100,
+	}
+
+	if orders > 0 {
+		response.AverageBasket = math.Round(revenue/float64(orders)*100) / 100
+	}
+
+	return response
+}
+
+func nullID(id sql.NullInt64) *uint64 {
+	if !id.Valid {
+		return nil
+	}
+
+	value := uint64(id.Int64)
+	return &value
+}
+
+// AdminSummary returns the number of orders, units sold, revenue and average
+// basket between from and to. Canceled orders are left out of every sales report.
+func (h *salesReportHandler) AdminSummary(w http.ResponseWriter, r *http.Request) {
+	from, to, err := reportRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	summary, err := h.repo.FindSalesSummary(r.Context(), repository.FindSalesSummaryParams{
+		FromDate: from,
+		ToDate:   to,
+		Stores:   storeFilter(r.Context()),
+	})
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	response := dto.SalesSummaryResponse{
+		From:    from.Format(reportDateLayout),
+		To:      to.AddDate(0, 0, -1).Format(reportDateLayout),
+		Orders:  summary.Orders,
+		Units:   summary.Units,
+		Revenue: math.Round(summary.Revenue*100) / 100,
+	}
+
+	if summary.Orders > 0 {
+		response.AverageBasketValue = math.Round(summary.Revenue/float64(summary.Orders)*100) / 100
+		response.AverageBasketUnits = math.Round(float64(summary.Units)/float64(summary.Orders)*100) / 100
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// AdminSales reports orders, units and revenue grouped by period, store,
+// channel, cashier, category or product
+func (h *salesReportHandler) AdminSales(w http.ResponseWriter, r *http.Request) {
+	format, err := exportFormat(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	from, to, err := reportRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	group := r.URL.Query().Get("group")
+	if group == "" {
+		group = "period"
+	}
+
+	var lines = []dto.SalesResponse{}
+
+	switch group {
+	case "period":
+		layout, err := reportPeriod(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		rows, err := h.repo.FindSalesByPeriod(r.Context(), repository.FindSalesByPeriodParams{
+			Format:   layout,
+			FromDate: from,
+			ToDate:   to,
+			Stores:   storeFilter(r.Context()),
+		})
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		for _, row := range rows {
+			lines = append(lines, salesResponse(row.Period, row.Orders, row.Units, row.Revenue))
+		}
+	case "store":
+		rows, err := h.repo.FindSalesByStore(r.Context(), repository.FindSalesByStoreParams{FromDate: from, ToDate: to, Stores: storeFilter(r.Context())})
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		for _, row := range rows {
+			line := salesResponse(row.Store, row.Orders, row.Units, row.Revenue)
+			line.ID = nullID(row.StoreID)
+
+			lines = append(lines, line)
+		}
+	case "channel":
+		rows, err := h.repo.FindSalesByChannel(r.Context(), repository.FindSalesByChannelParams{FromDate: from, ToDate: to, Stores: storeFilter(r.Context())})
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		for _, row := range rows {
+			lines = append(lines, salesResponse(string(row.Channel), row.Orders, row.Units, row.Revenue))
+		}
+	case "cashier":
+		rows, err := h.repo.FindSalesByCashier(r.Context(), repository.FindSalesByCashierParams{FromDate: from, ToDate: to, Stores: storeFilter(r.Context())})
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		for _, row := range rows {
+			line := salesResponse(row.Cashier, row.Orders, row.Units, row.Revenue)
+			line.ID = nullID(row.CashierID)
+
+			lines = append(lines, line)
+		}
+	case "category":
+		rows, err := h.repo.FindSalesByCategory(r.Context(), repository.FindSalesByCategoryParams{FromDate: from, ToDate: to, Stores: storeFilter(r.Context())})
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		for _, row := range rows {
+			line := salesResponse(row.Category, row.Orders, row.Units, row.Revenue)
+			line.ID = nullID(row.CategoryID)
+
+			lines = append(lines, line)
+		}
+	case "product":
+		rows, err := h.repo.FindSalesByProduct(r.Context(), repository.FindSalesByProductParams{FromDate: from, ToDate: to, Stores: storeFilter(r.Context())})
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		for _, row := range rows {
+			line := salesResponse(row.Name, row.Orders, row.Units, row.Revenue)
+			productID := row.ProductID
+			line.ID = &productID
+			line.SKU = row.Sku
+
+			lines = append(lines, line)
+		}
+	default:
+		http.Error(w, "group should be period, store, channel, cashier, category or product", http.StatusBadRequest)
+		return
+	}
+
+	if format != "" {
+		columns, rows := salesTable(lines)
+		writeReport(w, format, "sales-by-"+group, columns, rows)
+		return
+	}
+
+	response := map[string]interface{}{
+		"group": group,
+		"from":  from.Format(reportDateLayout),
+		"to":    to.AddDate(0, 0, -1).Format(reportDateLayout),
+		"data":  lines,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// AdminTopSellers lists the products that sold the most units, 10 by default
+func (h *salesReportHandler) AdminTopSellers(w http.ResponseWriter, r *http.Request) {
+	format, err := exportFormat(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	from, to, err := reportRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit < 1 {
+		limit = 10
+	}
+
+	rows, err := h.repo.FindTopSellers(r.Context(), repository.FindTopSellersParams{
+		FromDate: from,
+		ToDate:   to,
+		Stores:   storeFilter(r.Context()),
+		Limit:    int32(limit),
+	})
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	var lines = []dto.SalesResponse{}
+
+	for _, row := range rows {
+		line := salesResponse(row.Name, row.Orders, row.Units, row.Revenue)
+		productID := row.ProductID
+		line.ID = &productID
+		line.SKU = row.Sku
+
+		lines = append(lines, line)
+	}
+
+	if format != "" {
+		columns, rows := salesTable(lines)
+		writeReport(w, format, "top-sellers", columns, rows)
+		return
+	}
+
+	response := map[string]interface{}{
+		"from":  from.Format(reportDateLayout),
+		"to":    to.AddDate(0, 0, -1).Format(reportDateLayout),
+		"limit": limit,
+		"data":  lines,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// salesTable lays sales lines out as export rows
+func salesTable(lines []dto.SalesResponse) ([]string, [][]interface{}) {
+	columns := []string{"SKU", "Name", "Orders", "Units", "Revenue", "Average basket"}
+
+	var rows [][]interface{}
+	for _, line := range lines {
+		rows = append(rows, []interface{}{line.SKU, line.Name, line.Orders, line.Units, helper.Money(line.Revenue), helper.Money(line.AverageBasket)})
+	}
+
+	return columns, rows
+}
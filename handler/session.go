@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"api/cmd/helper"
+	"api/cmd/revocation"
+	"api/handler/dto"
+	"api/repository"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// revokeSession ends a session: its refresh tokens can no longer be used and
+// its access tokens are rejected straight away
+func revokeSession(ctx context.Context, repo *repository.Queries, revocations revocation.Store, session string) error {
+	if err := repo.RevokeRefreshTokenFamily(ctx, session); err != nil {
+		return err
+	}
+
+	return revocations.Revoke(ctx, revocation.SessionKey(session), time.Now(), helper.AccessTokenTTL())
+}
+
+// revokeUserSessions ends every session of a user
+func revokeUserSessions(ctx context.Context, repo *repository.Queries, revocations revocation.Store, userID uint64) error {
+	if err := repo.RevokeUserRefreshTokens(ctx, userID); err != nil {
+		return err
+	}
+
+	return revocations.Revoke(ctx, revocation.UserKey(userID), time.Now(), helper.AccessTokenTTL())
+}
+
+type sessionHandler struct {
+	repo        *repository.Queries
+	revocations revocation.Store
+}
+
+func NewSessionHandler(repo *repository.Queries, revocations revocation.Store) *sessionHandler {
+	return &sessionHandler{repo: repo, revocations: revocations}
+}
+
+// findUser reads the user in the URL
+func findUser(w http.ResponseWriter, r *http.Request, repo *repository.Queries) (repository.User, bool) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return repository.User{}, false
+	}
+
+	user, err := repo.FindUserByID(r.Context(), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "User not found", http.StatusNotFound)
+		} else {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return user, false
+	}
+
+	return user, true
+}
+
+// List the sessions of a user that can still be refreshed
+func (h *sessionHandler) AdminFindAll(w http.ResponseWriter, r *http.Request) {
+	user, ok := findUser(w, r, h.repo)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.repo.FindActiveSessions(r.Context(), user.ID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	var response = []dto.SessionResponse{}
+
+	for _, s := range sessions {
+		session := dto.SessionResponse{
+			ID:        s.FamilyID,
+			ExpiresAt: s.ExpiresAt.UTC().Format(time.RFC3339),
+		}
+
+		if s.UserAgent.Valid {
+			session.UserAgent = &s.UserAgent.String
+		}
+
+		if s.StartedAt.Valid {
+			startedAt := s.StartedAt.Time.UTC().Format(time.RFC3339)
+			session.StartedAt = &startedAt
+		}
+
+		if s.LastUsedAt.Valid {
+			lastUsedAt := s.LastUsedAt.Time.UTC().Format(time.RFC3339)
+			session.LastUsedAt = &lastUsedAt
+		}
+
+		response = append(response, session)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// Sign a user out everywhere
+func (h *sessionHandler) AdminRevokeAll(w http.ResponseWriter, r *http.Request) {
+	user, ok := findUser(w, r, h.repo)
+	if !ok {
+		return
+	}
+
+	if err := revokeUserSessions(r.Context(), h.repo, h.revocations, user.ID); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to revoke sessions", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Sign a user out of one session
+func (h *sessionHandler) AdminRevoke(w http.ResponseWriter, r *http.Request) {
+	user, ok := findUser(w, r, h.repo)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.repo.FindActiveSessions(r.Context(), user.ID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	id := chi.URLParam(r, "sessionID")
+
+	found := false
+	for _, s := range sessions {
+		found = found || s.FamilyID == id
+	}
+
+	if !found {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+
+	if err := revokeSession(r.Context(), h.repo, h.revocations, id); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to revoke session", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
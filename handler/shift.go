@@ -0,0 +1,636 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"api/cmd/helper"
+	"api/cmd/middleware"
+	"api/handler/dto"
+	"api/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator"
+	"github.com/go-sql-driver/mysql"
+)
+
+type shiftHandler struct {
+	db   *sql.DB
+	repo *repository.Queries
+}
+
+func NewShiftHandler(db *sql.DB, repo *repository.Queries) *shiftHandler {
+	return &shiftHandler{db: db, repo: repo}
+}
+
+// shiftResponse works out the cash-up of a shift from its cash-tender orders
+// and cash movements
+func shiftResponse(ctx context.Context, repo *repository.Queries, shift repository.Shift) (dto.ShiftResponse, error) {
+	store, err := repo.FindStore(ctx, shift.StoreID)
+	if err != nil {
+		return dto.ShiftResponse{}, err
+	}
+
+	cashier, err := repo.FindUserByID(ctx, shift.CashierID)
+	if err != nil {
+		return dto.ShiftResponse{}, err
+	}
+
+	sales, err := repo.FindShiftSales(ctx, sql.NullInt64{Int64: int64(shift.ID), Valid: true})
+	if err != nil {
+		return dto.ShiftResponse{}, err
+	}
+
+	movements, err := repo.FindShiftCashMovements(ctx, shift.ID)
+	if err != nil {
+		return dto.ShiftResponse{}, err
+	}
+
+	response := dto.ShiftResponse{
+		ID:     shift.ID,
+		Status: string(shift.Status),
+		Store: dto.StoreResponse{
+			ID:     store.ID,
+			Slug:   store.Slug,
+			Name:   store.Name,
+			Status: store.Status,
+		},
+		Cashier: dto.UserResponse{
+			ID:        cashier.ID,
+			Firstname: cashier.Firstname,
+			Lastname:  cashier.Lastname,
+			Email:     cashier.Email.String,
+		},
+		OpeningFloat: shift.OpeningFloat,
+		Sales:        []dto.ShiftTenderResponse{},
+		Notes:        shift.Notes.String,
+		Movements:    []dto.ShiftCashMovementResponse{},
+		OpenedAt:     shift.OpenedAt.Time.UTC().Format(time.RFC3339),
+	}
+
+	if cashier.Phone.Valid {
+		response.Cashier.Phone = cashier.Phone.String
+	}
+
+	for _, sale := range sales {
+		response.Sales = append(response.Sales, dto.ShiftTenderResponse{
+			Tender: string(sale.Tender),
+			Orders: sale.Orders,
+			Total:  math.Round(sale.Total*100) / 100,
+		})
+
+		if sale.Tender == repository.InStoreOrderDetailsTenderCash {
+			response.CashSales += sale.Total
+		}
+	}
+
+	for _, movement := range movements {
+		response.Movements = append(response.Movements, dto.ShiftCashMovementResponse{
+			ID:        movement.ID,
+			Type:      string(movement.Type),
+			Amount:    movement.Amount,
+			Reason:    movement.Reason.String,
+			CreatedAt: movement.CreatedAt.Time.UTC().Format(time.RFC3339),
+		})
+
+		if movement.Type == repository.ShiftCashMovementsTypeCashIn {
+			response.CashIn += movement.Amount
+		} else {
+			response.CashOut += movement.Amount
+		}
+	}
+
+	response.CashSales = math.Round(response.CashSales*100) / 100
+	response.CashIn = math.Round(response.CashIn*100) / 100
+	response.CashOut = math.Round(response.CashOut*100) / 100
+	response.ExpectedCash = math.Round((shift.OpeningFloat+response.CashSales+response.CashIn-response.CashOut)*100) / 100
+
+	if shift.ExpectedCash.Valid {
+		response.ExpectedCash = shift.ExpectedCash.Float64
+	}
+	if shift.CountedCash.Valid {
+		response.CountedCash = &shift.CountedCash.Float64
+	}
+	if shift.Variance.Valid {
+		response.Variance = &shift.Variance.Float64
+	}
+	if shift.ClosedAt.Valid {
+		closedAt := shift.ClosedAt.Time.UTC().Format(time.RFC3339)
+		response.ClosedAt = &closedAt
+	}
+
+	return response, nil
+}
+
+// writeShiftReport writes the cash-up of a shift as JSON, or as a printable
+// report when a file format is asked for
+func writeShiftReport(w http.ResponseWriter, format string, shift dto.ShiftResponse, status int) {
+	if format == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(shift)
+		return
+	}
+
+	var closedAt interface{}
+	if shift.ClosedAt != nil {
+		closedAt = *shift.ClosedAt
+	}
+
+	rows := [][]interface{}{
+		{"Shift", shift.ID},
+		{"Status", shift.Status},
+		{"Store", shift.Store.Name},
+		{"Cashier", shift.Cashier.Firstname + " " + shift.Cashier.Lastname},
+		{"Opened", shift.OpenedAt},
+		{"Closed", closedAt},
+		{"Opening float", helper.Money(shift.OpeningFloat)},
+	}
+
+	for _, sale := range shift.Sales {
+		rows = append(rows, []interface{}{fmt.Sprintf("Sales by %s (%d orders)", sale.Tender, sale.Orders), helper.Money(sale.Total)})
+	}
+
+	rows = append(rows,
+		[]interface{}{"Cash sales", helper.Money(shift.CashSales)},
+		[]interface{}{"Cash in", helper.Money(shift.CashIn)},
+		[]interface{}{"Cash out", helper.Money(shift.CashOut)},
+		[]interface{}{"Expected cash", helper.Money(shift.ExpectedCash)},
+	)
+
+	if shift.CountedCash != nil {
+		rows = append(rows, []interface{}{"Counted cash", helper.Money(*shift.CountedCash)})
+	}
+	if shift.Variance != nil {
+		rows = append(rows, []interface{}{"Variance", helper.Money(*shift.Variance)})
+	}
+
+	for _, movement := range shift.Movements {
+		rows = append(rows, []interface{}{fmt.Sprintf("%s at %s: %s", movement.Type, movement.CreatedAt, movement.Reason), helper.Money(movement.Amount)})
+	}
+
+	if shift.Notes != "" {
+		rows = append(rows, []interface{}{"Notes", shift.Notes})
+	}
+
+	writeReport(w, format, fmt.Sprintf("shift-%d", shift.ID), []string{"Item", "Value"}, rows)
+}
+
+// findOpenShift loads the open shift of a cashier, writing a 404 when there is none
+func findOpenShift(w http.ResponseWriter, ctx context.Context, repo *repository.Queries, cashierID uint64) (repository.Shift, bool) {
+	shift, err := repo.FindOpenShiftByCashier(ctx, cashierID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "No open shift", http.StatusNotFound)
+		} else {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return shift, false
+	}
+
+	return shift, true
+}
+
+// Open a shift on a till with an opening float
+func (h *shiftHandler) Open(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	cashierID, err := middleware.UserID(ctx)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var form dto.OpenShiftRequest
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(form); err != nil {
+		http.Error(w, validationMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	allowed, err := h.repo.CheckStoreUser(ctx, repository.CheckStoreUserParams{
+		StoreID: form.StoreID,
+		UserID:  cashierID,
+	})
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if !allowed {
+		http.Error(w, "Not allowed to perform this task", http.StatusForbidden)
+		return
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	repo := h.repo.WithTx(tx)
+
+	_, err = repo.FindOpenShiftByCashier(ctx, cashierID)
+	if err == nil {
+		http.Error(w, "You already have an open shift", http.StatusConflict)
+		return
+	}
+	if err != sql.ErrNoRows {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	id, err := repo.InsertShift(ctx, repository.InsertShiftParams{
+		StoreID:      form.StoreID,
+		CashierID:    cashierID,
+		OpeningFloat: form.OpeningFloat,
+	})
+	if err != nil {
+		// Another shift was opened at the same time
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+			http.Error(w, "You already have an open shift", http.StatusConflict)
+			return
+		}
+
+		fmt.Println(err)
+		http.Error(w, "Failed to open shift", http.StatusInternalServerError)
+		return
+	}
+
+	shift, err := repo.FindShift(ctx, uint64(id))
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	response, err := shiftResponse(ctx, repo, shift)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	writeShiftReport(w, "", response, http.StatusCreated)
+}
+
+// Current shows the running cash-up of the cashier's open shift
+func (h *shiftHandler) Current(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	cashierID, err := middleware.UserID(ctx)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	shift, ok := findOpenShift(w, ctx, h.repo, cashierID)
+	if !ok {
+		return
+	}
+
+	response, err := shiftResponse(ctx, h.repo, shift)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	writeShiftReport(w, "", response, http.StatusOK)
+}
+
+// Cash records cash put into or taken out of the till during the open shift
+func (h *shiftHandler) Cash(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	cashierID, err := middleware.UserID(ctx)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var form dto.ShiftCashRequest
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(form); err != nil {
+		http.Error(w, validationMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	repo := h.repo.WithTx(tx)
+
+	shift, ok := findOpenShift(w, ctx, repo, cashierID)
+	if !ok {
+		return
+	}
+
+	movement := repository.ShiftCashMovementsType(form.Type)
+
+	if movement == repository.ShiftCashMovementsTypeCashOut {
+		current, err := shiftResponse(ctx, repo, shift)
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		if form.Amount > current.ExpectedCash {
+			http.Error(w, "There is not enough cash in the till", http.StatusBadRequest)
+			return
+		}
+	}
+
+	_, err = repo.InsertShiftCashMovement(ctx, repository.InsertShiftCashMovementParams{
+		ShiftID: shift.ID,
+		Type:    movement,
+		Amount:  math.Round(form.Amount*100) / 100,
+		Reason:  sql.NullString{String: form.Reason, Valid: form.Reason != ""},
+	})
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to record cash movement", http.StatusInternalServerError)
+		return
+	}
+
+	response, err := shiftResponse(ctx, repo, shift)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	writeShiftReport(w, "", response, http.StatusCreated)
+}
+
+// Close cashes up the open shift against the counted cash. The shift report
+// is returned as JSON, or as a printable file when format is given.
+func (h *shiftHandler) Close(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	cashierID, err := middleware.UserID(ctx)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	format, err := exportFormat(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var form dto.CloseShiftRequest
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(form); err != nil {
+		http.Error(w, validationMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	repo := h.repo.WithTx(tx)
+
+	shift, ok := findOpenShift(w, ctx, repo, cashierID)
+	if !ok {
+		return
+	}
+
+	current, err := shiftResponse(ctx, repo, shift)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	counted := math.Round(*form.CountedCash*100) / 100
+
+	closed, err := repo.CloseShift(ctx, repository.CloseShiftParams{
+		ExpectedCash: sql.NullFloat64{Float64: current.ExpectedCash, Valid: true},
+		CountedCash:  sql.NullFloat64{Float64: counted, Valid: true},
+		Variance:     sql.NullFloat64{Float64: math.Round((counted-current.ExpectedCash)*100) / 100, Valid: true},
+		Notes:        sql.NullString{String: form.Notes, Valid: form.Notes != ""},
+		ID:           shift.ID,
+	})
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to close shift", http.StatusInternalServerError)
+		return
+	}
+
+	if closed == 0 {
+		http.Error(w, "Shift is already closed", http.StatusConflict)
+		return
+	}
+
+	shift, err = repo.FindShift(ctx, shift.ID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	response, err := shiftResponse(ctx, repo, shift)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	writeShiftReport(w, format, response, http.StatusOK)
+}
+
+// CashierFindOne shows the report of one of the cashier's own shifts
+func (h *shiftHandler) CashierFindOne(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	cashierID, err := middleware.UserID(ctx)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	h.findOne(w, r, sql.NullInt64{Int64: int64(cashierID), Valid: true})
+}
+
+// AdminFindOne shows the report of any shift
+func (h *shiftHandler) AdminFindOne(w http.ResponseWriter, r *http.Request) {
+	h.findOne(w, r, sql.NullInt64{})
+}
+
+// findOne writes the report of the shift in the URL, limited to the shifts of
+// cashierID when it is set
+func (h *shiftHandler) findOne(w http.ResponseWriter, r *http.Request, cashierID sql.NullInt64) {
+	ctx := r.Context()
+
+	format, err := exportFormat(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid shift ID", http.StatusBadRequest)
+		return
+	}
+
+	shift, err := h.repo.FindShift(ctx, id)
+	if err == nil && cashierID.Valid && shift.CashierID != uint64(cashierID.Int64) {
+		err = sql.ErrNoRows
+	}
+	if err == nil && !middleware.ContextGetStores(ctx).Allows(shift.StoreID) {
+		err = sql.ErrNoRows
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Shift not found", http.StatusNotFound)
+		} else {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	response, err := shiftResponse(ctx, h.repo, shift)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	writeShiftReport(w, format, response, http.StatusOK)
+}
+
+// AdminFindAll lists shifts, newest first, optionally for a store or cashier
+func (h *shiftHandler) AdminFindAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var storeID, cashierID sql.NullInt64
+
+	if value := r.URL.Query().Get("store_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid store ID", http.StatusBadRequest)
+			return
+		}
+
+		storeID = sql.NullInt64{Int64: id, Valid: true}
+	}
+
+	if value := r.URL.Query().Get("cashier_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid cashier ID", http.StatusBadRequest)
+			return
+		}
+
+		cashierID = sql.NullInt64{Int64: id, Valid: true}
+	}
+
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil {
+		limit = 20
+	}
+	offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+	if err != nil {
+		offset = 0
+	}
+
+	count, err := h.repo.CountShifts(ctx, repository.CountShiftsParams{
+		StoreID:   storeID,
+		CashierID: cashierID,
+		Stores:    storeFilter(ctx),
+	})
+	if err != nil {
+		http.Error(w, "Failed to count shifts", http.StatusInternalServerError)
+		return
+	}
+
+	data, err := h.repo.FindShifts(ctx, repository.FindShiftsParams{
+		StoreID:   storeID,
+		CashierID: cashierID,
+		Stores:    storeFilter(ctx),
+		Limit:     int32(limit),
+		Offset:    int32(offset),
+	})
+	if err != nil {
+		http.Error(w, "Failed to retrieve shifts", http.StatusInternalServerError)
+		return
+	}
+
+	var shifts = []dto.ShiftResponse{}
+
+	for _, shift := range data {
+		response, err := shiftResponse(ctx, h.repo, shift)
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		shifts = append(shifts, response)
+	}
+
+	response := map[string]interface{}{
+		"total":  count,
+		"limit":  limit,
+		"offset": offset,
+		"data":   shifts,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
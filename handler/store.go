@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"api/cmd/middleware"
 	"api/handler/dto"
@@ -27,13 +28,6 @@ func NewStoreHandler(repo *repository.Queries) *storeHandler {
 
 // Create a new store
 func (h *storeHandler) Create(w http.ResponseWriter, r *http.Request) {
-	_, err := middleware.GuardAdmin(r.Context(), h.repo)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		w.Write([]byte("Unauthorized"))
-		return
-	}
-
 	var form dto.CreateStoreRequest
 	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
@@ -59,7 +53,7 @@ func (h *storeHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err = h.repo.FindStoreBySlug(context.Background(), slug.Make(form.Name))
+	_, err := h.repo.FindStoreBySlug(context.Background(), slug.Make(form.Name))
 	if err == nil {
 		http.Error(w, "Store already exists", http.StatusBadRequest)
 		return
@@ -97,13 +91,6 @@ func (h *storeHandler) Create(w http.ResponseWriter, r *http.Request) {
 func (h *storeHandler) AdminFindAll(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
 
-	_, err := middleware.GuardAdmin(r.Context(), h.repo)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		w.Write([]byte("Unauthorized"))
-		return
-	}
-
 	limitStr := r.URL.Query().Get("limit")
 	offsetStr := r.URL.Query().Get("offset")
 
@@ -157,13 +144,6 @@ func (h *storeHandler) AdminFindAll(w http.ResponseWriter, r *http.Request) {
 func (h *storeHandler) AdminFindOne(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
 
-	_, err := middleware.GuardAdmin(r.Context(), h.repo)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		w.Write([]byte("Unauthorized"))
-		return
-	}
-
 	storeIDStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(storeIDStr, 10, 64)
 	if err != nil {
@@ -192,13 +172,6 @@ func (h *storeHandler) AdminFindOne(w http.ResponseWriter, r *http.Request) {
 func (h *storeHandler) AdminUpdate(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
 
-	_, err := middleware.GuardAdmin(r.Context(), h.repo)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		w.Write([]byte("Unauthorized"))
-		return
-	}
-
 	storeIDStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(storeIDStr, 10, 64)
 	if err != nil {
@@ -272,13 +245,6 @@ func (h *storeHandler) AdminUpdate(w http.ResponseWriter, r *http.Request) {
 func (h *storeHandler) AdminDelete(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
 
-	_, err := middleware.GuardAdmin(r.Context(), h.repo)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		w.Write([]byte("Unauthorized"))
-		return
-	}
-
 	storeIDStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(storeIDStr, 10, 64)
 	if err != nil {
@@ -305,3 +271,38 @@ func (h *storeHandler) AdminDelete(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// storeFilter is the stores a query is limited to, as the comma separated
+// list FIND_IN_SET matches against. It is NULL when every store can be seen.
+func storeFilter(ctx context.Context) sql.NullString {
+	scope := middleware.ContextGetStores(ctx)
+	if scope.All {
+		return sql.NullString{}
+	}
+
+	ids := make([]string, len(scope.Stores))
+	for i, id := range scope.Stores {
+		ids[i] = strconv.FormatUint(id, 10)
+	}
+
+	return sql.NullString{String: strings.Join(ids, ","), Valid: true}
+}
+
+// checkStore checks that the request may act on a store, writing the
+// response when it may not
+func checkStore(w http.ResponseWriter, ctx context.Context, storeID uint64) bool {
+	if !middleware.ContextGetStores(ctx).Allows(storeID) {
+		http.Error(w, "You cannot access this store", http.StatusForbidden)
+		return false
+	}
+
+	return true
+}
+
+// canSeeStore reports whether the request may see a record of a store. Records
+// without a store are only seen by callers who can see every store.
+func canSeeStore(ctx context.Context, storeID sql.NullInt64) bool {
+	scope := middleware.ContextGetStores(ctx)
+
+	return scope.All || (storeID.Valid && scope.Allows(uint64(storeID.Int64)))
+}
@@ -0,0 +1,329 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"api/cmd/helper"
+	"api/handler/dto"
+	"api/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator"
+	"github.com/gosimple/slug"
+)
+
+type supplierHandler struct {
+	repo *repository.Queries
+}
+
+func NewSupplierHandler(repo *repository.Queries) *supplierHandler {
+	return &supplierHandler{repo: repo}
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+func supplierResponse(supplier repository.Supplier) dto.SupplierResponse {
+	return dto.SupplierResponse{
+		ID:           supplier.ID,
+		Slug:         supplier.Slug,
+		Name:         supplier.Name,
+		ContactName:  supplier.ContactName.String,
+		Email:        supplier.Email.String,
+		Phone:        supplier.Phone.String,
+		Address:      supplier.Address.String,
+		PaymentTerms: supplier.PaymentTerms.String,
+		LeadTimeDays: supplier.LeadTimeDays.Int32,
+		Status:       supplier.Status,
+	}
+}
+
+func readSupplierForm(r *http.Request) (dto.CreateSupplierRequest, string) {
+	var form dto.CreateSupplierRequest
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		return form, "Invalid request body"
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(form); err != nil {
+		return form, validationMessage(err)
+	}
+
+	return form, ""
+}
+
+// Create a new supplier
+func (h *supplierHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+
+	form, msg := readSupplierForm(r)
+	if msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	_, err := h.repo.FindSupplierBySlug(ctx, slug.Make(form.Name))
+	if err == nil {
+		http.Error(w, "Supplier with this name already exists", http.StatusBadRequest)
+		return
+	}
+
+	id, err := h.repo.InsertSupplier(ctx, repository.InsertSupplierParams{
+		Slug:         slug.Make(form.Name),
+		Name:         form.Name,
+		ContactName:  nullString(form.ContactName),
+		Email:        nullString(form.Email),
+		Phone:        nullString(form.Phone),
+		Address:      nullString(form.Address),
+		PaymentTerms: nullString(form.PaymentTerms),
+		LeadTimeDays: sql.NullInt32{Int32: form.LeadTimeDays, Valid: form.LeadTimeDays > 0},
+		Status:       form.Status,
+	})
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to create supplier", http.StatusInternalServerError)
+		return
+	}
+
+	supplier, err := h.repo.FindSupplier(ctx, uint64(id))
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(supplierResponse(supplier))
+}
+
+// List suppliers with pagination
+func (h *supplierHandler) AdminFindAll(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil {
+		limit = 20
+	}
+	offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+	if err != nil {
+		offset = 0
+	}
+
+	count, err := h.repo.CountSuppliers(ctx)
+	if err != nil {
+		http.Error(w, "Failed to count suppliers", http.StatusInternalServerError)
+		return
+	}
+
+	data, err := h.repo.FindSuppliers(ctx, repository.FindSuppliersParams{
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		http.Error(w, "Failed to retrieve suppliers", http.StatusInternalServerError)
+		return
+	}
+
+	var suppliers = []dto.SupplierResponse{}
+
+	for _, supplier := range data {
+		suppliers = append(suppliers, supplierResponse(supplier))
+	}
+
+	response := map[string]interface{}{
+		"total":  count,
+		"limit":  limit,
+		"offset": offset,
+		"data":   suppliers,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// Get a supplier
+func (h *supplierHandler) AdminFindOne(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid supplier ID", http.StatusBadRequest)
+		return
+	}
+
+	supplier, err := h.repo.FindSupplier(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Supplier not found", http.StatusNotFound)
+		} else {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(supplierResponse(supplier))
+}
+
+// Update a supplier
+func (h *supplierHandler) AdminUpdate(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid supplier ID", http.StatusBadRequest)
+		return
+	}
+
+	supplier, err := h.repo.FindSupplier(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Supplier not found", http.StatusNotFound)
+		} else {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	form, msg := readSupplierForm(r)
+	if msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	if supplier.Slug != slug.Make(form.Name) {
+		_, err = h.repo.FindSupplierBySlug(ctx, slug.Make(form.Name))
+		if err == nil {
+			http.Error(w, "Supplier with this name already exists", http.StatusBadRequest)
+			return
+		}
+	}
+
+	err = h.repo.UpdateSupplier(ctx, repository.UpdateSupplierParams{
+		Slug:         slug.Make(form.Name),
+		Name:         form.Name,
+		ContactName:  nullString(form.ContactName),
+		Email:        nullString(form.Email),
+		Phone:        nullString(form.Phone),
+		Address:      nullString(form.Address),
+		PaymentTerms: nullString(form.PaymentTerms),
+		LeadTimeDays: sql.NullInt32{Int32: form.LeadTimeDays, Valid: form.LeadTimeDays > 0},
+		Status:       form.Status,
+		ID:           supplier.ID,
+	})
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Error updating supplier", http.StatusInternalServerError)
+		return
+	}
+
+	supplier, _ = h.repo.FindSupplier(ctx, supplier.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(supplierResponse(supplier))
+}
+
+// Delete a supplier. Suppliers with purchase orders cannot be deleted.
+func (h *supplierHandler) AdminDelete(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid supplier ID", http.StatusBadRequest)
+		return
+	}
+
+	supplier, err := h.repo.FindSupplier(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Supplier not found", http.StatusNotFound)
+		} else {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	err = h.repo.DeleteSupplier(ctx, supplier.ID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Supplier has purchase orders and cannot be deleted", http.StatusConflict)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AdminSpendReport sums the goods received from each supplier between from and to
+func (h *supplierHandler) AdminSpendReport(w http.ResponseWriter, r *http.Request) {
+	format, err := exportFormat(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	from, to, err := reportRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rows, err := h.repo.FindSupplierSpend(r.Context(), repository.FindSupplierSpendParams{
+		FromDate: from,
+		ToDate:   to,
+	})
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	var spend = []dto.SupplierSpendResponse{}
+	var total float64
+
+	for _, row := range rows {
+		item := dto.SupplierSpendResponse{
+			Supplier:   row.Supplier,
+			Deliveries: row.Deliveries,
+			Units:      row.Units,
+			Spend:      row.Spend,
+		}
+
+		if row.SupplierID.Valid {
+			supplierID := uint64(row.SupplierID.Int64)
+			item.SupplierID = &supplierID
+		}
+
+		total += row.Spend
+		spend = append(spend, item)
+	}
+
+	if format != "" {
+		var rows [][]interface{}
+		for _, item := range spend {
+			rows = append(rows, []interface{}{item.Supplier, item.Deliveries, item.Units, helper.Money(item.Spend)})
+		}
+
+		writeReport(w, format, "supplier-spend", []string{"Supplier", "Deliveries", "Units", "Spend"}, rows)
+		return
+	}
+
+	response := map[string]interface{}{
+		"from":  from.Format(reportDateLayout),
+		"to":    to.AddDate(0, 0, -1).Format(reportDateLayout),
+		"total": total,
+		"data":  spend,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
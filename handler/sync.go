@@ -0,0 +1,416 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"api/cmd/costing"
+	"api/cmd/middleware"
+	"api/handler/dto"
+	"api/repository"
+
+	"github.com/go-playground/validator"
+)
+
+// Outcomes of applying an offline order. A rejected order will never apply
+// and should be dealt with by hand; a failed one can be sent again.
+const (
+	syncCreated   = "created"
+	syncDuplicate = "duplicate"
+	syncRejected  = "rejected"
+	syncFailed    = "failed"
+)
+
+type syncHandler struct {
+	db    *sql.DB
+	repo  *repository.Queries
+	costs *costing.Engine
+}
+
+func NewSyncHandler(db *sql.DB, repo *repository.Queries, costs *costing.Engine) *syncHandler {
+	return &syncHandler{db: db, repo: repo, costs: costs}
+}
+
+// nextOrderNumber follows on from the number of the last order
+func nextOrderNumber(ctx context.Context, repo *repository.Queries) (string, error) {
+	last, err := repo.FindLastCreatedOrder(ctx)
+	if err == sql.ErrNoRows {
+		return incrementNumber("00000")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return incrementNumber(last.Number)
+}
+
+// checkCashierStore checks that the cashier works at the store, writing the
+// error response when not
+func checkCashierStore(w http.ResponseWriter, ctx context.Context, repo *repository.Queries, cashierID, storeID uint64) bool {
+	allowed, err := repo.CheckStoreUser(ctx, repository.CheckStoreUserParams{
+		StoreID: storeID,
+		UserID:  cashierID,
+	})
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return false
+	}
+
+	if !allowed {
+		http.Error(w, "Not allowed to perform this task", http.StatusForbidden)
+		return false
+	}
+
+	return true
+}
+
+// Orders applies a batch of orders made while the till was offline. Orders
+// are applied oldest first, each on its own, and are matched on their client
+// ID so a batch can safely be sent again after a dropped connection.
+func (h *syncHandler) Orders(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	cashierID, err := middleware.UserID(ctx)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var form dto.SyncOrdersRequest
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(form); err != nil {
+		http.Error(w, validationMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	if !checkCashierStore(w, ctx, h.repo, cashierID, form.StoreID) {
+		return
+	}
+
+	results := make([]dto.SyncOrderResult, len(form.Orders))
+	createdAt := make([]time.Time, len(form.Orders))
+	order := make([]int, 0, len(form.Orders))
+	now := time.Now().UTC()
+
+	for i, o := range form.Orders {
+		date, err := time.Parse(time.RFC3339, o.CreatedAt)
+		if err != nil {
+			results[i] = dto.SyncOrderResult{
+				ClientID: o.ClientID,
+				Status:   syncRejected,
+				Error:    "created_at should be an RFC 3339 date",
+			}
+			continue
+		}
+
+		// A till with a clock running fast cannot date sales in the future
+		if date.After(now) {
+			date = now
+		}
+
+		createdAt[i] = date
+		order = append(order, i)
+	}
+
+	// Numbers are handed out in the order the sales were made
+	sort.SliceStable(order, func(a, b int) bool {
+		return createdAt[order[a]].Before(createdAt[order[b]])
+	})
+
+	for _, i := range order {
+		results[i] = h.apply(ctx, cashierID, form.StoreID, form.Orders[i], createdAt[i])
+	}
+
+	response := map[string]interface{}{
+		"results": results,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// apply records one offline order. The sale already took place, so stock,
+// shift and price differences are reported as conflicts rather than refused.
+// Only orders the server cannot record at all are rejected.
+func (h *syncHandler) apply(ctx context.Context, cashierID, storeID uint64, form dto.SyncOrderRequest, createdAt time.Time) dto.SyncOrderResult {
+	result := dto.SyncOrderResult{
+		ClientID:     form.ClientID,
+		ClientNumber: form.Number,
+		Conflicts:    []dto.SyncConflict{},
+	}
+
+	clientID := sql.NullString{String: strings.ToLower(form.ClientID), Valid: true}
+
+	failed := func(err error) dto.SyncOrderResult {
+		fmt.Println(err)
+		return dto.SyncOrderResult{ClientID: form.ClientID, Status: syncFailed, Error: "Something went wrong"}
+	}
+
+	rejected := func(message string) dto.SyncOrderResult {
+		return dto.SyncOrderResult{ClientID: form.ClientID, Status: syncRejected, Error: message}
+	}
+
+	existing, err := h.repo.FindStoreOrderDetailsByClientID(ctx, clientID)
+	if err == nil {
+		o, err := h.repo.FindOrder(ctx, existing.OrderID)
+		if err != nil {
+			return failed(err)
+		}
+
+		result.Status = syncDuplicate
+		result.OrderID = o.ID
+		result.Number = o.Number
+		result.Conflicts = nil
+		return result
+	}
+	if err != sql.ErrNoRows {
+		return failed(err)
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return failed(err)
+	}
+	defer tx.Rollback()
+
+	repo := h.repo.WithTx(tx)
+
+	shiftID := sql.NullInt64{}
+
+	shift, err := repo.FindOpenShiftByCashier(ctx, cashierID)
+	switch {
+	case err == nil && shift.StoreID == storeID:
+		shiftID = sql.NullInt64{Int64: int64(shift.ID), Valid: true}
+	case err == nil || err == sql.ErrNoRows:
+		result.Conflicts = append(result.Conflicts, dto.SyncConflict{
+			Type:    "shift",
+			Message: "No open shift at this store, the order is left out of the cash-up",
+		})
+	default:
+		return failed(err)
+	}
+
+	number, err := nextOrderNumber(ctx, repo)
+	if err != nil {
+		return failed(err)
+	}
+
+	if form.Number != "" && form.Number != number {
+		result.Conflicts = append(result.Conflicts, dto.SyncConflict{
+			Type:    "number",
+			Message: fmt.Sprintf("Order %s was numbered %s", form.Number, number),
+		})
+	}
+
+	tender := repository.InStoreOrderDetailsTenderCash
+	if form.Tender != "" {
+		tender = repository.InStoreOrderDetailsTender(form.Tender)
+	}
+
+	total := calculateTotal(form.Items)
+
+	var tendered sql.NullFloat64
+	if form.Tendered > 0 {
+		tendered = sql.NullFloat64{Float64: form.Tendered, Valid: true}
+	}
+
+	if tender == repository.InStoreOrderDetailsTenderCash && tendered.Valid && tendered.Float64 < total {
+		tendered = sql.NullFloat64{}
+		result.Conflicts = append(result.Conflicts, dto.SyncConflict{
+			Type:    "tender",
+			Message: "Amount tendered is less than the order total and was left out",
+		})
+	}
+
+	orderID, err := repo.InsertSyncedOrder(ctx, repository.InsertSyncedOrderParams{
+		Number:    number,
+		Channel:   repository.OrdersChannelInStore,
+		Status:    repository.OrdersStatusPending,
+		Total:     total,
+		CreatedAt: sql.NullTime{Time: createdAt, Valid: true},
+	})
+	if err != nil {
+		return failed(err)
+	}
+
+	for _, item := range form.Items {
+		if item.Quantity < 1 {
+			return rejected(fmt.Sprintf("The minimum order quantity for item SKU: %s is 1", item.SKU))
+		}
+
+		p, err := findProductByCode(ctx, repo, item.SKU)
+		if err == sql.ErrNoRows {
+			return rejected(fmt.Sprintf("Product not found: %s", item.SKU))
+		}
+		if err != nil {
+			return failed(err)
+		}
+
+		if !p.Status {
+			result.Conflicts = append(result.Conflicts, dto.SyncConflict{
+				Type:    "product",
+				SKU:     p.Sku,
+				Message: "Product is no longer for sale",
+			})
+		}
+
+		stock, err := repo.FindProductStock(ctx, p.ID)
+		if err != nil {
+			return failed(err)
+		}
+
+		if item.Quantity > stock.Remaining {
+			result.Conflicts = append(result.Conflicts, dto.SyncConflict{
+				Type:      "stock",
+				SKU:       p.Sku,
+				Requested: item.Quantity,
+				Available: max(stock.Remaining, 0),
+				Message:   "Sold more than the recorded stock",
+			})
+		}
+
+		cogs, err := h.costs.Issue(ctx, repo, p.ID, sql.NullInt64{Int64: int64(storeID), Valid: true}, item.Quantity)
+		if err != nil {
+			return failed(err)
+		}
+
+		err = repo.InsertOrderItem(ctx, repository.InsertOrderItemParams{
+			OrderID:   uint64(orderID),
+			ProductID: p.ID,
+			Quantity:  item.Quantity,
+			Price:     item.Price,
+			Cogs:      sql.NullFloat64{Float64: cogs, Valid: true},
+		})
+		if err != nil {
+			return failed(err)
+		}
+	}
+
+	err = repo.InsertInStoreOrderDetails(ctx, repository.InsertInStoreOrderDetailsParams{
+		OrderID:      uint64(orderID),
+		CashierID:    sql.NullInt64{Int64: int64(cashierID), Valid: true},
+		StoreID:      storeID,
+		ShiftID:      shiftID,
+		Tender:       tender,
+		Tendered:     tendered,
+		ClientID:     clientID,
+		ClientNumber: sql.NullString{String: form.Number, Valid: form.Number != ""},
+	})
+	if err != nil {
+		return failed(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return failed(err)
+	}
+
+	result.Status = syncCreated
+	result.OrderID = uint64(orderID)
+	result.Number = number
+	return result
+}
+
+// Catalogue returns the products, prices and store stock that changed since
+// the cursor of an earlier call, or everything when no cursor is given. The
+// cursor returned is sent back on the next call. Changes made in the second
+// the cursor was taken may be sent twice, so the POS should upsert.
+func (h *syncHandler) Catalogue(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	cashierID, err := middleware.UserID(ctx)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	storeID, err := strconv.ParseUint(r.URL.Query().Get("store_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid store ID", http.StatusBadRequest)
+		return
+	}
+
+	since := time.Unix(0, 0).UTC()
+
+	if value := r.URL.Query().Get("cursor"); value != "" {
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid cursor", http.StatusBadRequest)
+			return
+		}
+
+		since = time.Unix(seconds, 0).UTC()
+	}
+
+	if !checkCashierStore(w, ctx, h.repo, cashierID, storeID) {
+		return
+	}
+
+	// Taken before reading so nothing changed during the reads is missed
+	cursor := time.Now().UTC().Truncate(time.Second)
+
+	products, err := h.repo.FindProductsChangedSince(ctx, sql.NullTime{Time: since, Valid: true})
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	stock, err := h.repo.FindStoreStockChangedSince(ctx, repository.FindStoreStockChangedSinceParams{
+		StoreID: sql.NullInt64{Int64: int64(storeID), Valid: true},
+		Since:   sql.NullTime{Time: since, Valid: true},
+	})
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	response := dto.SyncCatalogueResponse{
+		Cursor:   strconv.FormatInt(cursor.Unix(), 10),
+		Products: []dto.SyncProductResponse{},
+		Stock:    []dto.SyncStockResponse{},
+	}
+
+	for _, p := range products {
+		product := dto.SyncProductResponse{
+			ID:         p.ID,
+			SKU:        p.Sku,
+			Name:       p.Name,
+			CategoryID: nullID(p.CategoryID),
+			Price:      nullFloat(p.Price),
+			Status:     p.Status,
+		}
+
+		updatedAt := p.UpdatedAt
+		if !updatedAt.Valid {
+			updatedAt = p.CreatedAt
+		}
+		product.UpdatedAt = updatedAt.Time.UTC().Format(time.RFC3339)
+
+		response.Products = append(response.Products, product)
+	}
+
+	for _, s := range stock {
+		response.Stock = append(response.Stock, dto.SyncStockResponse{
+			ProductID: s.ProductID,
+			SKU:       s.Sku,
+			Quantity:  s.Quantity,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -4,34 +4,37 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"api/cmd/middleware"
+	"api/cmd/revocation"
 	"api/handler/dto"
 	"api/repository"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator"
+	"github.com/go-sql-driver/mysql"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type userHandler struct {
-	repo *repository.Queries
+	db          *sql.DB
+	repo        *repository.Queries
+	revocations revocation.Store
 }
 
-func NewUserHandler(repo *repository.Queries) *userHandler {
-	return &userHandler{repo: repo}
+func NewUserHandler(db *sql.DB, repo *repository.Queries, revocations revocation.Store) *userHandler {
+	return &userHandler{db: db, repo: repo, revocations: revocations}
 }
 
 func (h *userHandler) AdminAssignStoreUser(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
-	_, err := middleware.GuardAdmin(r.Context(), h.repo)
-	if err != nil {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
-
 	var form dto.AssignStoreUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
@@ -57,7 +60,41 @@ func (h *userHandler) AdminAssignStoreUser(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	user, err := h.repo.FindUserByEmail(ctx, form.Email)
+	if !checkStore(w, r.Context(), form.StoreID) {
+		return
+	}
+
+	// Users join a store as cashiers unless another role is given
+	if form.Role == "" {
+		form.Role = "cashier"
+	}
+
+	// Store managers can add cashiers, other roles are given by those who
+	// manage roles
+	if form.Role != "cashier" {
+		callerID, err := middleware.UserID(r.Context())
+		if err != nil {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		allowed, err := h.repo.CheckUserPermission(ctx, repository.CheckUserPermissionParams{
+			UserID: callerID,
+			Name:   "roles:manage",
+		})
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		if !allowed {
+			http.Error(w, "You can only add cashiers", http.StatusForbidden)
+			return
+		}
+	}
+
+	user, err := h.repo.FindUserByEmail(ctx, sql.NullString{String: form.Email, Valid: true})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			http.Error(w, "User not found", http.StatusNotFound)
@@ -79,29 +116,37 @@ func (h *userHandler) AdminAssignStoreUser(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	role, err := h.repo.FindRoleByName(ctx, "cashier")
+	role, err := h.repo.FindRoleByName(ctx, form.Role)
 	if err != nil {
-		fmt.Printf("error: %s", err.Error())
-		http.Error(w, "Something went wrong", http.StatusInternalServerError)
-		return
-	}
-
-	hasRole, err := h.repo.CheckUserRole(ctx, repository.CheckUserRoleParams{
-		UserID: user.ID,
-		RoleID: role.ID,
-	})
-	if err != nil {
-		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		if err == sql.ErrNoRows {
+			http.Error(w, "Role not found", http.StatusNotFound)
+		} else {
+			fmt.Printf("error: %s", err.Error())
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		}
 		return
 	}
 
-	if !hasRole {
-		err = h.repo.AssignUserRole(ctx, repository.AssignUserRoleParams{UserID: user.ID, RoleID: role.ID})
+	// The cashier endpoints check the cashier role itself, the store decides
+	// where the cashier may sell
+	if role.Name == "cashier" {
+		hasRole, err := h.repo.CheckUserRole(ctx, repository.CheckUserRoleParams{
+			UserID: user.ID,
+			RoleID: role.ID,
+		})
 		if err != nil {
-			fmt.Printf("error: %s", err.Error())
 			http.Error(w, "Something went wrong", http.StatusInternalServerError)
 			return
 		}
+
+		if !hasRole {
+			err = h.repo.AssignUserRole(ctx, repository.AssignUserRoleParams{UserID: user.ID, RoleID: role.ID})
+			if err != nil {
+				fmt.Printf("error: %s", err.Error())
+				http.Error(w, "Something went wrong", http.StatusInternalServerError)
+				return
+			}
+		}
 	}
 
 	exits, err := h.repo.CheckStoreUser(ctx, repository.CheckStoreUserParams{
@@ -124,6 +169,7 @@ func (h *userHandler) AdminAssignStoreUser(w http.ResponseWriter, r *http.Reques
 	err = h.repo.AssignStoreUser(ctx, repository.AssignStoreUserParams{
 		StoreID: store.ID,
 		UserID:  user.ID,
+		RoleID:  role.ID,
 	})
 	if err != nil {
 		http.Error(w, "Something went wrong", http.StatusInternalServerError)
@@ -135,12 +181,6 @@ func (h *userHandler) AdminAssignStoreUser(w http.ResponseWriter, r *http.Reques
 
 func (h *userHandler) AdminFindStoreUsers(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
-	_, err := middleware.GuardAdmin(r.Context(), h.repo)
-	if err != nil {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
-
 	// Get the category ID from URL parameters
 	storeIDStr := chi.URLParam(r, "id")
 	fmt.Println(storeIDStr)
@@ -150,6 +190,10 @@ func (h *userHandler) AdminFindStoreUsers(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if !checkStore(w, r.Context(), id) {
+		return
+	}
+
 	u, err := h.repo.FindStoreUsers(ctx, id)
 	if err != nil {
 		fmt.Println(err)
@@ -164,7 +208,7 @@ func (h *userHandler) AdminFindStoreUsers(w http.ResponseWriter, r *http.Request
 			ID:        us.ID,
 			Firstname: us.Firstname,
 			Lastname:  us.Lastname,
-			Email:     us.Email,
+			Email:     us.Email.String,
 		})
 	}
 
@@ -174,12 +218,6 @@ func (h *userHandler) AdminFindStoreUsers(w http.ResponseWriter, r *http.Request
 
 func (h *userHandler) AdminDeleteStoreUser(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
-	_, err := middleware.GuardAdmin(r.Context(), h.repo)
-	if err != nil {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
-
 	// Get the category ID from URL parameters
 	storeIDStr := chi.URLParam(r, "storeID")
 	storeID, err := strconv.ParseUint(storeIDStr, 10, 64)
@@ -188,6 +226,10 @@ func (h *userHandler) AdminDeleteStoreUser(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	if !checkStore(w, r.Context(), storeID) {
+		return
+	}
+
 	// Get the category ID from URL parameters
 	userIDStr := chi.URLParam(r, "userID")
 	userID, err := strconv.ParseUint(userIDStr, 10, 64)
@@ -218,21 +260,43 @@ func (h *userHandler) AdminDeleteStoreUser(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	exits, err := h.repo.CheckStoreUser(ctx, repository.CheckStoreUserParams{
+	role, err := h.repo.FindStoreUserRole(ctx, repository.FindStoreUserRoleParams{
 		StoreID: store.ID,
 		UserID:  user.ID,
 	})
 	if err != nil {
-		if err != sql.ErrNoRows {
+		if err == sql.ErrNoRows {
+			http.Error(w, "User not assigned", http.StatusConflict)
+		} else {
 			fmt.Println(err)
 			http.Error(w, "Something went wrong", http.StatusInternalServerError)
-			return
 		}
+		return
 	}
 
-	if !exits {
-		http.Error(w, "User not assigned", http.StatusConflict)
-		return
+	// Store managers can remove cashiers, other roles are taken away by those
+	// who manage roles
+	if role != "cashier" {
+		callerID, err := middleware.UserID(r.Context())
+		if err != nil {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		allowed, err := h.repo.CheckUserPermission(ctx, repository.CheckUserPermissionParams{
+			UserID: callerID,
+			Name:   "roles:manage",
+		})
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		if !allowed {
+			http.Error(w, "You can only remove cashiers", http.StatusForbidden)
+			return
+		}
 	}
 
 	err = h.repo.DeleteStoreUser(ctx, repository.DeleteStoreUserParams{
@@ -244,5 +308,407 @@ func (h *userHandler) AdminDeleteStoreUser(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	// Sign the cashier out so the removal takes effect straight away
+	if err := revokeUserSessions(ctx, h.repo, h.revocations, user.ID); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// adminUserResponse describes a user with their roles for admins
+func (h *userHandler) adminUserResponse(ctx context.Context, user repository.User) (dto.AdminUserResponse, error) {
+	roles, err := h.repo.FindUserRoles(ctx, user.ID)
+	if err != nil {
+		return dto.AdminUserResponse{}, err
+	}
+
+	response := dto.AdminUserResponse{
+		ID:        user.ID,
+		Firstname: user.Firstname,
+		Lastname:  user.Lastname,
+		Roles:     []dto.RoleResponse{},
+	}
+
+	if user.Email.Valid {
+		response.Email = &user.Email.String
+	}
+
+	if user.Phone.Valid {
+		response.Phone = &user.Phone.String
+	}
+
+	if user.EmailVerifiedAt.Valid {
+		verifiedAt := user.EmailVerifiedAt.Time.UTC().Format(time.RFC3339)
+		response.EmailVerifiedAt = &verifiedAt
+	}
+
+	if user.PhoneVerifiedAt.Valid {
+		verifiedAt := user.PhoneVerifiedAt.Time.UTC().Format(time.RFC3339)
+		response.PhoneVerifiedAt = &verifiedAt
+	}
+
+	if user.DeactivatedAt.Valid {
+		deactivatedAt := user.DeactivatedAt.Time.UTC().Format(time.RFC3339)
+		response.DeactivatedAt = &deactivatedAt
+	}
+
+	if user.DeletedAt.Valid {
+		deletedAt := user.DeletedAt.Time.UTC().Format(time.RFC3339)
+		response.DeletedAt = &deletedAt
+	}
+
+	for _, role := range roles {
+		response.Roles = append(response.Roles, dto.RoleResponse{ID: role.ID, Name: role.Name})
+	}
+
+	return response, nil
+}
+
+// Retrieve users, newest first, optionally searching their names, email and
+// phone
+func (h *userHandler) AdminFindAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	var (
+		users []repository.User
+		count int64
+	)
+
+	if search := strings.TrimSpace(r.URL.Query().Get("search")); search != "" {
+		pattern := "%" + search + "%"
+
+		count, err = h.repo.CountSearchUsers(ctx, pattern)
+		if err == nil {
+			users, err = h.repo.SearchUsers(ctx, repository.SearchUsersParams{
+				Search: pattern,
+				Limit:  int32(limit),
+				Offset: int32(offset),
+			})
+		}
+	} else {
+		count, err = h.repo.CountUsers(ctx)
+		if err == nil {
+			users, err = h.repo.FindUsers(ctx, repository.FindUsersParams{
+				Limit:  int32(limit),
+				Offset: int32(offset),
+			})
+		}
+	}
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	var data = []dto.AdminUserResponse{}
+
+	for _, user := range users {
+		u, err := h.adminUserResponse(ctx, user)
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		data = append(data, u)
+	}
+
+	response := map[string]interface{}{
+		"total":  count,
+		"limit":  limit,
+		"offset": offset,
+		"data":   data,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// Retrieve a user with their roles
+func (h *userHandler) AdminFindOne(w http.ResponseWriter, r *http.Request) {
+	user, ok := findUser(w, r, h.repo)
+	if !ok {
+		return
+	}
+
+	response, err := h.adminUserResponse(r.Context(), user)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// Create a staff account, with the roles it starts with. Giving roles needs
+// the permission to manage them.
+func (h *userHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var form dto.CreateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(form); err != nil {
+		http.Error(w, validationMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	if len(form.Roles) > 0 {
+		callerID, err := middleware.UserID(ctx)
+		if err != nil {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		allowed, err := h.repo.CheckUserPermission(ctx, repository.CheckUserPermissionParams{
+			UserID: callerID,
+			Name:   "roles:manage",
+		})
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			return
+		}
+
+		if !allowed {
+			http.Error(w, "You cannot give roles", http.StatusForbidden)
+			return
+		}
+	}
+
+	password, err := bcrypt.GenerateFromPassword([]byte(form.Password), bcrypt.DefaultCost)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	var phone sql.NullString
+	if form.Phone != nil {
+		phone = sql.NullString{String: *form.Phone, Valid: true}
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	repo := h.repo.WithTx(tx)
+
+	userID, err := repo.InsertStaffUser(ctx, repository.InsertStaffUserParams{
+		Firstname: form.Firstname,
+		Lastname:  form.Lastname,
+		Email:     sql.NullString{String: form.Email, Valid: true},
+		Phone:     phone,
+		Password:  string(password),
+	})
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+			http.Error(w, "User already exists", http.StatusConflict)
+			return
+		}
+
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	for _, roleID := range slices.Compact(slices.Sorted(slices.Values(form.Roles))) {
+		if _, err := repo.FindRole(ctx, roleID); err != nil {
+			if err == sql.ErrNoRows {
+				http.Error(w, "Role not found", http.StatusNotFound)
+			} else {
+				fmt.Println(err)
+				http.Error(w, "Something went wrong", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		if err := repo.AssignUserRole(ctx, repository.AssignUserRoleParams{
+			UserID: uint64(userID),
+			RoleID: roleID,
+		}); err != nil {
+			fmt.Println(err)
+			http.Error(w, "Failed to assign role", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	user, err := repo.FindUserByID(ctx, uint64(userID))
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	response, err := h.adminUserResponse(ctx, user)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// Update the name and phone of a user. A new phone has to be verified again.
+func (h *userHandler) AdminUpdate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := findUser(w, r, h.repo)
+	if !ok {
+		return
+	}
+
+	var form dto.UpdateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&form); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(form); err != nil {
+		http.Error(w, validationMessage(err), http.StatusBadRequest)
+		return
+	}
+
+	var phone sql.NullString
+	if form.Phone != nil {
+		phone = sql.NullString{String: *form.Phone, Valid: true}
+	}
+
+	// Users who registered with their phone sign in with it
+	if !phone.Valid && !user.Email.Valid {
+		http.Error(w, "Phone is required for users without an email", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.UpdateUser(ctx, repository.UpdateUserParams{
+		Firstname: form.Firstname,
+		Lastname:  form.Lastname,
+		Phone:     phone,
+		ID:        user.ID,
+	}); err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+			http.Error(w, "Phone is used by another account", http.StatusConflict)
+			return
+		}
+
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	user, err := h.repo.FindUserByID(ctx, user.ID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	response, err := h.adminUserResponse(ctx, user)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// Stop a user from signing in and sign them out. Their orders, shifts and
+// other records are kept.
+func (h *userHandler) AdminDeactivate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := findUser(w, r, h.repo)
+	if !ok {
+		return
+	}
+
+	adminID, err := middleware.UserID(ctx)
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if user.ID == adminID {
+		http.Error(w, "You cannot deactivate yourself", http.StatusConflict)
+		return
+	}
+
+	deactivated, err := h.repo.DeactivateUser(ctx, user.ID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to deactivate user", http.StatusInternalServerError)
+		return
+	}
+
+	if deactivated == 0 {
+		http.Error(w, "User is already deactivated", http.StatusConflict)
+		return
+	}
+
+	if err := revokeUserSessions(ctx, h.repo, h.revocations, user.ID); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Something went wrong", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Let a deactivated user sign in again
+func (h *userHandler) AdminReactivate(w http.ResponseWriter, r *http.Request) {
+	user, ok := findUser(w, r, h.repo)
+	if !ok {
+		return
+	}
+
+	reactivated, err := h.repo.ReactivateUser(r.Context(), user.ID)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to reactivate user", http.StatusInternalServerError)
+		return
+	}
+
+	if reactivated == 0 {
+		http.Error(w, "User is not deactivated", http.StatusConflict)
+		return
+	}
+
 	w.WriteHeader(http.StatusNoContent)
 }
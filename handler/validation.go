@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"fmt"
+
+	"github.com/go-playground/validator"
+)
+
+// validationMessage is the message for the client of the last failed rule of a
+// validated struct
+func validationMessage(err error) string {
+	var msg string
+
+	for _, err := range err.(validator.ValidationErrors) {
+		switch err.Tag() {
+		case "required":
+			msg = fmt.Sprintf("%s is a required field", err.Field())
+		case "required_without":
+			msg = fmt.Sprintf("%s is required without %s", err.Field(), err.Param())
+		case "gte":
+			msg = fmt.Sprintf("%s should at least be greater than %s", err.Field(), err.Param())
+		case "gt":
+			msg = fmt.Sprintf("%s should be greater than %s", err.Field(), err.Param())
+		case "lte":
+			msg = fmt.Sprintf("%s should at most be %s", err.Field(), err.Param())
+		case "min":
+			msg = fmt.Sprintf("%s should have at least %s item", err.Field(), err.Param())
+		case "oneof":
+			msg = fmt.Sprintf("%s should be one of %s", err.Field(), err.Param())
+		case "email":
+			msg = fmt.Sprintf("%s provided is invalid", err.Field())
+		case "max":
+			msg = fmt.Sprintf("%s should not exceed %s", err.Field(), err.Param())
+		case "uuid":
+			msg = fmt.Sprintf("%s should be a UUID", err.Field())
+		case "e164":
+			msg = fmt.Sprintf("%s should be a phone number like +265991234567", err.Field())
+		case "len":
+			msg = fmt.Sprintf("%s should be %s characters long", err.Field(), err.Param())
+		case "alpha":
+			msg = fmt.Sprintf("%s should only contain letters", err.Field())
+		default:
+			msg = fmt.Sprintf("%s is invalid", err.Field())
+		}
+	}
+
+	return msg
+}
@@ -1,14 +1,23 @@
 package main
 
 import (
+	"api/cmd/costing"
 	"api/cmd/helper"
+	"api/cmd/jobs"
+	mid "api/cmd/middleware"
+	"api/cmd/revocation"
 	"api/cmd/router"
+	"api/cmd/sms"
+	"api/cmd/storage"
+	"api/cmd/throttle"
 	"api/database"
+	"api/repository"
 	"context"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"time"
 
 	_ "github.com/joho/godotenv/autoload"
 )
@@ -27,11 +36,63 @@ func main() {
 		log.Fatal(err)
 	}
 
-	server := router.New(database.DB, issuer)
+	blobs, err := storage.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	costs, err := costing.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Costs are replayed from the history the first time the server starts
+	// after the costing migration
+	rebuilt, err := costs.Prepare(context.Background(), database.DB)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if rebuilt {
+		log.Println("rebuilt stock costs from purchases and sales")
+	}
+
+	revocations, err := revocation.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	attempts, err := throttle.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	verifier, err := sms.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	server := router.New(database.DB, issuer, blobs, costs, revocations, verifier, attempts)
 
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer cancel()
 
+	// Remove orphaned images in the background when an interval is configured
+	if interval, err := time.ParseDuration(os.Getenv("IMAGE_GC_INTERVAL")); err == nil && interval > 0 {
+		collector := jobs.NewImageCollector(repository.New(database.DB), blobs, jobs.ImageGCGrace())
+		go collector.Run(ctx, interval)
+	}
+
+	// Alert about items at their reorder point when an interval is configured
+	if interval, err := time.ParseDuration(os.Getenv("STOCK_CHECK_INTERVAL")); err == nil && interval > 0 {
+		checker := jobs.NewStockChecker(database.DB, jobs.StockNotifiers()...)
+		go checker.Run(ctx, interval)
+	}
+
+	// Expired idempotency keys are removed as often as they expire, at least hourly
+	sweep := min(mid.IdempotencyKeyTTL(), time.Hour)
+	go jobs.NewIdempotencySweeper(repository.New(database.DB)).Run(ctx, sweep)
+
 	if err := server.Serve(ctx); err != nil {
 		log.Fatal(err)
 	}
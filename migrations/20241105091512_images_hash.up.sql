@@ -0,0 +1,2 @@
+ALTER TABLE images
+    ADD COLUMN hash CHAR(64) UNIQUE AFTER name;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS import_jobs(
+    id bigint unsigned NOT NULL AUTO_INCREMENT,
+    kind VARCHAR(50) NOT NULL,
+    status ENUM('pending', 'running', 'completed', 'failed') NOT NULL,
+    total int NOT NULL DEFAULT 0,
+    processed int NOT NULL DEFAULT 0,
+    inserted int NOT NULL DEFAULT 0,
+    updated int NOT NULL DEFAULT 0,
+    errors JSON,
+    user_id bigint unsigned,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP NULL,
+    PRIMARY KEY(`id`),
+    FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE SET NULL
+);
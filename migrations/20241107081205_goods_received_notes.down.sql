@@ -0,0 +1,5 @@
+ALTER TABLE purchases
+    DROP FOREIGN KEY purchases_goods_received_note_fk,
+    DROP COLUMN goods_received_note_id;
+
+DROP TABLE IF EXISTS goods_received_notes;
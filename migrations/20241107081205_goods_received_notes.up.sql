@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS goods_received_notes(
+    id bigint unsigned NOT NULL AUTO_INCREMENT,
+    supplier VARCHAR(255) NOT NULL,
+    invoice_number VARCHAR(100) NOT NULL,
+    store_id bigint unsigned,
+    date TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    lines_count int NOT NULL DEFAULT 0,
+    total_quantity int NOT NULL DEFAULT 0,
+    total_cost DOUBLE NOT NULL DEFAULT 0,
+    user_id bigint unsigned,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(`id`),
+    UNIQUE KEY `supplier_invoice` (`supplier`, `invoice_number`),
+    FOREIGN KEY (`store_id`) REFERENCES `stores` (`id`) ON DELETE SET NULL,
+    FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE SET NULL
+);
+
+ALTER TABLE purchases
+    ADD COLUMN goods_received_note_id bigint unsigned,
+    ADD CONSTRAINT `purchases_goods_received_note_fk` FOREIGN KEY (`goods_received_note_id`) REFERENCES `goods_received_notes` (`id`) ON DELETE CASCADE;
@@ -0,0 +1,9 @@
+ALTER TABLE goods_received_notes
+    DROP FOREIGN KEY goods_received_notes_purchase_order_fk,
+    DROP FOREIGN KEY goods_received_notes_supplier_fk,
+    DROP COLUMN purchase_order_id,
+    DROP COLUMN supplier_id;
+
+DROP TABLE IF EXISTS purchase_order_items;
+DROP TABLE IF EXISTS purchase_orders;
+DROP TABLE IF EXISTS suppliers;
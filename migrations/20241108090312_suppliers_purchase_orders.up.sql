@@ -0,0 +1,52 @@
+CREATE TABLE IF NOT EXISTS suppliers(
+    id bigint unsigned NOT NULL AUTO_INCREMENT,
+    slug VARCHAR(255) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    contact_name VARCHAR(255),
+    email VARCHAR(255),
+    phone VARCHAR(50),
+    address TEXT,
+    payment_terms VARCHAR(100),
+    lead_time_days int,
+    status BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NULL,
+    PRIMARY KEY(`id`)
+);
+
+CREATE TABLE IF NOT EXISTS purchase_orders(
+    id bigint unsigned NOT NULL AUTO_INCREMENT,
+    number VARCHAR(20) NOT NULL UNIQUE,
+    supplier_id bigint unsigned NOT NULL,
+    store_id bigint unsigned,
+    status ENUM('draft', 'sent', 'partially_received', 'received', 'closed') NOT NULL DEFAULT 'draft',
+    expected_at TIMESTAMP NULL,
+    notes TEXT,
+    user_id bigint unsigned,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NULL,
+    PRIMARY KEY(`id`),
+    FOREIGN KEY (`supplier_id`) REFERENCES `suppliers` (`id`),
+    FOREIGN KEY (`store_id`) REFERENCES `stores` (`id`) ON DELETE SET NULL,
+    FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS purchase_order_items(
+    id bigint unsigned NOT NULL AUTO_INCREMENT,
+    purchase_order_id bigint unsigned NOT NULL,
+    product_id bigint unsigned NOT NULL,
+    quantity int NOT NULL,
+    received int NOT NULL DEFAULT 0,
+    order_price FLOAT NOT NULL,
+    selling_price FLOAT NOT NULL,
+    PRIMARY KEY(`id`),
+    UNIQUE KEY `purchase_order_product` (`purchase_order_id`, `product_id`),
+    FOREIGN KEY (`purchase_order_id`) REFERENCES `purchase_orders` (`id`) ON DELETE CASCADE,
+    FOREIGN KEY (`product_id`) REFERENCES `products` (`id`)
+);
+
+ALTER TABLE goods_received_notes
+    ADD COLUMN supplier_id bigint unsigned,
+    ADD COLUMN purchase_order_id bigint unsigned,
+    ADD CONSTRAINT `goods_received_notes_supplier_fk` FOREIGN KEY (`supplier_id`) REFERENCES `suppliers` (`id`) ON DELETE SET NULL,
+    ADD CONSTRAINT `goods_received_notes_purchase_order_fk` FOREIGN KEY (`purchase_order_id`) REFERENCES `purchase_orders` (`id`) ON DELETE SET NULL;
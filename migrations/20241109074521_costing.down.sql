@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS costing_rebuilds;
+
+DROP TABLE IF EXISTS stock_costs;
+
+ALTER TABLE online_order_details
+    DROP FOREIGN KEY online_order_details_store_fk,
+    DROP COLUMN store_id;
+
+ALTER TABLE order_items
+    DROP COLUMN cogs;
+
+ALTER TABLE purchases
+    DROP COLUMN remaining;
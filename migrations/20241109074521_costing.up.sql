@@ -0,0 +1,31 @@
+ALTER TABLE purchases
+    ADD COLUMN remaining int NOT NULL DEFAULT 0;
+
+ALTER TABLE order_items
+    ADD COLUMN cogs DOUBLE;
+
+ALTER TABLE online_order_details
+    ADD COLUMN store_id bigint unsigned,
+    ADD CONSTRAINT `online_order_details_store_fk` FOREIGN KEY (`store_id`) REFERENCES `stores` (`id`) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS stock_costs(
+    product_id bigint unsigned NOT NULL,
+    store_id bigint unsigned NOT NULL,
+    quantity int NOT NULL DEFAULT 0,
+    average_cost DOUBLE NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY(`product_id`, `store_id`),
+    FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE,
+    FOREIGN KEY (`store_id`) REFERENCES `stores` (`id`) ON DELETE CASCADE
+);
+
+-- Cost layers, average costs and the cost of order items are filled in by
+-- replaying purchases and sales. The server does so on start up until a
+-- rebuild has been recorded here.
+CREATE TABLE IF NOT EXISTS costing_rebuilds(
+    id bigint unsigned NOT NULL AUTO_INCREMENT,
+    purchases int NOT NULL,
+    order_items int NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(`id`)
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS stock_alert_deliveries;
+DROP TABLE IF EXISTS stock_alerts;
+DROP TABLE IF EXISTS reorder_points;
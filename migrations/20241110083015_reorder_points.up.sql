@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS reorder_points(
+    id bigint unsigned NOT NULL AUTO_INCREMENT,
+    product_id bigint unsigned NOT NULL,
+    store_id bigint unsigned,
+    reorder_point int NOT NULL,
+    reorder_quantity int NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NULL,
+    PRIMARY KEY(`id`),
+    FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE,
+    FOREIGN KEY (`store_id`) REFERENCES `stores` (`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS stock_alerts(
+    id bigint unsigned NOT NULL AUTO_INCREMENT,
+    product_id bigint unsigned NOT NULL,
+    store_id bigint unsigned NOT NULL,
+    quantity int NOT NULL,
+    reorder_point int NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP NULL,
+    PRIMARY KEY(`id`),
+    FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE,
+    FOREIGN KEY (`store_id`) REFERENCES `stores` (`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS stock_alert_deliveries(
+    alert_id bigint unsigned NOT NULL,
+    notifier VARCHAR(50) NOT NULL,
+    delivered_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(`alert_id`, `notifier`),
+    FOREIGN KEY (`alert_id`) REFERENCES `stock_alerts` (`id`) ON DELETE CASCADE
+);
@@ -0,0 +1,5 @@
+ALTER TABLE order_items
+    DROP INDEX order_items_order_product_idx;
+
+ALTER TABLE orders
+    DROP INDEX orders_created_at_idx;
@@ -0,0 +1,5 @@
+ALTER TABLE orders
+    ADD INDEX orders_created_at_idx (created_at, status);
+
+ALTER TABLE order_items
+    ADD INDEX order_items_order_product_idx (order_id, product_id, quantity, total);
@@ -0,0 +1,7 @@
+ALTER TABLE in_store_order_details
+    DROP FOREIGN KEY in_store_order_details_shift_fk,
+    DROP COLUMN tender,
+    DROP COLUMN shift_id;
+
+DROP TABLE IF EXISTS shift_cash_movements;
+DROP TABLE IF EXISTS shifts;
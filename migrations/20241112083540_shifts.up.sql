@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS shifts(
+    id bigint unsigned NOT NULL AUTO_INCREMENT,
+    store_id bigint unsigned NOT NULL,
+    cashier_id bigint unsigned NOT NULL,
+    status ENUM('open', 'closed') NOT NULL DEFAULT 'open',
+    opening_float DOUBLE NOT NULL DEFAULT 0,
+    expected_cash DOUBLE,
+    counted_cash DOUBLE,
+    variance DOUBLE,
+    notes TEXT,
+    opened_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    closed_at TIMESTAMP NULL,
+    open_cashier_id bigint unsigned AS (IF(status = 'open', cashier_id, NULL)),
+    PRIMARY KEY(`id`),
+    KEY `shifts_cashier_status_idx` (`cashier_id`, `status`),
+    UNIQUE KEY `shifts_open_cashier_idx` (`open_cashier_id`),
+    FOREIGN KEY (`store_id`) REFERENCES `stores` (`id`) ON DELETE CASCADE,
+    FOREIGN KEY (`cashier_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS shift_cash_movements(
+    id bigint unsigned NOT NULL AUTO_INCREMENT,
+    shift_id bigint unsigned NOT NULL,
+    type ENUM('cash_in', 'cash_out') NOT NULL,
+    amount DOUBLE NOT NULL,
+    reason VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(`id`),
+    FOREIGN KEY (`shift_id`) REFERENCES `shifts` (`id`) ON DELETE CASCADE
+);
+
+ALTER TABLE in_store_order_details
+    ADD COLUMN shift_id bigint unsigned,
+    ADD COLUMN tender ENUM('cash', 'card', 'mobile_money') NOT NULL DEFAULT 'cash',
+    ADD CONSTRAINT `in_store_order_details_shift_fk` FOREIGN KEY (`shift_id`) REFERENCES `shifts` (`id`) ON DELETE SET NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE in_store_order_details
+    DROP COLUMN tendered;
+
+DROP TABLE IF EXISTS receipt_templates;
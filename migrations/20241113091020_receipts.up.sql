@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS receipt_templates(
+    store_id bigint unsigned NOT NULL,
+    header TEXT,
+    footer TEXT,
+    tax_label VARCHAR(50) NOT NULL DEFAULT 'VAT',
+    tax_rate DOUBLE NOT NULL DEFAULT 0,
+    paper_width int NOT NULL DEFAULT 80,
+    show_qr BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY(`store_id`),
+    FOREIGN KEY (`store_id`) REFERENCES `stores` (`id`) ON DELETE CASCADE
+);
+
+ALTER TABLE in_store_order_details
+    ADD COLUMN tendered DOUBLE;
@@ -0,0 +1,10 @@
+DROP INDEX purchases_store_created_at_idx ON purchases;
+DROP INDEX orders_updated_at_idx ON orders;
+
+ALTER TABLE in_store_order_details
+    DROP INDEX in_store_order_details_client_id_key,
+    DROP COLUMN client_number,
+    DROP COLUMN client_id;
+
+ALTER TABLE products
+    DROP COLUMN updated_at;
@@ -0,0 +1,10 @@
+ALTER TABLE products
+    ADD COLUMN updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP;
+
+ALTER TABLE in_store_order_details
+    ADD COLUMN client_id CHAR(36),
+    ADD COLUMN client_number VARCHAR(50),
+    ADD CONSTRAINT `in_store_order_details_client_id_key` UNIQUE (`client_id`);
+
+CREATE INDEX orders_updated_at_idx ON orders (updated_at);
+CREATE INDEX purchases_store_created_at_idx ON purchases (store_id, created_at);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys(
+    id bigint unsigned NOT NULL AUTO_INCREMENT,
+    user_id bigint unsigned NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    method VARCHAR(10) NOT NULL,
+    path VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    status_code int,
+    content_type VARCHAR(255),
+    response_body MEDIUMBLOB,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    expires_at DATETIME NOT NULL,
+    PRIMARY KEY(`id`),
+    UNIQUE KEY `idempotency_keys_user_key` (`user_id`, `idempotency_key`),
+    KEY `idempotency_keys_expires_at_idx` (`expires_at`),
+    FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS product_barcodes(
+    id bigint unsigned NOT NULL AUTO_INCREMENT,
+    product_id bigint unsigned NOT NULL,
+    code VARCHAR(64) NOT NULL,
+    symbology ENUM('ean13', 'upca', 'code128') NOT NULL,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(`id`),
+    UNIQUE KEY `product_barcodes_code_key` (`code`),
+    FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE
+);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS refresh_tokens(
+    id bigint unsigned NOT NULL AUTO_INCREMENT,
+    user_id bigint unsigned NOT NULL,
+    family_id CHAR(36) NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    user_agent VARCHAR(255),
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME,
+    replaced_by bigint unsigned,
+    revoked_at DATETIME,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(`id`),
+    UNIQUE KEY `refresh_tokens_token_hash_key` (`token_hash`),
+    KEY `refresh_tokens_family_id_idx` (`family_id`),
+    FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+);
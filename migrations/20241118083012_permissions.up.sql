@@ -0,0 +1,120 @@
+CREATE TABLE IF NOT EXISTS permissions(
+    id bigint unsigned NOT NULL AUTO_INCREMENT,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(`id`),
+    UNIQUE KEY `permissions_name_key` (`name`)
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions(
+    role_id bigint unsigned NOT NULL,
+    permission_id bigint unsigned NOT NULL,
+    PRIMARY KEY(`role_id`, `permission_id`),
+    FOREIGN KEY (`role_id`) REFERENCES `roles` (`id`) ON DELETE CASCADE,
+    FOREIGN KEY (`permission_id`) REFERENCES `permissions` (`id`) ON DELETE CASCADE
+);
+
+INSERT IGNORE INTO roles (name) VALUES ('admin'), ('customer'), ('cashier');
+
+INSERT INTO permissions (name, description) VALUES
+    ('categories:view', 'View categories'),
+    ('categories:manage', 'Create, update and delete categories'),
+    ('products:view', 'View products'),
+    ('products:manage', 'Create, update and delete products'),
+    ('products:import', 'Import products'),
+    ('products:export', 'Export products'),
+    ('barcodes:view', 'Look up products by barcode'),
+    ('barcodes:manage', 'Add, generate and remove barcodes'),
+    ('labels:print', 'Print barcode labels'),
+    ('stores:view', 'View stores and receipt templates'),
+    ('stores:manage', 'Create, update and delete stores and receipt templates'),
+    ('purchases:view', 'View purchases'),
+    ('purchases:create', 'Record purchases'),
+    ('purchases:delete', 'Delete purchases'),
+    ('goods-received:view', 'View goods received notes'),
+    ('goods-received:create', 'Receive goods'),
+    ('suppliers:view', 'View suppliers'),
+    ('suppliers:manage', 'Create, update and delete suppliers'),
+    ('purchase-orders:view', 'View purchase orders'),
+    ('purchase-orders:manage', 'Create, update, send, close and delete purchase orders'),
+    ('purchase-orders:receive', 'Receive purchase orders'),
+    ('reorder-points:view', 'View reorder points'),
+    ('reorder-points:manage', 'Set and delete reorder points'),
+    ('orders:view', 'View all orders and their receipts'),
+    ('orders:create', 'Sell in store'),
+    ('orders:view-own', 'View own in-store orders and their receipts'),
+    ('receipts:send', 'Email receipts'),
+    ('shifts:view', 'View all shifts'),
+    ('shifts:operate', 'Open, run and close own shifts'),
+    ('pos:sync', 'Sync an offline till'),
+    ('profile:view', 'View own cashier profile'),
+    ('cashiers:view', 'View the cashiers of stores'),
+    ('cashiers:manage', 'Assign and remove cashiers'),
+    ('sessions:view', 'View the sessions of users'),
+    ('sessions:revoke', 'Sign users out'),
+    ('roles:manage', 'Manage roles, permissions and user roles'),
+    ('costing:rebuild', 'Rebuild stock costs'),
+    ('reports:view', 'View reports'),
+    ('images:manage', 'Find and remove orphaned images'),
+    ('catalogue:view', 'View products in the shop'),
+    ('online-orders:create', 'Order online'),
+    ('online-orders:view-own', 'View own online orders');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r, permissions p
+WHERE r.name = 'admin' AND p.name IN (
+    'categories:view',
+    'categories:manage',
+    'products:view',
+    'products:manage',
+    'products:import',
+    'products:export',
+    'barcodes:view',
+    'barcodes:manage',
+    'labels:print',
+    'stores:view',
+    'stores:manage',
+    'purchases:view',
+    'purchases:create',
+    'purchases:delete',
+    'goods-received:view',
+    'goods-received:create',
+    'suppliers:view',
+    'suppliers:manage',
+    'purchase-orders:view',
+    'purchase-orders:manage',
+    'purchase-orders:receive',
+    'reorder-points:view',
+    'reorder-points:manage',
+    'orders:view',
+    'shifts:view',
+    'cashiers:view',
+    'cashiers:manage',
+    'sessions:view',
+    'sessions:revoke',
+    'roles:manage',
+    'costing:rebuild',
+    'reports:view',
+    'images:manage'
+);
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r, permissions p
+WHERE r.name = 'cashier' AND p.name IN (
+    'barcodes:view',
+    'orders:create',
+    'orders:view-own',
+    'receipts:send',
+    'shifts:operate',
+    'pos:sync',
+    'profile:view'
+);
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r, permissions p
+WHERE r.name = 'customer' AND p.name IN (
+    'catalogue:view',
+    'online-orders:create',
+    'online-orders:view-own'
+);
@@ -0,0 +1,8 @@
+ALTER TABLE refresh_tokens DROP COLUMN store_id;
+
+ALTER TABLE store_users
+    DROP FOREIGN KEY `fk_store_users_role`,
+    DROP KEY `fk_store_users_role`,
+    DROP COLUMN role_id;
+
+DELETE FROM roles WHERE name = 'manager';
@@ -0,0 +1,27 @@
+ALTER TABLE store_users ADD COLUMN role_id bigint unsigned;
+
+INSERT IGNORE INTO roles (name) VALUES ('cashier'), ('manager');
+
+UPDATE store_users SET role_id = (SELECT id FROM roles WHERE name = 'cashier');
+
+ALTER TABLE store_users
+    MODIFY role_id bigint unsigned NOT NULL,
+    ADD KEY `fk_store_users_role` (`role_id`),
+    ADD CONSTRAINT `fk_store_users_role` FOREIGN KEY (`role_id`) REFERENCES `roles` (`id`);
+
+ALTER TABLE refresh_tokens ADD COLUMN store_id bigint unsigned;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r, permissions p
+WHERE r.name = 'manager' AND p.name IN (
+    'orders:view',
+    'shifts:view',
+    'purchases:view',
+    'purchases:create',
+    'purchases:delete',
+    'goods-received:view',
+    'goods-received:create',
+    'cashiers:view',
+    'cashiers:manage',
+    'reports:view'
+);
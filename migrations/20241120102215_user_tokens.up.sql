@@ -0,0 +1,13 @@
+ALTER TABLE users ADD COLUMN email_verified_at DATETIME;
+
+CREATE TABLE IF NOT EXISTS user_tokens(
+    id CHAR(36) NOT NULL,
+    user_id bigint unsigned NOT NULL,
+    purpose VARCHAR(32) NOT NULL,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(`id`),
+    KEY `user_tokens_user_purpose_idx` (`user_id`, `purpose`),
+    FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+);
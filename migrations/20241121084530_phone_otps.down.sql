@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS phone_otps;
+
+DELETE FROM users WHERE email IS NULL;
+
+ALTER TABLE users
+    DROP KEY `users_phone_key`,
+    DROP COLUMN phone_verified_at,
+    MODIFY phone VARCHAR(15),
+    MODIFY email VARCHAR(255) NOT NULL;
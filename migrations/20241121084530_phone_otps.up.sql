@@ -0,0 +1,18 @@
+ALTER TABLE users
+    MODIFY email VARCHAR(255),
+    MODIFY phone VARCHAR(16),
+    ADD COLUMN phone_verified_at DATETIME,
+    ADD UNIQUE KEY `users_phone_key` (`phone`);
+
+CREATE TABLE IF NOT EXISTS phone_otps(
+    id bigint unsigned NOT NULL AUTO_INCREMENT,
+    phone VARCHAR(16) NOT NULL,
+    ip VARCHAR(45) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    expires_at DATETIME NOT NULL,
+    approved_at DATETIME,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(`id`),
+    KEY `phone_otps_phone_idx` (`phone`, `created_at`),
+    KEY `phone_otps_ip_idx` (`ip`, `created_at`)
+);
@@ -0,0 +1,7 @@
+ALTER TABLE refresh_tokens DROP COLUMN mfa;
+
+ALTER TABLE user_tokens DROP COLUMN attempts;
+
+DROP TABLE IF EXISTS mfa_recovery_codes;
+
+DROP TABLE IF EXISTS user_mfa;
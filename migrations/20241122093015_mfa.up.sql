@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS user_mfa(
+    user_id bigint unsigned NOT NULL,
+    secret VARCHAR(64) NOT NULL,
+    last_step bigint NOT NULL DEFAULT 0,
+    enabled_at DATETIME,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(`user_id`),
+    FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS mfa_recovery_codes(
+    id bigint unsigned NOT NULL AUTO_INCREMENT,
+    user_id bigint unsigned NOT NULL,
+    code_hash CHAR(64) NOT NULL,
+    used_at DATETIME,
+    PRIMARY KEY(`id`),
+    KEY `mfa_recovery_codes_user_idx` (`user_id`, `code_hash`),
+    FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+);
+
+ALTER TABLE user_tokens ADD COLUMN attempts INT NOT NULL DEFAULT 0;
+
+ALTER TABLE refresh_tokens ADD COLUMN mfa BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS login_audits(
+    id bigint unsigned NOT NULL AUTO_INCREMENT,
+    user_id bigint unsigned,
+    actor_id bigint unsigned,
+    email VARCHAR(255),
+    ip VARCHAR(45),
+    user_agent VARCHAR(255),
+    event ENUM('failed', 'locked', 'unlocked') NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(`id`),
+    KEY `login_audits_user_idx` (`user_id`, `created_at`),
+    FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE SET NULL,
+    FOREIGN KEY (`actor_id`) REFERENCES `users` (`id`) ON DELETE SET NULL
+);
+
+INSERT INTO permissions (name, description) VALUES
+    ('users:unlock', 'Unlock accounts locked after failed sign ins');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r, permissions p
+WHERE r.name = 'admin' AND p.name = 'users:unlock';
@@ -0,0 +1,9 @@
+ALTER TABLE users ADD COLUMN deactivated_at TIMESTAMP NULL;
+
+INSERT INTO permissions (name, description) VALUES
+    ('users:view', 'View and search users'),
+    ('users:manage', 'Create, edit, deactivate and reactivate users');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r, permissions p
+WHERE r.name = 'admin' AND p.name IN ('users:view', 'users:manage');
@@ -0,0 +1,17 @@
+DELETE FROM permissions WHERE name = 'account:manage';
+
+ALTER TABLE users DROP COLUMN deleted_at;
+
+ALTER TABLE online_order_details
+    DROP FOREIGN KEY online_order_details_address_fk,
+    DROP COLUMN address_id,
+    DROP COLUMN delivery_recipient,
+    DROP COLUMN delivery_phone,
+    DROP COLUMN delivery_line1,
+    DROP COLUMN delivery_line2,
+    DROP COLUMN delivery_city,
+    DROP COLUMN delivery_region,
+    DROP COLUMN delivery_postal_code,
+    DROP COLUMN delivery_country;
+
+DROP TABLE IF EXISTS user_addresses;
@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS user_addresses(
+    id bigint unsigned NOT NULL AUTO_INCREMENT,
+    user_id bigint unsigned NOT NULL,
+    label VARCHAR(50),
+    recipient VARCHAR(100) NOT NULL,
+    phone VARCHAR(16),
+    line1 VARCHAR(255) NOT NULL,
+    line2 VARCHAR(255),
+    city VARCHAR(100) NOT NULL,
+    region VARCHAR(100),
+    postal_code VARCHAR(20),
+    country CHAR(2) NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY(`id`),
+    KEY `user_addresses_user_idx` (`user_id`, `is_default`),
+    FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+);
+
+ALTER TABLE online_order_details
+    ADD COLUMN address_id bigint unsigned,
+    ADD COLUMN delivery_recipient VARCHAR(100),
+    ADD COLUMN delivery_phone VARCHAR(16),
+    ADD COLUMN delivery_line1 VARCHAR(255),
+    ADD COLUMN delivery_line2 VARCHAR(255),
+    ADD COLUMN delivery_city VARCHAR(100),
+    ADD COLUMN delivery_region VARCHAR(100),
+    ADD COLUMN delivery_postal_code VARCHAR(20),
+    ADD COLUMN delivery_country CHAR(2),
+    ADD CONSTRAINT `online_order_details_address_fk` FOREIGN KEY (`address_id`) REFERENCES `user_addresses` (`id`) ON DELETE SET NULL;
+
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP NULL;
+
+INSERT INTO permissions (name, description) VALUES
+    ('account:manage', 'View and edit own profile and addresses, and delete own account');
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r, permissions p
+WHERE r.name = 'customer' AND p.name = 'account:manage';
@@ -0,0 +1,106 @@
+-- name: OpenCostLayer :exec
+UPDATE purchases
+SET remaining = quantity
+WHERE id = ?;
+
+-- name: FindCostLayers :many
+SELECT id, remaining, order_price FROM purchases
+WHERE product_id = ? AND store_id = ? AND remaining > 0
+ORDER BY date, id
+FOR UPDATE;
+
+-- name: LockCostLayer :one
+SELECT * FROM purchases
+WHERE id = ?
+FOR UPDATE;
+
+-- name: ConsumeCostLayer :exec
+UPDATE purchases
+SET remaining = remaining - ?
+WHERE id = ?;
+
+-- name: FindLatestPurchaseCost :one
+SELECT order_price FROM purchases
+WHERE product_id = ?
+ORDER BY date DESC, id DESC
+LIMIT 1;
+
+-- name: FindStockCost :one
+SELECT * FROM stock_costs
+WHERE product_id = ? AND store_id = ?
+FOR UPDATE;
+
+-- name: SaveStockCost :exec
+INSERT INTO stock_costs (product_id, store_id, quantity, average_cost)
+VALUES (?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE
+    quantity = VALUES(quantity),
+    average_cost = VALUES(average_cost);
+
+-- name: ResetCostLayers :exec
+UPDATE purchases SET remaining = 0;
+
+-- name: DeleteStockCosts :exec
+DELETE FROM stock_costs;
+
+-- name: FindPurchasesForCosting :many
+SELECT id, product_id, store_id, quantity, order_price, date
+FROM purchases
+ORDER BY date, id;
+
+-- name: FindOrderItemsForCosting :many
+SELECT
+    oi.id,
+    oi.product_id,
+    oi.quantity,
+    COALESCE(isd.store_id, ood.store_id) AS store_id,
+    o.created_at
+FROM order_items oi
+JOIN orders o ON o.id = oi.order_id
+LEFT JOIN in_store_order_details isd ON isd.order_id = o.id
+LEFT JOIN online_order_details ood ON ood.order_id = o.id
+WHERE o.status <> 'canceled'
+ORDER BY o.created_at, oi.id;
+
+-- name: UpdateOrderItemCogs :exec
+UPDATE order_items
+SET cogs = ?
+WHERE id = ?;
+
+-- name: FindFIFOValuation :many
+SELECT
+    s.id AS store_id,
+    s.name AS store,
+    p.id AS product_id,
+    p.sku,
+    p.name,
+    SUM(pu.remaining) AS quantity,
+    SUM(pu.remaining * pu.order_price) AS value
+FROM purchases pu
+JOIN stores s ON s.id = pu.store_id
+JOIN products p ON p.id = pu.product_id
+WHERE pu.remaining > 0
+GROUP BY s.id, s.name, p.id, p.sku, p.name
+ORDER BY s.name, p.name;
+
+-- name: FindAverageValuation :many
+SELECT
+    s.id AS store_id,
+    s.name AS store,
+    p.id AS product_id,
+    p.sku,
+    p.name,
+    sc.quantity,
+    sc.quantity * sc.average_cost AS value
+FROM stock_costs sc
+JOIN stores s ON s.id = sc.store_id
+JOIN products p ON p.id = sc.product_id
+WHERE sc.quantity > 0
+ORDER BY s.name, p.name;
+
+-- name: InsertCostingRebuild :exec
+INSERT INTO costing_rebuilds (purchases, order_items)
+VALUES (?, ?);
+
+-- name: CountCostingRebuilds :one
+SELECT COUNT(*) AS count FROM costing_rebuilds;
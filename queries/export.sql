@@ -0,0 +1,63 @@
+-- name: FindStoreOrdersForExport :many
+SELECT
+    o.id,
+    o.number,
+    o.status,
+    o.created_at,
+    s.name AS store,
+    COALESCE(CONCAT(u.firstname, ' ', u.lastname), '') AS cashier,
+    CAST(COALESCE((SELECT SUM(oi.quantity) FROM order_items oi WHERE oi.order_id = o.id), 0) AS SIGNED) AS units,
+    o.total,
+    (SELECT SUM(oi.cogs) FROM order_items oi WHERE oi.order_id = o.id) AS cogs
+FROM orders o
+JOIN in_store_order_details i ON o.id = i.order_id
+JOIN stores s ON s.id = i.store_id
+LEFT JOIN users u ON u.id = i.cashier_id
+WHERE s.id = sqlc.arg(store_id)
+    AND o.created_at >= sqlc.arg(from_date) AND o.created_at < sqlc.arg(to_date)
+    AND o.id < sqlc.arg(before_id)
+ORDER BY o.id DESC
+LIMIT ?;
+
+-- name: FindOnlineOrdersForExport :many
+SELECT
+    o.id,
+    o.number,
+    o.status,
+    o.created_at,
+    COALESCE(s.name, '') AS store,
+    COALESCE(CONCAT(u.firstname, ' ', u.lastname), '') AS customer,
+    COALESCE(u.email, '') AS email,
+    CAST(COALESCE((SELECT SUM(oi.quantity) FROM order_items oi WHERE oi.order_id = o.id), 0) AS SIGNED) AS units,
+    o.total,
+    (SELECT SUM(oi.cogs) FROM order_items oi WHERE oi.order_id = o.id) AS cogs
+FROM orders o
+JOIN online_order_details i ON o.id = i.order_id
+LEFT JOIN stores s ON s.id = i.store_id
+LEFT JOIN users u ON u.id = i.customer_id
+WHERE o.created_at >= sqlc.arg(from_date) AND o.created_at < sqlc.arg(to_date)
+    AND o.id < sqlc.arg(before_id)
+ORDER BY o.id DESC
+LIMIT ?;
+
+-- name: FindPurchasesForExport :many
+SELECT
+    pu.id,
+    pu.date,
+    p.sku,
+    p.name,
+    COALESCE(s.name, '') AS store,
+    COALESCE(g.supplier, '') AS supplier,
+    COALESCE(g.invoice_number, '') AS invoice_number,
+    pu.quantity,
+    pu.order_price,
+    pu.selling_price
+FROM purchases pu
+JOIN products p ON p.id = pu.product_id
+LEFT JOIN stores s ON s.id = pu.store_id
+LEFT JOIN goods_received_notes g ON g.id = pu.goods_received_note_id
+WHERE pu.date >= sqlc.arg(from_date) AND pu.date < sqlc.arg(to_date)
+    AND pu.id < sqlc.arg(before_id)
+    AND (sqlc.narg(stores) IS NULL OR FIND_IN_SET(pu.store_id, sqlc.narg(stores)))
+ORDER BY pu.id DESC
+LIMIT ?;
@@ -0,0 +1,29 @@
+-- name: InsertGoodsReceivedNote :execlastid
+INSERT INTO goods_received_notes (supplier, invoice_number, store_id, date, lines_count, total_quantity, total_cost, user_id, supplier_id, purchase_order_id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: FindGoodsReceivedNote :one
+SELECT * FROM goods_received_notes
+WHERE id = ?;
+
+-- name: FindGoodsReceivedNoteByInvoice :one
+SELECT * FROM goods_received_notes
+WHERE supplier = ? AND invoice_number = ?;
+
+-- name: FindGoodsReceivedNotes :many
+SELECT * FROM goods_received_notes
+WHERE (sqlc.narg(stores) IS NULL OR FIND_IN_SET(store_id, sqlc.narg(stores)))
+ORDER BY id DESC
+LIMIT ? OFFSET ?;
+
+-- name: CountGoodsReceivedNotes :one
+SELECT COUNT(*) AS count
+FROM goods_received_notes
+WHERE (sqlc.narg(stores) IS NULL OR FIND_IN_SET(store_id, sqlc.narg(stores)));
+
+-- name: RemoveGoodsReceivedLine :exec
+UPDATE goods_received_notes
+SET lines_count = lines_count - 1,
+    total_quantity = total_quantity - ?,
+    total_cost = ROUND(total_cost - ?, 2)
+WHERE id = ?;
@@ -0,0 +1,15 @@
+-- name: FindIdempotencyKey :one
+SELECT * FROM idempotency_keys WHERE user_id = ? AND idempotency_key = ?;
+
+-- name: InsertIdempotencyKey :execlastid
+INSERT INTO idempotency_keys (user_id, idempotency_key, method, path, request_hash, expires_at)
+VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: SaveIdempotencyResponse :exec
+UPDATE idempotency_keys SET status_code = ?, content_type = ?, response_body = ? WHERE id = ?;
+
+-- name: DeleteIdempotencyKey :exec
+DELETE FROM idempotency_keys WHERE id = ?;
+
+-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys WHERE expires_at < ?;
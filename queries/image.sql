@@ -1,8 +1,33 @@
 -- name: InsertImage :execlastid
-INSERT INTO images (name) VALUES (?);
+INSERT INTO images (name, hash) VALUES (?, ?);
 
 -- name: FindImage :one
 SELECT * FROM images WHERE id = ?;
 
+-- name: FindImageByHash :one
+SELECT * FROM images WHERE hash = ?;
+
 -- name: DeleteImage :exec
-DELETE FROM images WHERE id = ?;
\ No newline at end of file
+DELETE FROM images WHERE id = ?;
+
+-- name: FindImageNames :many
+SELECT name FROM images;
+
+-- name: FindOrphanImages :many
+SELECT i.* FROM images i
+WHERE i.created_at < ?
+AND NOT EXISTS (SELECT 1 FROM product_images pi WHERE pi.image_id = i.id)
+AND NOT EXISTS (SELECT 1 FROM categories c WHERE c.image_id = i.id)
+ORDER BY i.id;
+
+-- name: DeleteOrphanImage :execrows
+DELETE FROM images
+WHERE images.id = ? AND images.created_at < ?
+AND NOT EXISTS (SELECT 1 FROM product_images pi WHERE pi.image_id = images.id)
+AND NOT EXISTS (SELECT 1 FROM categories c WHERE c.image_id = images.id);
+
+-- name: FindImageByName :one
+SELECT * FROM images WHERE name = ?;
+
+-- name: TouchImage :exec
+UPDATE images SET created_at = CURRENT_TIMESTAMP WHERE id = ?;
@@ -0,0 +1,22 @@
+-- name: InsertImportJob :execlastid
+INSERT INTO import_jobs (kind, status, total, user_id)
+VALUES (?, ?, ?, ?);
+
+-- name: FindImportJob :one
+SELECT * FROM import_jobs
+WHERE id = ? AND kind = ?;
+
+-- name: UpdateImportJobStatus :exec
+UPDATE import_jobs
+SET status = ?
+WHERE id = ?;
+
+-- name: UpdateImportJobProgress :exec
+UPDATE import_jobs
+SET processed = ?, inserted = ?, updated = ?
+WHERE id = ?;
+
+-- name: FinishImportJob :exec
+UPDATE import_jobs
+SET status = ?, errors = ?, finished_at = CURRENT_TIMESTAMP
+WHERE id = ?;
@@ -0,0 +1,15 @@
+-- name: InsertLoginAudit :exec
+INSERT INTO login_audits (user_id, actor_id, email, ip, user_agent, event)
+VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: FindUserLoginAudits :many
+SELECT * FROM login_audits
+WHERE user_id = ?
+ORDER BY id DESC
+LIMIT ? OFFSET ?;
+
+-- name: CountUserLoginAudits :one
+SELECT COUNT(*) FROM login_audits WHERE user_id = ?;
+
+-- name: AnonymiseUserLoginAudits :exec
+UPDATE login_audits SET email = NULL, ip = NULL, user_agent = NULL WHERE user_id = ?;
@@ -0,0 +1,28 @@
+-- name: FindUserMfa :one
+SELECT * FROM user_mfa WHERE user_id = ?;
+
+-- name: UpsertUserMfa :exec
+INSERT INTO user_mfa (user_id, secret) VALUES (?, ?)
+ON DUPLICATE KEY UPDATE secret = VALUES(secret), last_step = 0, enabled_at = NULL;
+
+-- name: EnableUserMfa :exec
+UPDATE user_mfa SET enabled_at = NOW() WHERE user_id = ?;
+
+-- name: UseMfaStep :execrows
+UPDATE user_mfa SET last_step = ? WHERE user_id = ? AND last_step < ?;
+
+-- name: DeleteUserMfa :exec
+DELETE FROM user_mfa WHERE user_id = ?;
+
+-- name: InsertRecoveryCode :exec
+INSERT INTO mfa_recovery_codes (user_id, code_hash) VALUES (?, ?);
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM mfa_recovery_codes WHERE user_id = ?;
+
+-- name: UseRecoveryCode :execrows
+UPDATE mfa_recovery_codes SET used_at = NOW()
+WHERE user_id = ? AND code_hash = ? AND used_at IS NULL;
+
+-- name: CountRecoveryCodes :one
+SELECT COUNT(*) FROM mfa_recovery_codes WHERE user_id = ? AND used_at IS NULL;
@@ -3,16 +3,16 @@ INSERT INTO orders (number, channel, status, total)
 VALUES(?, ?, ?, ?);
 
 -- name: InsertOrderItem :exec
-INSERT INTO order_items (order_id, product_id, quantity, price)
-VALUES (?, ?, ?, ?);
+INSERT INTO order_items (order_id, product_id, quantity, price, cogs)
+VALUES (?, ?, ?, ?, ?);
 
 -- name: InsertInStoreOrderDetails :exec
-INSERT INTO in_store_order_details (order_id, cashier_id, store_id)
-VALUES (?, ?, ?);
+INSERT INTO in_store_order_details (order_id, cashier_id, store_id, shift_id, tender, tendered, client_id, client_number)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?);
 
 -- name: InsertOnlineOrderDetails :exec
-INSERT INTO online_order_details (order_id, customer_id)
-VALUES (?, ?);
+INSERT INTO online_order_details (order_id, customer_id, store_id, address_id, delivery_recipient, delivery_phone, delivery_line1, delivery_line2, delivery_city, delivery_region, delivery_postal_code, delivery_country)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
 
 -- name: FindOrder :one
 SELECT * FROM orders WHERE id = ?;
@@ -23,7 +23,7 @@ SELECT * FROM orders WHERE id = ? AND channel = ?;
 -- name: FindLastCreatedOrder :one
 SELECT * 
 FROM orders 
-ORDER BY created_at DESC 
+ORDER BY id DESC 
 LIMIT 1;
 
 
@@ -81,6 +81,10 @@ SELECT COUNT(o.id) AS count FROM orders o
 JOIN online_order_details i ON o.id = i.order_id
 ORDER BY o.id DESC;
 
+-- name: CountSales :one
+SELECT COUNT(*) AS count FROM orders
+WHERE status <> 'canceled';
+
 -- name: FindOrderItemByProductSKU :one
 SELECT oi.*
 FROM order_items oi
@@ -91,3 +95,16 @@ WHERE p.sku = ?;
 UPDATE orders
 SET status = ?, updated_at = CURRENT_TIMESTAMP
 WHERE id = ?;
+
+-- name: AnonymiseOnlineOrderAddresses :exec
+UPDATE online_order_details d
+JOIN orders o ON o.id = d.order_id
+SET d.delivery_recipient = NULL,
+    d.delivery_phone = NULL,
+    d.delivery_line1 = NULL,
+    d.delivery_line2 = NULL,
+    d.delivery_city = NULL,
+    d.delivery_region = NULL,
+    d.delivery_postal_code = NULL,
+    d.delivery_country = NULL
+WHERE d.customer_id = ? AND o.status IN ('completed', 'canceled');
@@ -0,0 +1,25 @@
+-- name: FindPermissions :many
+SELECT * FROM permissions ORDER BY name;
+
+-- name: FindPermissionByName :one
+SELECT * FROM permissions WHERE name = ?;
+
+-- name: FindRolePermissions :many
+SELECT p.*
+FROM permissions AS p
+JOIN role_permissions AS rp ON rp.permission_id = p.id
+WHERE rp.role_id = ?
+ORDER BY p.name;
+
+-- name: InsertRolePermission :exec
+INSERT INTO role_permissions (role_id, permission_id) VALUES (?, ?);
+
+-- name: DeleteRolePermissions :exec
+DELETE FROM role_permissions WHERE role_id = ?;
+
+-- name: CheckUserPermission :one
+SELECT COUNT(*) > 0
+FROM user_roles AS ur
+JOIN role_permissions AS rp ON rp.role_id = ur.role_id
+JOIN permissions AS p ON p.id = rp.permission_id
+WHERE ur.user_id = ? AND p.name = ?;
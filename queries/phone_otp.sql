@@ -0,0 +1,39 @@
+-- name: InsertPhoneOtp :execlastid
+INSERT INTO phone_otps (phone, ip, expires_at) VALUES (?, ?, NOW() + INTERVAL 10 MINUTE);
+
+-- name: FindLatestPhoneOtp :one
+SELECT id, attempts, approved_at, expires_at <= NOW() AS expired,
+    TIMESTAMPDIFF(SECOND, created_at, NOW()) AS age
+FROM phone_otps
+WHERE phone = ?
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: FindPreviousPhoneOtp :one
+SELECT id, TIMESTAMPDIFF(SECOND, created_at, NOW()) AS age
+FROM phone_otps
+WHERE phone = ? AND id < ?
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: CountRecentPhoneOtps :one
+SELECT COUNT(*) FROM phone_otps WHERE phone = ? AND id < ? AND created_at > NOW() - INTERVAL 1 HOUR;
+
+-- name: CountRecentIPPhoneOtps :one
+SELECT COUNT(*) FROM phone_otps WHERE ip = ? AND id < ? AND created_at > NOW() - INTERVAL 1 HOUR;
+
+-- name: AttemptPhoneOtp :execrows
+UPDATE phone_otps SET attempts = attempts + 1
+WHERE id = ? AND attempts < ? AND approved_at IS NULL AND expires_at > NOW();
+
+-- name: ApprovePhoneOtp :exec
+UPDATE phone_otps SET approved_at = NOW() WHERE id = ?;
+
+-- name: DeleteOldPhoneOtps :exec
+DELETE FROM phone_otps WHERE created_at < NOW() - INTERVAL 1 DAY;
+
+-- name: DeletePhoneOtp :exec
+DELETE FROM phone_otps WHERE id = ?;
+
+-- name: DeletePhoneOtps :exec
+DELETE FROM phone_otps WHERE phone = ?;
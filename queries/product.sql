@@ -1,6 +1,6 @@
 -- name: InsertProduct :execlastid
-INSERT INTO products (slug, name, description, sku, category_id, status, visibility)
-VALUES (?, ?, ?, ?, ?, ?, ?);
+INSERT INTO products (slug, name, description, sku, category_id, status, visibility, price)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?);
 
 -- name: FindProduct :one
 SELECT * FROM products WHERE id = ?;
@@ -26,7 +26,8 @@ SET name = COALESCE(?, name),
     sku = COALESCE(?, sku),
     category_id = ?,
     status = COALESCE(?, status),
-    visibility = COALESCE(?, visibility)
+    visibility = COALESCE(?, visibility),
+    price = COALESCE(?, price)
 WHERE id = ?;
 
 -- name: DeleteProduct :exec
@@ -76,4 +77,22 @@ SELECT DISTINCT p.*
 FROM products p
 JOIN purchases pur ON pur.product_id = p.id
 WHERE p.sku = ?
-ORDER BY p.id DESC;
\ No newline at end of file
+ORDER BY p.id DESC;
+
+-- name: FindProductsForExport :many
+SELECT
+    p.id,
+    p.name,
+    p.sku,
+    p.description,
+    c.slug AS category_slug,
+    p.status,
+    p.visibility,
+    p.price,
+    (SELECT GROUP_CONCAT(i.name ORDER BY i.id SEPARATOR '|')
+        FROM images i
+        JOIN product_images pi ON pi.image_id = i.id
+        WHERE pi.product_id = p.id) AS images
+FROM products p
+LEFT JOIN categories c ON c.id = p.category_id
+ORDER BY p.id;
@@ -0,0 +1,25 @@
+-- name: InsertProductBarcode :execlastid
+INSERT INTO product_barcodes (product_id, code, symbology, is_primary) VALUES (?, ?, ?, ?);
+
+-- name: FindProductBarcodes :many
+SELECT * FROM product_barcodes WHERE product_id = ? ORDER BY is_primary DESC, id;
+
+-- name: FindProductBarcode :one
+SELECT * FROM product_barcodes WHERE id = ? AND product_id = ?;
+
+-- name: FindProductBarcodeByCode :one
+SELECT * FROM product_barcodes WHERE code = ?;
+
+-- name: FindProductByBarcode :one
+SELECT p.* FROM products p
+JOIN product_barcodes b ON b.product_id = p.id
+WHERE b.code = ?;
+
+-- name: ClearPrimaryProductBarcode :exec
+UPDATE product_barcodes SET is_primary = FALSE WHERE product_id = ?;
+
+-- name: DeleteProductBarcode :exec
+DELETE FROM product_barcodes WHERE id = ?;
+
+-- name: SetPrimaryProductBarcode :exec
+UPDATE product_barcodes SET is_primary = TRUE WHERE id = ?;
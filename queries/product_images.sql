@@ -1,5 +1,5 @@
 -- name: AssignProductImage :exec
-INSERT INTO product_images (product_id, image_id) VALUES (?, ?);
+INSERT IGNORE INTO product_images (product_id, image_id) VALUES (?, ?);
 
 -- name: FindProductImages :many
 SELECT i.id, i.name
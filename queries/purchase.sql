@@ -1,9 +1,10 @@
 -- name: InsertPurchase :execlastid
-INSERT INTO purchases (product_id, date, quantity, order_price, selling_price, store_id, user_id)
-VALUES (?, ?, ?, ?, ?, ?, ?);
+INSERT INTO purchases (product_id, date, quantity, order_price, selling_price, store_id, user_id, goods_received_note_id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?);
 
 -- name: FindPurchases :many
 SELECT * FROM purchases
+WHERE (sqlc.narg(stores) IS NULL OR FIND_IN_SET(store_id, sqlc.narg(stores)))
 ORDER BY id DESC
 LIMIT ? OFFSET ?;
 
@@ -29,4 +30,10 @@ WHERE id = ?;
 
 -- name: CountPurchases :one
 SELECT COUNT(*) AS count
-FROM purchases;
\ No newline at end of file
+FROM purchases
+WHERE (sqlc.narg(stores) IS NULL OR FIND_IN_SET(store_id, sqlc.narg(stores)));
+
+-- name: FindPurchasesByGoodsReceivedNote :many
+SELECT * FROM purchases
+WHERE goods_received_note_id = ?
+ORDER BY id;
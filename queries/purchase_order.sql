@@ -0,0 +1,76 @@
+-- name: InsertPurchaseOrder :execlastid
+INSERT INTO purchase_orders (number, supplier_id, store_id, status, expected_at, notes, user_id)
+VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: FindPurchaseOrder :one
+SELECT * FROM purchase_orders WHERE id = ?;
+
+-- name: FindLastCreatedPurchaseOrder :one
+SELECT * FROM purchase_orders
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: FindPurchaseOrders :many
+SELECT * FROM purchase_orders
+ORDER BY id DESC
+LIMIT ? OFFSET ?;
+
+-- name: CountPurchaseOrders :one
+SELECT COUNT(*) AS count
+FROM purchase_orders;
+
+-- name: UpdatePurchaseOrder :exec
+UPDATE purchase_orders
+SET supplier_id = ?,
+    store_id = ?,
+    expected_at = ?,
+    notes = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: UpdatePurchaseOrderStatus :exec
+UPDATE purchase_orders
+SET status = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: DeletePurchaseOrder :exec
+DELETE FROM purchase_orders WHERE id = ?;
+
+-- name: InsertPurchaseOrderItem :exec
+INSERT INTO purchase_order_items (purchase_order_id, product_id, quantity, order_price, selling_price)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: DeletePurchaseOrderItems :exec
+DELETE FROM purchase_order_items WHERE purchase_order_id = ?;
+
+-- name: FindPurchaseOrderItems :many
+SELECT * FROM purchase_order_items
+WHERE purchase_order_id = ?
+ORDER BY id;
+
+-- name: ReceivePurchaseOrderItem :exec
+UPDATE purchase_order_items
+SET received = received + ?
+WHERE id = ?;
+
+-- name: FindOutstandingPurchaseOrderItems :many
+SELECT
+    po.id AS purchase_order_id,
+    po.number,
+    po.status,
+    po.expected_at,
+    s.id AS supplier_id,
+    s.name AS supplier,
+    p.id AS product_id,
+    p.sku,
+    p.name,
+    i.quantity,
+    i.received,
+    i.order_price
+FROM purchase_order_items i
+JOIN purchase_orders po ON po.id = i.purchase_order_id
+JOIN suppliers s ON s.id = po.supplier_id
+JOIN products p ON p.id = i.product_id
+WHERE po.status IN ('sent', 'partially_received') AND i.received < i.quantity
+ORDER BY po.expected_at IS NULL, po.expected_at, po.id, i.id;
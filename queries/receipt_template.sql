@@ -0,0 +1,13 @@
+-- name: FindReceiptTemplate :one
+SELECT * FROM receipt_templates WHERE store_id = ?;
+
+-- name: UpsertReceiptTemplate :exec
+INSERT INTO receipt_templates (store_id, header, footer, tax_label, tax_rate, paper_width, show_qr)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE
+    header = VALUES(header),
+    footer = VALUES(footer),
+    tax_label = VALUES(tax_label),
+    tax_rate = VALUES(tax_rate),
+    paper_width = VALUES(paper_width),
+    show_qr = VALUES(show_qr);
@@ -0,0 +1,28 @@
+-- name: InsertRefreshToken :execlastid
+INSERT INTO refresh_tokens (user_id, family_id, token_hash, user_agent, expires_at, store_id, mfa)
+VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: FindRefreshTokenByHash :one
+SELECT * FROM refresh_tokens WHERE token_hash = ?;
+
+-- name: UseRefreshToken :execrows
+UPDATE refresh_tokens SET used_at = NOW(), replaced_by = ?
+WHERE id = ? AND used_at IS NULL AND revoked_at IS NULL;
+
+-- name: RevokeRefreshTokenFamily :exec
+UPDATE refresh_tokens SET revoked_at = NOW()
+WHERE family_id = ? AND revoked_at IS NULL;
+
+-- name: RevokeUserRefreshTokens :exec
+UPDATE refresh_tokens SET revoked_at = NOW()
+WHERE user_id = ? AND revoked_at IS NULL;
+
+-- name: DeleteUserRefreshTokens :exec
+DELETE FROM refresh_tokens WHERE user_id = ?;
+
+-- name: FindActiveSessions :many
+SELECT t.family_id, t.user_agent, t.created_at AS last_used_at, t.expires_at,
+    (SELECT MIN(f.created_at) FROM refresh_tokens f WHERE f.family_id = t.family_id) AS started_at
+FROM refresh_tokens t
+WHERE t.user_id = ? AND t.used_at IS NULL AND t.revoked_at IS NULL AND t.expires_at > NOW()
+ORDER BY t.created_at DESC;
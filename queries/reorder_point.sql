@@ -0,0 +1,67 @@
+-- name: InsertReorderPoint :execlastid
+INSERT INTO reorder_points (product_id, store_id, reorder_point, reorder_quantity)
+VALUES (?, ?, ?, ?);
+
+-- name: FindReorderPoint :one
+SELECT * FROM reorder_points WHERE id = ?;
+
+-- name: FindReorderPointByProductStore :one
+SELECT * FROM reorder_points
+WHERE product_id = ? AND store_id <=> ?;
+
+-- name: FindReorderPoints :many
+SELECT * FROM reorder_points
+ORDER BY product_id, store_id
+LIMIT ? OFFSET ?;
+
+-- name: CountReorderPoints :one
+SELECT COUNT(*) AS count
+FROM reorder_points;
+
+-- name: UpdateReorderPoint :exec
+UPDATE reorder_points
+SET reorder_point = ?,
+    reorder_quantity = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: DeleteReorderPoint :exec
+DELETE FROM reorder_points WHERE id = ?;
+
+-- name: FindLowStock :many
+SELECT
+    p.id AS product_id,
+    p.sku,
+    p.name,
+    s.id AS store_id,
+    s.name AS store,
+    rp.reorder_point,
+    rp.reorder_quantity,
+    CAST(COALESCE(pu.quantity, 0) - COALESCE(so.quantity, 0) AS SIGNED) AS quantity
+FROM reorder_points rp
+JOIN products p ON p.id = rp.product_id
+JOIN stores s ON s.id = rp.store_id OR rp.store_id IS NULL
+LEFT JOIN (
+    SELECT product_id, store_id, SUM(quantity) AS quantity
+    FROM purchases
+    WHERE store_id IS NOT NULL
+    GROUP BY product_id, store_id
+) pu ON pu.product_id = p.id AND pu.store_id = s.id
+LEFT JOIN (
+    SELECT oi.product_id, COALESCE(isd.store_id, ood.store_id) AS store_id, SUM(oi.quantity) AS quantity
+    FROM order_items oi
+    JOIN orders o ON o.id = oi.order_id
+    LEFT JOIN in_store_order_details isd ON isd.order_id = o.id
+    LEFT JOIN online_order_details ood ON ood.order_id = o.id
+    WHERE o.status <> 'canceled'
+    GROUP BY oi.product_id, COALESCE(isd.store_id, ood.store_id)
+) so ON so.product_id = p.id AND so.store_id = s.id
+WHERE p.status AND s.status
+    AND (rp.store_id IS NOT NULL OR (
+        pu.quantity IS NOT NULL AND NOT EXISTS (
+            SELECT 1 FROM reorder_points o WHERE o.product_id = rp.product_id AND o.store_id = s.id
+        )
+    ))
+    AND COALESCE(pu.quantity, 0) - COALESCE(so.quantity, 0) <= rp.reorder_point
+    AND (sqlc.narg(store_id) IS NULL OR s.id = sqlc.narg(store_id))
+ORDER BY s.name, p.name;
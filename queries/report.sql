@@ -0,0 +1,199 @@
+-- name: FindMarginByProduct :many
+SELECT
+    p.id AS product_id,
+    p.sku,
+    p.name,
+    SUM(oi.quantity) AS units,
+    SUM(oi.total) AS revenue,
+    SUM(oi.cogs) AS cogs
+FROM order_items oi
+JOIN orders o ON o.id = oi.order_id
+JOIN products p ON p.id = oi.product_id
+WHERE o.status <> 'canceled' AND oi.cogs IS NOT NULL
+    AND o.created_at >= ? AND o.created_at < ?
+GROUP BY p.id, p.sku, p.name
+ORDER BY revenue DESC;
+
+-- name: FindMarginByCategory :many
+SELECT
+    c.id AS category_id,
+    COALESCE(c.name, '') AS category,
+    SUM(oi.quantity) AS units,
+    SUM(oi.total) AS revenue,
+    SUM(oi.cogs) AS cogs
+FROM order_items oi
+JOIN orders o ON o.id = oi.order_id
+JOIN products p ON p.id = oi.product_id
+LEFT JOIN categories c ON c.id = p.category_id
+WHERE o.status <> 'canceled' AND oi.cogs IS NOT NULL
+    AND o.created_at >= ? AND o.created_at < ?
+GROUP BY c.id, c.name
+ORDER BY revenue DESC;
+
+-- name: FindMarginByStore :many
+SELECT
+    s.id AS store_id,
+    COALESCE(s.name, '') AS store,
+    SUM(oi.quantity) AS units,
+    SUM(oi.total) AS revenue,
+    SUM(oi.cogs) AS cogs
+FROM order_items oi
+JOIN orders o ON o.id = oi.order_id
+LEFT JOIN in_store_order_details isd ON isd.order_id = o.id
+LEFT JOIN online_order_details ood ON ood.order_id = o.id
+LEFT JOIN stores s ON s.id = COALESCE(isd.store_id, ood.store_id)
+WHERE o.status <> 'canceled' AND oi.cogs IS NOT NULL
+    AND o.created_at >= ? AND o.created_at < ?
+GROUP BY s.id, s.name
+ORDER BY revenue DESC;
+
+-- name: FindMarginByPeriod :many
+SELECT
+    DATE_FORMAT(o.created_at, sqlc.arg(format)) AS period,
+    SUM(oi.quantity) AS units,
+    SUM(oi.total) AS revenue,
+    SUM(oi.cogs) AS cogs
+FROM order_items oi
+JOIN orders o ON o.id = oi.order_id
+WHERE o.status <> 'canceled' AND oi.cogs IS NOT NULL
+    AND o.created_at >= sqlc.arg(from_date) AND o.created_at < sqlc.arg(to_date)
+GROUP BY 1
+ORDER BY 1;
+
+-- name: FindSalesSummary :one
+SELECT
+    COUNT(DISTINCT o.id) AS orders,
+    CAST(COALESCE(SUM(oi.quantity), 0) AS SIGNED) AS units,
+    CAST(COALESCE(SUM(oi.total), 0) AS DOUBLE) AS revenue
+FROM orders o
+JOIN order_items oi ON oi.order_id = o.id
+LEFT JOIN in_store_order_details isd ON isd.order_id = o.id
+LEFT JOIN online_order_details ood ON ood.order_id = o.id
+WHERE o.status <> 'canceled'
+    AND o.created_at >= ? AND o.created_at < ?
+    AND (sqlc.narg(stores) IS NULL OR FIND_IN_SET(COALESCE(isd.store_id, ood.store_id), sqlc.narg(stores)));
+
+-- name: FindSalesByPeriod :many
+SELECT
+    DATE_FORMAT(o.created_at, sqlc.arg(format)) AS period,
+    COUNT(DISTINCT o.id) AS orders,
+    SUM(oi.quantity) AS units,
+    SUM(oi.total) AS revenue
+FROM orders o
+JOIN order_items oi ON oi.order_id = o.id
+LEFT JOIN in_store_order_details isd ON isd.order_id = o.id
+LEFT JOIN online_order_details ood ON ood.order_id = o.id
+WHERE o.status <> 'canceled'
+    AND o.created_at >= sqlc.arg(from_date) AND o.created_at < sqlc.arg(to_date)
+    AND (sqlc.narg(stores) IS NULL OR FIND_IN_SET(COALESCE(isd.store_id, ood.store_id), sqlc.narg(stores)))
+GROUP BY 1
+ORDER BY 1;
+
+-- name: FindSalesByStore :many
+SELECT
+    s.id AS store_id,
+    COALESCE(s.name, '') AS store,
+    COUNT(DISTINCT o.id) AS orders,
+    SUM(oi.quantity) AS units,
+    SUM(oi.total) AS revenue
+FROM orders o
+JOIN order_items oi ON oi.order_id = o.id
+LEFT JOIN in_store_order_details isd ON isd.order_id = o.id
+LEFT JOIN online_order_details ood ON ood.order_id = o.id
+LEFT JOIN stores s ON s.id = COALESCE(isd.store_id, ood.store_id)
+WHERE o.status <> 'canceled'
+    AND o.created_at >= ? AND o.created_at < ?
+    AND (sqlc.narg(stores) IS NULL OR FIND_IN_SET(COALESCE(isd.store_id, ood.store_id), sqlc.narg(stores)))
+GROUP BY s.id, s.name
+ORDER BY revenue DESC;
+
+-- name: FindSalesByChannel :many
+SELECT
+    o.channel,
+    COUNT(DISTINCT o.id) AS orders,
+    SUM(oi.quantity) AS units,
+    SUM(oi.total) AS revenue
+FROM orders o
+JOIN order_items oi ON oi.order_id = o.id
+LEFT JOIN in_store_order_details isd ON isd.order_id = o.id
+LEFT JOIN online_order_details ood ON ood.order_id = o.id
+WHERE o.status <> 'canceled'
+    AND o.created_at >= ? AND o.created_at < ?
+    AND (sqlc.narg(stores) IS NULL OR FIND_IN_SET(COALESCE(isd.store_id, ood.store_id), sqlc.narg(stores)))
+GROUP BY o.channel
+ORDER BY revenue DESC;
+
+-- name: FindSalesByCashier :many
+SELECT
+    u.id AS cashier_id,
+    COALESCE(CONCAT(u.firstname, ' ', u.lastname), '') AS cashier,
+    COUNT(DISTINCT o.id) AS orders,
+    SUM(oi.quantity) AS units,
+    SUM(oi.total) AS revenue
+FROM orders o
+JOIN order_items oi ON oi.order_id = o.id
+JOIN in_store_order_details isd ON isd.order_id = o.id
+LEFT JOIN users u ON u.id = isd.cashier_id
+WHERE o.status <> 'canceled'
+    AND o.created_at >= ? AND o.created_at < ?
+    AND (sqlc.narg(stores) IS NULL OR FIND_IN_SET(isd.store_id, sqlc.narg(stores)))
+GROUP BY u.id, u.firstname, u.lastname
+ORDER BY revenue DESC;
+
+-- name: FindSalesByCategory :many
+SELECT
+    c.id AS category_id,
+    COALESCE(c.name, '') AS category,
+    COUNT(DISTINCT o.id) AS orders,
+    SUM(oi.quantity) AS units,
+    SUM(oi.total) AS revenue
+FROM orders o
+JOIN order_items oi ON oi.order_id = o.id
+LEFT JOIN in_store_order_details isd ON isd.order_id = o.id
+LEFT JOIN online_order_details ood ON ood.order_id = o.id
+JOIN products p ON p.id = oi.product_id
+LEFT JOIN categories c ON c.id = p.category_id
+WHERE o.status <> 'canceled'
+    AND o.created_at >= ? AND o.created_at < ?
+    AND (sqlc.narg(stores) IS NULL OR FIND_IN_SET(COALESCE(isd.store_id, ood.store_id), sqlc.narg(stores)))
+GROUP BY c.id, c.name
+ORDER BY revenue DESC;
+
+-- name: FindSalesByProduct :many
+SELECT
+    p.id AS product_id,
+    p.sku,
+    p.name,
+    COUNT(DISTINCT o.id) AS orders,
+    SUM(oi.quantity) AS units,
+    SUM(oi.total) AS revenue
+FROM orders o
+JOIN order_items oi ON oi.order_id = o.id
+LEFT JOIN in_store_order_details isd ON isd.order_id = o.id
+LEFT JOIN online_order_details ood ON ood.order_id = o.id
+JOIN products p ON p.id = oi.product_id
+WHERE o.status <> 'canceled'
+    AND o.created_at >= ? AND o.created_at < ?
+    AND (sqlc.narg(stores) IS NULL OR FIND_IN_SET(COALESCE(isd.store_id, ood.store_id), sqlc.narg(stores)))
+GROUP BY p.id, p.sku, p.name
+ORDER BY revenue DESC;
+
+-- name: FindTopSellers :many
+SELECT
+    p.id AS product_id,
+    p.sku,
+    p.name,
+    COUNT(DISTINCT o.id) AS orders,
+    SUM(oi.quantity) AS units,
+    SUM(oi.total) AS revenue
+FROM orders o
+JOIN order_items oi ON oi.order_id = o.id
+LEFT JOIN in_store_order_details isd ON isd.order_id = o.id
+LEFT JOIN online_order_details ood ON ood.order_id = o.id
+JOIN products p ON p.id = oi.product_id
+WHERE o.status <> 'canceled'
+    AND o.created_at >= ? AND o.created_at < ?
+    AND (sqlc.narg(stores) IS NULL OR FIND_IN_SET(COALESCE(isd.store_id, ood.store_id), sqlc.narg(stores)))
+GROUP BY p.id, p.sku, p.name
+ORDER BY units DESC, revenue DESC
+LIMIT ?;
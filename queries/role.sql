@@ -5,4 +5,18 @@ INSERT INTO roles (name) VALUES (?);
 SELECT * FROM roles WHERE id = ?;
 
 -- name: FindRoleByName :one
-SELECT * FROM roles WHERE name = ?;
\ No newline at end of file
+SELECT * FROM roles WHERE name = ?;
+
+-- name: FindRoles :many
+SELECT * FROM roles ORDER BY id;
+
+-- name: UpdateRole :exec
+UPDATE roles SET name = ? WHERE id = ?;
+
+-- name: DeleteRole :exec
+DELETE FROM roles WHERE id = ?;
+
+-- name: CountRoleUsers :one
+SELECT
+    (SELECT COUNT(*) FROM user_roles WHERE role_id = sqlc.arg(role_id))
+    + (SELECT COUNT(*) FROM store_users WHERE role_id = sqlc.arg(role_id)) AS count;
@@ -0,0 +1,51 @@
+-- name: InsertShift :execlastid
+INSERT INTO shifts (store_id, cashier_id, opening_float)
+VALUES (?, ?, ?);
+
+-- name: FindShift :one
+SELECT * FROM shifts WHERE id = ?;
+
+-- name: FindOpenShiftByCashier :one
+SELECT * FROM shifts
+WHERE cashier_id = ? AND status = 'open'
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: FindShifts :many
+SELECT * FROM shifts
+WHERE (sqlc.narg(store_id) IS NULL OR store_id = sqlc.narg(store_id))
+    AND (sqlc.narg(cashier_id) IS NULL OR cashier_id = sqlc.narg(cashier_id))
+    AND (sqlc.narg(stores) IS NULL OR FIND_IN_SET(store_id, sqlc.narg(stores)))
+ORDER BY id DESC
+LIMIT ? OFFSET ?;
+
+-- name: CountShifts :one
+SELECT COUNT(*) AS count FROM shifts
+WHERE (sqlc.narg(store_id) IS NULL OR store_id = sqlc.narg(store_id))
+    AND (sqlc.narg(cashier_id) IS NULL OR cashier_id = sqlc.narg(cashier_id))
+    AND (sqlc.narg(stores) IS NULL OR FIND_IN_SET(store_id, sqlc.narg(stores)));
+
+-- name: CloseShift :execrows
+UPDATE shifts
+SET status = 'closed', expected_cash = ?, counted_cash = ?, variance = ?, notes = ?, closed_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'open';
+
+-- name: InsertShiftCashMovement :execlastid
+INSERT INTO shift_cash_movements (shift_id, type, amount, reason)
+VALUES (?, ?, ?, ?);
+
+-- name: FindShiftCashMovements :many
+SELECT * FROM shift_cash_movements
+WHERE shift_id = ?
+ORDER BY id;
+
+-- name: FindShiftSales :many
+SELECT
+    i.tender,
+    COUNT(o.id) AS orders,
+    SUM(o.total) AS total
+FROM orders o
+JOIN in_store_order_details i ON i.order_id = o.id
+WHERE i.shift_id = ? AND o.status <> 'canceled'
+GROUP BY i.tender
+ORDER BY i.tender;
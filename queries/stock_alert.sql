@@ -0,0 +1,27 @@
+-- name: InsertStockAlert :execlastid
+INSERT INTO stock_alerts (product_id, store_id, quantity, reorder_point)
+VALUES (?, ?, ?, ?);
+
+-- name: FindOpenStockAlerts :many
+SELECT * FROM stock_alerts
+WHERE resolved_at IS NULL;
+
+-- name: ResolveStockAlert :exec
+UPDATE stock_alerts
+SET resolved_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: FindOpenStockAlertDeliveries :many
+SELECT d.* FROM stock_alert_deliveries d
+JOIN stock_alerts a ON a.id = d.alert_id
+WHERE a.resolved_at IS NULL;
+
+-- name: InsertStockAlertDelivery :exec
+INSERT INTO stock_alert_deliveries (alert_id, notifier)
+VALUES (?, ?);
+
+-- name: LockStockCheck :one
+SELECT COALESCE(GET_LOCK('stock_check', 0), 0) AS locked;
+
+-- name: UnlockStockCheck :exec
+DO RELEASE_LOCK('stock_check');
@@ -1,11 +1,17 @@
 -- name: AssignStoreUser :exec
-INSERT INTO store_users (store_id, user_id) VALUES (?, ?);
+INSERT INTO store_users (store_id, user_id, role_id) VALUES (?, ?, ?);
 
 -- name: CheckStoreUser :one
 SELECT COUNT(*) > 0
 FROM store_users
 WHERE store_id = ? AND user_id = ?;
 
+-- name: FindStoreUserRole :one
+SELECT r.name
+FROM store_users AS su
+JOIN roles AS r ON r.id = su.role_id
+WHERE su.store_id = ? AND su.user_id = ?;
+
 -- name: FindStoreUsers :many
 SELECT u.*
 FROM store_users AS su
@@ -14,4 +20,11 @@ WHERE su.store_id = ?;
 
 -- name: DeleteStoreUser :exec
 DELETE FROM store_users
-WHERE store_id = ? AND user_id = ?;
\ No newline at end of file
+WHERE store_id = ? AND user_id = ?;
+
+-- name: FindUserPermissionStores :many
+SELECT su.store_id
+FROM store_users AS su
+JOIN role_permissions AS rp ON rp.role_id = su.role_id
+JOIN permissions AS p ON p.id = rp.permission_id
+WHERE su.user_id = ? AND p.name = ?;
@@ -0,0 +1,48 @@
+-- name: InsertSupplier :execlastid
+INSERT INTO suppliers (slug, name, contact_name, email, phone, address, payment_terms, lead_time_days, status)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: FindSupplier :one
+SELECT * FROM suppliers WHERE id = ?;
+
+-- name: FindSupplierBySlug :one
+SELECT * FROM suppliers WHERE slug = ?;
+
+-- name: FindSuppliers :many
+SELECT * FROM suppliers
+ORDER BY name
+LIMIT ? OFFSET ?;
+
+-- name: CountSuppliers :one
+SELECT COUNT(*) AS count
+FROM suppliers;
+
+-- name: UpdateSupplier :exec
+UPDATE suppliers
+SET slug = ?,
+    name = ?,
+    contact_name = ?,
+    email = ?,
+    phone = ?,
+    address = ?,
+    payment_terms = ?,
+    lead_time_days = ?,
+    status = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: DeleteSupplier :exec
+DELETE FROM suppliers WHERE id = ?;
+
+-- name: FindSupplierSpend :many
+SELECT
+    g.supplier_id,
+    COALESCE(s.name, g.supplier) AS supplier,
+    COUNT(*) AS deliveries,
+    SUM(g.total_quantity) AS units,
+    SUM(g.total_cost) AS spend
+FROM goods_received_notes g
+LEFT JOIN suppliers s ON s.id = g.supplier_id
+WHERE g.date >= ? AND g.date < ?
+GROUP BY g.supplier_id, COALESCE(s.name, g.supplier)
+ORDER BY spend DESC;
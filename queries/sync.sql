@@ -0,0 +1,45 @@
+-- name: InsertSyncedOrder :execlastid
+INSERT INTO orders (number, channel, status, total, created_at)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: FindStoreOrderDetailsByClientID :one
+SELECT * FROM in_store_order_details WHERE client_id = ?;
+
+-- name: FindProductsChangedSince :many
+SELECT * FROM products
+WHERE COALESCE(updated_at, created_at) >= ?
+ORDER BY id;
+
+-- name: FindStoreStockChangedSince :many
+SELECT
+    p.id AS product_id,
+    p.sku,
+    CAST(COALESCE(pu.quantity, 0) - COALESCE(so.quantity, 0) AS SIGNED) AS quantity
+FROM products p
+LEFT JOIN (
+    SELECT product_id, SUM(quantity) AS quantity
+    FROM purchases
+    WHERE store_id = sqlc.arg(store_id)
+    GROUP BY product_id
+) pu ON pu.product_id = p.id
+LEFT JOIN (
+    SELECT oi.product_id, SUM(oi.quantity) AS quantity
+    FROM order_items oi
+    JOIN orders o ON o.id = oi.order_id
+    LEFT JOIN in_store_order_details isd ON isd.order_id = o.id
+    LEFT JOIN online_order_details ood ON ood.order_id = o.id
+    WHERE o.status <> 'canceled' AND COALESCE(isd.store_id, ood.store_id) = sqlc.arg(store_id)
+    GROUP BY oi.product_id
+) so ON so.product_id = p.id
+WHERE p.id IN (
+    SELECT product_id FROM purchases
+    WHERE store_id = sqlc.arg(store_id) AND created_at >= sqlc.arg(since)
+    UNION
+    SELECT oi.product_id
+    FROM order_items oi
+    JOIN orders o ON o.id = oi.order_id
+    LEFT JOIN in_store_order_details isd ON isd.order_id = o.id
+    LEFT JOIN online_order_details ood ON ood.order_id = o.id
+    WHERE COALESCE(isd.store_id, ood.store_id) = sqlc.arg(store_id) AND o.updated_at >= sqlc.arg(since)
+)
+ORDER BY p.id;
@@ -1,21 +1,87 @@
 -- name: InsertUser :execlastid
 INSERT INTO users (firstname, lastname, email, password) VALUES (?, ?, ?, ?);
 
+-- name: InsertPhoneUser :execlastid
+INSERT INTO users (firstname, lastname, phone, password, phone_verified_at) VALUES (?, ?, ?, '', NOW());
+
 -- name: FindUserByEmail :one
 SELECT * FROM users WHERE email = ?;
 
+-- name: FindUserByPhone :one
+SELECT * FROM users WHERE phone = ?;
+
 -- name: FindUserByID :one
 SELECT * FROM users WHERE id = ? LIMIT 1;
 
--- name: FindUserStore :one
-SELECT s.* FROM stores s
-JOIN store_users su ON su.store_id = s.id
-JOIN users u ON u.id = su.user_id 
-WHERE u.id = ?
-LIMIT 1;
-
 -- name: FindUserStores :many
-SELECT s.* FROM stores s
+SELECT s.id, s.slug, s.name, s.status, r.name AS role
+FROM stores s
 JOIN store_users su ON su.store_id = s.id
-JOIN users u ON u.id = su.user_id 
-WHERE u.id = ?;
\ No newline at end of file
+JOIN roles r ON r.id = su.role_id
+WHERE su.user_id = ?
+ORDER BY s.name;
+
+-- name: UpdateUserPassword :exec
+UPDATE users SET password = ? WHERE id = ?;
+
+-- name: UpdateUserPhone :exec
+UPDATE users SET phone = ?, phone_verified_at = NOW() WHERE id = ?;
+
+-- name: VerifyUserEmail :exec
+UPDATE users SET email_verified_at = NOW() WHERE id = ? AND email_verified_at IS NULL;
+
+-- name: InsertStaffUser :execlastid
+INSERT INTO users (firstname, lastname, email, phone, password) VALUES (?, ?, ?, ?, ?);
+
+-- name: FindUsers :many
+SELECT * FROM users
+ORDER BY id DESC
+LIMIT ? OFFSET ?;
+
+-- name: SearchUsers :many
+SELECT * FROM users
+WHERE firstname LIKE sqlc.arg(search)
+    OR lastname LIKE sqlc.arg(search)
+    OR email LIKE sqlc.arg(search)
+    OR phone LIKE sqlc.arg(search)
+ORDER BY id DESC
+LIMIT ? OFFSET ?;
+
+-- name: CountUsers :one
+SELECT COUNT(*) AS count
+FROM users;
+
+-- name: CountSearchUsers :one
+SELECT COUNT(*) AS count
+FROM users
+WHERE firstname LIKE sqlc.arg(search)
+    OR lastname LIKE sqlc.arg(search)
+    OR email LIKE sqlc.arg(search)
+    OR phone LIKE sqlc.arg(search);
+
+-- name: UpdateUser :exec
+UPDATE users
+SET firstname = ?,
+    lastname = ?,
+    phone_verified_at = IF(phone <=> sqlc.narg(phone), phone_verified_at, NULL),
+    phone = sqlc.narg(phone)
+WHERE id = sqlc.arg(id);
+
+-- name: DeactivateUser :execrows
+UPDATE users SET deactivated_at = NOW() WHERE id = ? AND deactivated_at IS NULL;
+
+-- name: ReactivateUser :execrows
+UPDATE users SET deactivated_at = NULL WHERE id = ? AND deactivated_at IS NOT NULL AND deleted_at IS NULL;
+
+-- name: AnonymiseUser :exec
+UPDATE users
+SET firstname = 'Deleted',
+    lastname = 'User',
+    email = NULL,
+    phone = NULL,
+    password = '',
+    email_verified_at = NULL,
+    phone_verified_at = NULL,
+    deactivated_at = NOW(),
+    deleted_at = NOW()
+WHERE id = ?;
@@ -0,0 +1,50 @@
+-- name: InsertUserAddress :execlastid
+INSERT INTO user_addresses (user_id, label, recipient, phone, line1, line2, city, region, postal_code, country, is_default)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: FindUserAddresses :many
+SELECT * FROM user_addresses
+WHERE user_id = ?
+ORDER BY is_default DESC, id DESC;
+
+-- name: FindUserAddress :one
+SELECT * FROM user_addresses WHERE id = ? AND user_id = ?;
+
+-- name: FindDefaultUserAddress :one
+SELECT * FROM user_addresses WHERE user_id = ? AND is_default LIMIT 1;
+
+-- name: CountUserAddresses :one
+SELECT COUNT(*) AS count
+FROM user_addresses
+WHERE user_id = ?;
+
+-- name: UpdateUserAddress :exec
+UPDATE user_addresses
+SET label = ?,
+    recipient = ?,
+    phone = ?,
+    line1 = ?,
+    line2 = ?,
+    city = ?,
+    region = ?,
+    postal_code = ?,
+    country = ?
+WHERE id = ? AND user_id = ?;
+
+-- name: ClearDefaultUserAddress :exec
+UPDATE user_addresses SET is_default = FALSE WHERE user_id = ? AND is_default;
+
+-- name: SetDefaultUserAddress :exec
+UPDATE user_addresses SET is_default = TRUE WHERE id = ? AND user_id = ?;
+
+-- name: PromoteUserAddress :exec
+UPDATE user_addresses SET is_default = TRUE
+WHERE user_id = ?
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: DeleteUserAddress :exec
+DELETE FROM user_addresses WHERE id = ? AND user_id = ?;
+
+-- name: DeleteUserAddresses :exec
+DELETE FROM user_addresses WHERE user_id = ?;
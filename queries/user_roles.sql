@@ -10,4 +10,7 @@ WHERE user_id = ? AND role_id = ?;
 SELECT r.id, r.name
 FROM roles AS r
 JOIN user_roles AS ur ON ur.role_id = r.id
-WHERE ur.user_id = ?;
\ No newline at end of file
+WHERE ur.user_id = ?;
+
+-- name: DeleteUserRole :execrows
+DELETE FROM user_roles WHERE user_id = ? AND role_id = ?;
@@ -0,0 +1,14 @@
+-- name: InsertUserToken :exec
+INSERT INTO user_tokens (id, user_id, purpose, expires_at) VALUES (?, ?, ?, ?);
+
+-- name: UseUserToken :execrows
+UPDATE user_tokens SET used_at = NOW()
+WHERE id = ? AND user_id = ? AND purpose = ? AND used_at IS NULL AND expires_at > NOW();
+
+-- name: RevokeUserTokens :exec
+UPDATE user_tokens SET used_at = NOW()
+WHERE user_id = ? AND purpose = ? AND used_at IS NULL;
+
+-- name: AttemptUserToken :execrows
+UPDATE user_tokens SET attempts = attempts + 1
+WHERE id = ? AND user_id = ? AND purpose = ? AND used_at IS NULL AND expires_at > NOW() AND attempts < ?;
@@ -7,6 +7,7 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 )
 
 const deleteImage = `-- name: DeleteImage :exec
@@ -18,25 +19,159 @@ func (q *Queries) DeleteImage(ctx context.Context, id uint64) error {
 	return err
 }
 
+const deleteOrphanImage = `-- name: DeleteOrphanImage :execrows
+DELETE FROM images
+WHERE images.id = ? AND images.created_at < ?
+AND NOT EXISTS (SELECT 1 FROM product_images pi WHERE pi.image_id = images.id)
+AND NOT EXISTS (SELECT 1 FROM categories c WHERE c.image_id = images.id)
+`
+
+type DeleteOrphanImageParams struct {
+	ID        uint64       `json:"id"`
+	CreatedAt sql.NullTime `json:"created_at"`
+}
+
+func (q *Queries) DeleteOrphanImage(ctx context.Context, arg DeleteOrphanImageParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteOrphanImage, arg.ID, arg.CreatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const findImage = `-- name: FindImage :one
-SELECT id, name, created_at FROM images WHERE id = ?
+SELECT id, name, hash, created_at FROM images WHERE id = ?
 `
 
 func (q *Queries) FindImage(ctx context.Context, id uint64) (Image, error) {
 	row := q.db.QueryRowContext(ctx, findImage, id)
 	var i Image
-	err := row.Scan(&i.ID, &i.Name, &i.CreatedAt)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Hash,
+		&i.CreatedAt,
+	)
 	return i, err
 }
 
+const findImageByHash = `-- name: FindImageByHash :one
+SELECT id, name, hash, created_at FROM images WHERE hash = ?
+`
+
+func (q *Queries) FindImageByHash(ctx context.Context, hash sql.NullString) (Image, error) {
+	row := q.db.QueryRowContext(ctx, findImageByHash, hash)
+	var i Image
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Hash,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const findImageByName = `-- name: FindImageByName :one
+SELECT id, name, hash, created_at FROM images WHERE name = ?
+`
+
+func (q *Queries) FindImageByName(ctx context.Context, name string) (Image, error) {
+	row := q.db.QueryRowContext(ctx, findImageByName, name)
+	var i Image
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Hash,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const findImageNames = `-- name: FindImageNames :many
+SELECT name FROM images
+`
+
+func (q *Queries) FindImageNames(ctx context.Context) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, findImageNames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		items = append(items, name)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findOrphanImages = `-- name: FindOrphanImages :many
+SELECT i.id, i.name, i.hash, i.created_at FROM images i
+WHERE i.created_at < ?
+AND NOT EXISTS (SELECT 1 FROM product_images pi WHERE pi.image_id = i.id)
+AND NOT EXISTS (SELECT 1 FROM categories c WHERE c.image_id = i.id)
+ORDER BY i.id
+`
+
+func (q *Queries) FindOrphanImages(ctx context.Context, createdAt sql.NullTime) ([]Image, error) {
+	rows, err := q.db.QueryContext(ctx, findOrphanImages, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Image
+	for rows.Next() {
+		var i Image
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Hash,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const insertImage = `-- name: InsertImage :execlastid
-INSERT INTO images (name) VALUES (?)
+INSERT INTO images (name, hash) VALUES (?, ?)
 `
 
-func (q *Queries) InsertImage(ctx context.Context, name string) (int64, error) {
-	result, err := q.db.ExecContext(ctx, insertImage, name)
+type InsertImageParams struct {
+	Name string         `json:"name"`
+	Hash sql.NullString `json:"hash"`
+}
+
+func (q *Queries) InsertImage(ctx context.Context, arg InsertImageParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, insertImage, arg.Name, arg.Hash)
 	if err != nil {
 		return 0, err
 	}
 	return result.LastInsertId()
 }
+
+const touchImage = `-- name: TouchImage :exec
+UPDATE images SET created_at = CURRENT_TIMESTAMP WHERE id = ?
+`
+
+func (q *Queries) TouchImage(ctx context.Context, id uint64) error {
+	_, err := q.db.ExecContext(ctx, touchImage, id)
+	return err
+}
@@ -7,10 +7,141 @@ package repository
 import (
 	"database/sql"
 	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"time"
 )
 
+type ImportJobsStatus string
+
+const (
+	ImportJobsStatusPending   ImportJobsStatus = "pending"
+	ImportJobsStatusRunning   ImportJobsStatus = "running"
+	ImportJobsStatusCompleted ImportJobsStatus = "completed"
+	ImportJobsStatusFailed    ImportJobsStatus = "failed"
+)
+
+func (e *ImportJobsStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ImportJobsStatus(s)
+	case string:
+		*e = ImportJobsStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ImportJobsStatus: %T", src)
+	}
+	return nil
+}
+
+type NullImportJobsStatus struct {
+	ImportJobsStatus ImportJobsStatus `json:"import_jobs_status"`
+	Valid            bool             `json:"valid"` // Valid is true if ImportJobsStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullImportJobsStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ImportJobsStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ImportJobsStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullImportJobsStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ImportJobsStatus), nil
+}
+
+type InStoreOrderDetailsTender string
+
+const (
+	InStoreOrderDetailsTenderCash        InStoreOrderDetailsTender = "cash"
+	InStoreOrderDetailsTenderCard        InStoreOrderDetailsTender = "card"
+	InStoreOrderDetailsTenderMobileMoney InStoreOrderDetailsTender = "mobile_money"
+)
+
+func (e *InStoreOrderDetailsTender) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = InStoreOrderDetailsTender(s)
+	case string:
+		*e = InStoreOrderDetailsTender(s)
+	default:
+		return fmt.Errorf("unsupported scan type for InStoreOrderDetailsTender: %T", src)
+	}
+	return nil
+}
+
+type NullInStoreOrderDetailsTender struct {
+	InStoreOrderDetailsTender InStoreOrderDetailsTender `json:"in_store_order_details_tender"`
+	Valid                     bool                      `json:"valid"` // Valid is true if InStoreOrderDetailsTender is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullInStoreOrderDetailsTender) Scan(value interface{}) error {
+	if value == nil {
+		ns.InStoreOrderDetailsTender, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.InStoreOrderDetailsTender.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullInStoreOrderDetailsTender) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.InStoreOrderDetailsTender), nil
+}
+
+type LoginAuditsEvent string
+
+const (
+	LoginAuditsEventFailed   LoginAuditsEvent = "failed"
+	LoginAuditsEventLocked   LoginAuditsEvent = "locked"
+	LoginAuditsEventUnlocked LoginAuditsEvent = "unlocked"
+)
+
+func (e *LoginAuditsEvent) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = LoginAuditsEvent(s)
+	case string:
+		*e = LoginAuditsEvent(s)
+	default:
+		return fmt.Errorf("unsupported scan type for LoginAuditsEvent: %T", src)
+	}
+	return nil
+}
+
+type NullLoginAuditsEvent struct {
+	LoginAuditsEvent LoginAuditsEvent `json:"login_audits_event"`
+	Valid            bool             `json:"valid"` // Valid is true if LoginAuditsEvent is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullLoginAuditsEvent) Scan(value interface{}) error {
+	if value == nil {
+		ns.LoginAuditsEvent, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.LoginAuditsEvent.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullLoginAuditsEvent) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.LoginAuditsEvent), nil
+}
+
 type OrdersChannel string
 
 const (
@@ -96,6 +227,178 @@ func (ns NullOrdersStatus) Value() (driver.Value, error) {
 	return string(ns.OrdersStatus), nil
 }
 
+type ProductBarcodesSymbology string
+
+const (
+	ProductBarcodesSymbologyEan13   ProductBarcodesSymbology = "ean13"
+	ProductBarcodesSymbologyUpca    ProductBarcodesSymbology = "upca"
+	ProductBarcodesSymbologyCode128 ProductBarcodesSymbology = "code128"
+)
+
+func (e *ProductBarcodesSymbology) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ProductBarcodesSymbology(s)
+	case string:
+		*e = ProductBarcodesSymbology(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ProductBarcodesSymbology: %T", src)
+	}
+	return nil
+}
+
+type NullProductBarcodesSymbology struct {
+	ProductBarcodesSymbology ProductBarcodesSymbology `json:"product_barcodes_symbology"`
+	Valid                    bool                     `json:"valid"` // Valid is true if ProductBarcodesSymbology is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullProductBarcodesSymbology) Scan(value interface{}) error {
+	if value == nil {
+		ns.ProductBarcodesSymbology, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ProductBarcodesSymbology.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullProductBarcodesSymbology) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ProductBarcodesSymbology), nil
+}
+
+type PurchaseOrdersStatus string
+
+const (
+	PurchaseOrdersStatusDraft             PurchaseOrdersStatus = "draft"
+	PurchaseOrdersStatusSent              PurchaseOrdersStatus = "sent"
+	PurchaseOrdersStatusPartiallyReceived PurchaseOrdersStatus = "partially_received"
+	PurchaseOrdersStatusReceived          PurchaseOrdersStatus = "received"
+	PurchaseOrdersStatusClosed            PurchaseOrdersStatus = "closed"
+)
+
+func (e *PurchaseOrdersStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PurchaseOrdersStatus(s)
+	case string:
+		*e = PurchaseOrdersStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PurchaseOrdersStatus: %T", src)
+	}
+	return nil
+}
+
+type NullPurchaseOrdersStatus struct {
+	PurchaseOrdersStatus PurchaseOrdersStatus `json:"purchase_orders_status"`
+	Valid                bool                 `json:"valid"` // Valid is true if PurchaseOrdersStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPurchaseOrdersStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.PurchaseOrdersStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PurchaseOrdersStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPurchaseOrdersStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PurchaseOrdersStatus), nil
+}
+
+type ShiftCashMovementsType string
+
+const (
+	ShiftCashMovementsTypeCashIn  ShiftCashMovementsType = "cash_in"
+	ShiftCashMovementsTypeCashOut ShiftCashMovementsType = "cash_out"
+)
+
+func (e *ShiftCashMovementsType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ShiftCashMovementsType(s)
+	case string:
+		*e = ShiftCashMovementsType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ShiftCashMovementsType: %T", src)
+	}
+	return nil
+}
+
+type NullShiftCashMovementsType struct {
+	ShiftCashMovementsType ShiftCashMovementsType `json:"shift_cash_movements_type"`
+	Valid                  bool                   `json:"valid"` // Valid is true if ShiftCashMovementsType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullShiftCashMovementsType) Scan(value interface{}) error {
+	if value == nil {
+		ns.ShiftCashMovementsType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ShiftCashMovementsType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullShiftCashMovementsType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ShiftCashMovementsType), nil
+}
+
+type ShiftsStatus string
+
+const (
+	ShiftsStatusOpen   ShiftsStatus = "open"
+	ShiftsStatusClosed ShiftsStatus = "closed"
+)
+
+func (e *ShiftsStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ShiftsStatus(s)
+	case string:
+		*e = ShiftsStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ShiftsStatus: %T", src)
+	}
+	return nil
+}
+
+type NullShiftsStatus struct {
+	ShiftsStatus ShiftsStatus `json:"shifts_status"`
+	Valid        bool         `json:"valid"` // Valid is true if ShiftsStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullShiftsStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ShiftsStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ShiftsStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullShiftsStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ShiftsStatus), nil
+}
+
 type Category struct {
 	ID           uint64        `json:"id"`
 	Slug         string        `json:"slug"`
@@ -106,23 +409,107 @@ type Category struct {
 	ImageID      sql.NullInt64 `json:"image_id"`
 }
 
+type CostingRebuild struct {
+	ID         uint64       `json:"id"`
+	Purchases  int32        `json:"purchases"`
+	OrderItems int32        `json:"order_items"`
+	CreatedAt  sql.NullTime `json:"created_at"`
+}
+
+type GoodsReceivedNote struct {
+	ID              uint64        `json:"id"`
+	Supplier        string        `json:"supplier"`
+	InvoiceNumber   string        `json:"invoice_number"`
+	StoreID         sql.NullInt64 `json:"store_id"`
+	Date            time.Time     `json:"date"`
+	LinesCount      int32         `json:"lines_count"`
+	TotalQuantity   int32         `json:"total_quantity"`
+	TotalCost       float64       `json:"total_cost"`
+	UserID          sql.NullInt64 `json:"user_id"`
+	CreatedAt       sql.NullTime  `json:"created_at"`
+	SupplierID      sql.NullInt64 `json:"supplier_id"`
+	PurchaseOrderID sql.NullInt64 `json:"purchase_order_id"`
+}
+
+type IdempotencyKey struct {
+	ID             uint64         `json:"id"`
+	UserID         uint64         `json:"user_id"`
+	IdempotencyKey string         `json:"idempotency_key"`
+	Method         string         `json:"method"`
+	Path           string         `json:"path"`
+	RequestHash    string         `json:"request_hash"`
+	StatusCode     sql.NullInt32  `json:"status_code"`
+	ContentType    sql.NullString `json:"content_type"`
+	ResponseBody   []byte         `json:"response_body"`
+	CreatedAt      sql.NullTime   `json:"created_at"`
+	ExpiresAt      time.Time      `json:"expires_at"`
+}
+
 type Image struct {
-	ID        uint64       `json:"id"`
-	Name      string       `json:"name"`
-	CreatedAt sql.NullTime `json:"created_at"`
+	ID        uint64         `json:"id"`
+	Name      string         `json:"name"`
+	Hash      sql.NullString `json:"hash"`
+	CreatedAt sql.NullTime   `json:"created_at"`
+}
+
+type ImportJob struct {
+	ID         uint64           `json:"id"`
+	Kind       string           `json:"kind"`
+	Status     ImportJobsStatus `json:"status"`
+	Total      int32            `json:"total"`
+	Processed  int32            `json:"processed"`
+	Inserted   int32            `json:"inserted"`
+	Updated    int32            `json:"updated"`
+	Errors     json.RawMessage  `json:"errors"`
+	UserID     sql.NullInt64    `json:"user_id"`
+	CreatedAt  sql.NullTime     `json:"created_at"`
+	FinishedAt sql.NullTime     `json:"finished_at"`
 }
 
 type InStoreOrderDetail struct {
-	ID        uint64        `json:"id"`
-	OrderID   uint64        `json:"order_id"`
-	CashierID sql.NullInt64 `json:"cashier_id"`
-	StoreID   uint64        `json:"store_id"`
+	ID           uint64                    `json:"id"`
+	OrderID      uint64                    `json:"order_id"`
+	CashierID    sql.NullInt64             `json:"cashier_id"`
+	StoreID      uint64                    `json:"store_id"`
+	ShiftID      sql.NullInt64             `json:"shift_id"`
+	Tender       InStoreOrderDetailsTender `json:"tender"`
+	Tendered     sql.NullFloat64           `json:"tendered"`
+	ClientID     sql.NullString            `json:"client_id"`
+	ClientNumber sql.NullString            `json:"client_number"`
+}
+
+type LoginAudit struct {
+	ID        uint64           `json:"id"`
+	UserID    sql.NullInt64    `json:"user_id"`
+	ActorID   sql.NullInt64    `json:"actor_id"`
+	Email     sql.NullString   `json:"email"`
+	Ip        sql.NullString   `json:"ip"`
+	UserAgent sql.NullString   `json:"user_agent"`
+	Event     LoginAuditsEvent `json:"event"`
+	CreatedAt sql.NullTime     `json:"created_at"`
+}
+
+type MfaRecoveryCode struct {
+	ID       uint64       `json:"id"`
+	UserID   uint64       `json:"user_id"`
+	CodeHash string       `json:"code_hash"`
+	UsedAt   sql.NullTime `json:"used_at"`
 }
 
 type OnlineOrderDetail struct {
-	ID         uint64        `json:"id"`
-	OrderID    uint64        `json:"order_id"`
-	CustomerID sql.NullInt64 `json:"customer_id"`
+	ID                 uint64         `json:"id"`
+	OrderID            uint64         `json:"order_id"`
+	CustomerID         sql.NullInt64  `json:"customer_id"`
+	StoreID            sql.NullInt64  `json:"store_id"`
+	AddressID          sql.NullInt64  `json:"address_id"`
+	DeliveryRecipient  sql.NullString `json:"delivery_recipient"`
+	DeliveryPhone      sql.NullString `json:"delivery_phone"`
+	DeliveryLine1      sql.NullString `json:"delivery_line1"`
+	DeliveryLine2      sql.NullString `json:"delivery_line2"`
+	DeliveryCity       sql.NullString `json:"delivery_city"`
+	DeliveryRegion     sql.NullString `json:"delivery_region"`
+	DeliveryPostalCode sql.NullString `json:"delivery_postal_code"`
+	DeliveryCountry    sql.NullString `json:"delivery_country"`
 }
 
 type Order struct {
@@ -142,36 +529,126 @@ type OrderItem struct {
 	Quantity  int32           `json:"quantity"`
 	Price     float64         `json:"price"`
 	Total     sql.NullFloat64 `json:"total"`
+	Cogs      sql.NullFloat64 `json:"cogs"`
 }
 
-type Product struct {
+type Permission struct {
 	ID          uint64         `json:"id"`
-	Slug        string         `json:"slug"`
 	Name        string         `json:"name"`
 	Description sql.NullString `json:"description"`
-	Sku         string         `json:"sku"`
-	CategoryID  sql.NullInt64  `json:"category_id"`
-	Status      bool           `json:"status"`
-	Visibility  bool           `json:"visibility"`
 	CreatedAt   sql.NullTime   `json:"created_at"`
 }
 
+type PhoneOtp struct {
+	ID         uint64       `json:"id"`
+	Phone      string       `json:"phone"`
+	Ip         string       `json:"ip"`
+	Attempts   int32        `json:"attempts"`
+	ExpiresAt  time.Time    `json:"expires_at"`
+	ApprovedAt sql.NullTime `json:"approved_at"`
+	CreatedAt  sql.NullTime `json:"created_at"`
+}
+
+type Product struct {
+	ID          uint64          `json:"id"`
+	Slug        string          `json:"slug"`
+	Name        string          `json:"name"`
+	Description sql.NullString  `json:"description"`
+	Sku         string          `json:"sku"`
+	CategoryID  sql.NullInt64   `json:"category_id"`
+	Status      bool            `json:"status"`
+	Visibility  bool            `json:"visibility"`
+	CreatedAt   sql.NullTime    `json:"created_at"`
+	Price       sql.NullFloat64 `json:"price"`
+	UpdatedAt   sql.NullTime    `json:"updated_at"`
+}
+
+type ProductBarcode struct {
+	ID        uint64                   `json:"id"`
+	ProductID uint64                   `json:"product_id"`
+	Code      string                   `json:"code"`
+	Symbology ProductBarcodesSymbology `json:"symbology"`
+	IsPrimary bool                     `json:"is_primary"`
+	CreatedAt sql.NullTime             `json:"created_at"`
+}
+
 type ProductImage struct {
 	ProductID uint64 `json:"product_id"`
 	ImageID   uint64 `json:"image_id"`
 }
 
 type Purchase struct {
-	ID           uint64        `json:"id"`
-	ProductID    uint64        `json:"product_id"`
-	Date         time.Time     `json:"date"`
-	Quantity     int32         `json:"quantity"`
-	OrderPrice   float64       `json:"order_price"`
-	SellingPrice float64       `json:"selling_price"`
-	StoreID      sql.NullInt64 `json:"store_id"`
-	UserID       sql.NullInt64 `json:"user_id"`
-	CreatedAt    sql.NullTime  `json:"created_at"`
-	UpdatedAt    sql.NullTime  `json:"updated_at"`
+	ID                  uint64        `json:"id"`
+	ProductID           uint64        `json:"product_id"`
+	Date                time.Time     `json:"date"`
+	Quantity            int32         `json:"quantity"`
+	OrderPrice          float64       `json:"order_price"`
+	SellingPrice        float64       `json:"selling_price"`
+	StoreID             sql.NullInt64 `json:"store_id"`
+	UserID              sql.NullInt64 `json:"user_id"`
+	CreatedAt           sql.NullTime  `json:"created_at"`
+	UpdatedAt           sql.NullTime  `json:"updated_at"`
+	GoodsReceivedNoteID sql.NullInt64 `json:"goods_received_note_id"`
+	Remaining           int32         `json:"remaining"`
+}
+
+type PurchaseOrder struct {
+	ID         uint64               `json:"id"`
+	Number     string               `json:"number"`
+	SupplierID uint64               `json:"supplier_id"`
+	StoreID    sql.NullInt64        `json:"store_id"`
+	Status     PurchaseOrdersStatus `json:"status"`
+	ExpectedAt sql.NullTime         `json:"expected_at"`
+	Notes      sql.NullString       `json:"notes"`
+	UserID     sql.NullInt64        `json:"user_id"`
+	CreatedAt  sql.NullTime         `json:"created_at"`
+	UpdatedAt  sql.NullTime         `json:"updated_at"`
+}
+
+type PurchaseOrderItem struct {
+	ID              uint64  `json:"id"`
+	PurchaseOrderID uint64  `json:"purchase_order_id"`
+	ProductID       uint64  `json:"product_id"`
+	Quantity        int32   `json:"quantity"`
+	Received        int32   `json:"received"`
+	OrderPrice      float64 `json:"order_price"`
+	SellingPrice    float64 `json:"selling_price"`
+}
+
+type ReceiptTemplate struct {
+	StoreID    uint64         `json:"store_id"`
+	Header     sql.NullString `json:"header"`
+	Footer     sql.NullString `json:"footer"`
+	TaxLabel   string         `json:"tax_label"`
+	TaxRate    float64        `json:"tax_rate"`
+	PaperWidth int32          `json:"paper_width"`
+	ShowQr     bool           `json:"show_qr"`
+	UpdatedAt  sql.NullTime   `json:"updated_at"`
+}
+
+type RefreshToken struct {
+	ID         uint64         `json:"id"`
+	UserID     uint64         `json:"user_id"`
+	FamilyID   string         `json:"family_id"`
+	TokenHash  string         `json:"token_hash"`
+	UserAgent  sql.NullString `json:"user_agent"`
+	ExpiresAt  time.Time      `json:"expires_at"`
+	UsedAt     sql.NullTime   `json:"used_at"`
+	ReplacedBy sql.NullInt64  `json:"replaced_by"`
+	RevokedAt  sql.NullTime   `json:"revoked_at"`
+	CreatedAt  sql.NullTime   `json:"created_at"`
+	StoreID    sql.NullInt64  `json:"store_id"`
+	Mfa        bool           `json:"mfa"`
+}
+
+type ReorderPoint struct {
+	ID              uint64        `json:"id"`
+	ProductID       uint64        `json:"product_id"`
+	StoreID         sql.NullInt64 `json:"store_id"`
+	ReorderPoint    int32         `json:"reorder_point"`
+	ReorderQuantity int32         `json:"reorder_quantity"`
+	CreatedAt       sql.NullTime  `json:"created_at"`
+	UpdatedAt       sql.NullTime  `json:"updated_at"`
 }
 
 type Role struct {
@@ -180,6 +657,59 @@ type Role struct {
 	CreatedAt sql.NullTime `json:"created_at"`
 }
 
+type RolePermission struct {
+	RoleID       uint64 `json:"role_id"`
+	PermissionID uint64 `json:"permission_id"`
+}
+
+type Shift struct {
+	ID            uint64          `json:"id"`
+	StoreID       uint64          `json:"store_id"`
+	CashierID     uint64          `json:"cashier_id"`
+	Status        ShiftsStatus    `json:"status"`
+	OpeningFloat  float64         `json:"opening_float"`
+	ExpectedCash  sql.NullFloat64 `json:"expected_cash"`
+	CountedCash   sql.NullFloat64 `json:"counted_cash"`
+	Variance      sql.NullFloat64 `json:"variance"`
+	Notes         sql.NullString  `json:"notes"`
+	OpenedAt      sql.NullTime    `json:"opened_at"`
+	ClosedAt      sql.NullTime    `json:"closed_at"`
+	OpenCashierID sql.NullInt64   `json:"open_cashier_id"`
+}
+
+type ShiftCashMovement struct {
+	ID        uint64                 `json:"id"`
+	ShiftID   uint64                 `json:"shift_id"`
+	Type      ShiftCashMovementsType `json:"type"`
+	Amount    float64                `json:"amount"`
+	Reason    sql.NullString         `json:"reason"`
+	CreatedAt sql.NullTime           `json:"created_at"`
+}
+
+type StockAlert struct {
+	ID           uint64       `json:"id"`
+	ProductID    uint64       `json:"product_id"`
+	StoreID      uint64       `json:"store_id"`
+	Quantity     int32        `json:"quantity"`
+	ReorderPoint int32        `json:"reorder_point"`
+	CreatedAt    sql.NullTime `json:"created_at"`
+	ResolvedAt   sql.NullTime `json:"resolved_at"`
+}
+
+type StockAlertDelivery struct {
+	AlertID     uint64       `json:"alert_id"`
+	Notifier    string       `json:"notifier"`
+	DeliveredAt sql.NullTime `json:"delivered_at"`
+}
+
+type StockCost struct {
+	ProductID   uint64       `json:"product_id"`
+	StoreID     uint64       `json:"store_id"`
+	Quantity    int32        `json:"quantity"`
+	AverageCost float64      `json:"average_cost"`
+	UpdatedAt   sql.NullTime `json:"updated_at"`
+}
+
 type Store struct {
 	ID     uint64 `json:"id"`
 	Slug   string `json:"slug"`
@@ -190,18 +720,73 @@ type Store struct {
 type StoreUser struct {
 	UserID  uint64 `json:"user_id"`
 	StoreID uint64 `json:"store_id"`
+	RoleID  uint64 `json:"role_id"`
+}
+
+type Supplier struct {
+	ID           uint64         `json:"id"`
+	Slug         string         `json:"slug"`
+	Name         string         `json:"name"`
+	ContactName  sql.NullString `json:"contact_name"`
+	Email        sql.NullString `json:"email"`
+	Phone        sql.NullString `json:"phone"`
+	Address      sql.NullString `json:"address"`
+	PaymentTerms sql.NullString `json:"payment_terms"`
+	LeadTimeDays sql.NullInt32  `json:"lead_time_days"`
+	Status       bool           `json:"status"`
+	CreatedAt    sql.NullTime   `json:"created_at"`
+	UpdatedAt    sql.NullTime   `json:"updated_at"`
 }
 
 type User struct {
-	ID        uint64         `json:"id"`
-	Firstname string         `json:"firstname"`
-	Lastname  string         `json:"lastname"`
-	Email     string         `json:"email"`
-	Phone     sql.NullString `json:"phone"`
-	Password  string         `json:"password"`
+	ID              uint64         `json:"id"`
+	Firstname       string         `json:"firstname"`
+	Lastname        string         `json:"lastname"`
+	Email           sql.NullString `json:"email"`
+	Phone           sql.NullString `json:"phone"`
+	Password        string         `json:"password"`
+	EmailVerifiedAt sql.NullTime   `json:"email_verified_at"`
+	PhoneVerifiedAt sql.NullTime   `json:"phone_verified_at"`
+	DeactivatedAt   sql.NullTime   `json:"deactivated_at"`
+	DeletedAt       sql.NullTime   `json:"deleted_at"`
+}
+
+type UserAddress struct {
+	ID         uint64         `json:"id"`
+	UserID     uint64         `json:"user_id"`
+	Label      sql.NullString `json:"label"`
+	Recipient  string         `json:"recipient"`
+	Phone      sql.NullString `json:"phone"`
+	Line1      string         `json:"line1"`
+	Line2      sql.NullString `json:"line2"`
+	City       string         `json:"city"`
+	Region     sql.NullString `json:"region"`
+	PostalCode sql.NullString `json:"postal_code"`
+	Country    string         `json:"country"`
+	IsDefault  bool           `json:"is_default"`
+	CreatedAt  sql.NullTime   `json:"created_at"`
+	UpdatedAt  sql.NullTime   `json:"updated_at"`
+}
+
+type UserMfa struct {
+	UserID    uint64       `json:"user_id"`
+	Secret    string       `json:"secret"`
+	LastStep  int64        `json:"last_step"`
+	EnabledAt sql.NullTime `json:"enabled_at"`
+	CreatedAt sql.NullTime `json:"created_at"`
 }
 
 type UserRole struct {
 	UserID uint64 `json:"user_id"`
 	RoleID uint64 `json:"role_id"`
 }
+
+type UserToken struct {
+	ID        string       `json:"id"`
+	UserID    uint64       `json:"user_id"`
+	Purpose   string       `json:"purpose"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	UsedAt    sql.NullTime `json:"used_at"`
+	CreatedAt sql.NullTime `json:"created_at"`
+	Attempts  int32        `json:"attempts"`
+}
@@ -10,6 +10,25 @@ import (
 	"database/sql"
 )
 
+const anonymiseOnlineOrderAddresses = `-- name: AnonymiseOnlineOrderAddresses :exec
+UPDATE online_order_details d
+JOIN orders o ON o.id = d.order_id
+SET d.delivery_recipient = NULL,
+    d.delivery_phone = NULL,
+    d.delivery_line1 = NULL,
+    d.delivery_line2 = NULL,
+    d.delivery_city = NULL,
+    d.delivery_region = NULL,
+    d.delivery_postal_code = NULL,
+    d.delivery_country = NULL
+WHERE d.customer_id = ? AND o.status IN ('completed', 'canceled')
+`
+
+func (q *Queries) AnonymiseOnlineOrderAddresses(ctx context.Context, customerID sql.NullInt64) error {
+	_, err := q.db.ExecContext(ctx, anonymiseOnlineOrderAddresses, customerID)
+	return err
+}
+
 const countOnlineOrders = `-- name: CountOnlineOrders :one
 SELECT COUNT(o.id) AS count FROM orders o
 JOIN online_order_details i ON o.id = i.order_id
@@ -23,6 +42,18 @@ func (q *Queries) CountOnlineOrders(ctx context.Context) (int64, error) {
 	return count, err
 }
 
+const countSales = `-- name: CountSales :one
+SELECT COUNT(*) AS count FROM orders
+WHERE status <> 'canceled'
+`
+
+func (q *Queries) CountSales(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSales)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countStoreOrders = `-- name: CountStoreOrders :one
 SELECT COUNT(o.id) AS count FROM orders o
 JOIN in_store_order_details i ON o.id = i.order_id
@@ -41,7 +72,7 @@ func (q *Queries) CountStoreOrders(ctx context.Context, id uint64) (int64, error
 const findLastCreatedOrder = `-- name: FindLastCreatedOrder :one
 SELECT id, number, channel, status, total, created_at, updated_at 
 FROM orders 
-ORDER BY created_at DESC 
+ORDER BY id DESC 
 LIMIT 1
 `
 
@@ -83,13 +114,27 @@ func (q *Queries) FindOnlineOrder(ctx context.Context, id uint64) (Order, error)
 }
 
 const findOnlineOrderDetails = `-- name: FindOnlineOrderDetails :one
-SELECT id, order_id, customer_id FROM online_order_details WHERE order_id = ?
+SELECT id, order_id, customer_id, store_id, address_id, delivery_recipient, delivery_phone, delivery_line1, delivery_line2, delivery_city, delivery_region, delivery_postal_code, delivery_country FROM online_order_details WHERE order_id = ?
 `
 
 func (q *Queries) FindOnlineOrderDetails(ctx context.Context, orderID uint64) (OnlineOrderDetail, error) {
 	row := q.db.QueryRowContext(ctx, findOnlineOrderDetails, orderID)
 	var i OnlineOrderDetail
-	err := row.Scan(&i.ID, &i.OrderID, &i.CustomerID)
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.CustomerID,
+		&i.StoreID,
+		&i.AddressID,
+		&i.DeliveryRecipient,
+		&i.DeliveryPhone,
+		&i.DeliveryLine1,
+		&i.DeliveryLine2,
+		&i.DeliveryCity,
+		&i.DeliveryRegion,
+		&i.DeliveryPostalCode,
+		&i.DeliveryCountry,
+	)
 	return i, err
 }
 
@@ -156,7 +201,7 @@ func (q *Queries) FindOrder(ctx context.Context, id uint64) (Order, error) {
 }
 
 const findOrderItemByProductSKU = `-- name: FindOrderItemByProductSKU :one
-SELECT oi.id, oi.order_id, oi.product_id, oi.quantity, oi.price, oi.total
+SELECT oi.id, oi.order_id, oi.product_id, oi.quantity, oi.price, oi.total, oi.cogs
 FROM order_items oi
 JOIN products p ON p.id = oi.product_id
 WHERE p.sku = ?
@@ -172,12 +217,13 @@ func (q *Queries) FindOrderItemByProductSKU(ctx context.Context, sku string) (Or
 		&i.Quantity,
 		&i.Price,
 		&i.Total,
+		&i.Cogs,
 	)
 	return i, err
 }
 
 const findOrderItems = `-- name: FindOrderItems :many
-SELECT id, order_id, product_id, quantity, price, total FROM order_items WHERE order_id = ?
+SELECT id, order_id, product_id, quantity, price, total, cogs FROM order_items WHERE order_id = ?
 `
 
 func (q *Queries) FindOrderItems(ctx context.Context, orderID uint64) ([]OrderItem, error) {
@@ -196,6 +242,7 @@ func (q *Queries) FindOrderItems(ctx context.Context, orderID uint64) ([]OrderIt
 			&i.Quantity,
 			&i.Price,
 			&i.Total,
+			&i.Cogs,
 		); err != nil {
 			return nil, err
 		}
@@ -305,7 +352,7 @@ func (q *Queries) FindStoreOrder(ctx context.Context, arg FindStoreOrderParams)
 }
 
 const findStoreOrderDetails = `-- name: FindStoreOrderDetails :one
-SELECT id, order_id, cashier_id, store_id FROM in_store_order_details WHERE order_id = ?
+SELECT id, order_id, cashier_id, store_id, shift_id, tender, tendered, client_id, client_number FROM in_store_order_details WHERE order_id = ?
 `
 
 func (q *Queries) FindStoreOrderDetails(ctx context.Context, orderID uint64) (InStoreOrderDetail, error) {
@@ -316,6 +363,11 @@ func (q *Queries) FindStoreOrderDetails(ctx context.Context, orderID uint64) (In
 		&i.OrderID,
 		&i.CashierID,
 		&i.StoreID,
+		&i.ShiftID,
+		&i.Tender,
+		&i.Tendered,
+		&i.ClientID,
+		&i.ClientNumber,
 	)
 	return i, err
 }
@@ -367,33 +419,70 @@ func (q *Queries) FindStoreOrders(ctx context.Context, arg FindStoreOrdersParams
 }
 
 const insertInStoreOrderDetails = `-- name: InsertInStoreOrderDetails :exec
-INSERT INTO in_store_order_details (order_id, cashier_id, store_id)
-VALUES (?, ?, ?)
+INSERT INTO in_store_order_details (order_id, cashier_id, store_id, shift_id, tender, tendered, client_id, client_number)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type InsertInStoreOrderDetailsParams struct {
-	OrderID   uint64        `json:"order_id"`
-	CashierID sql.NullInt64 `json:"cashier_id"`
-	StoreID   uint64        `json:"store_id"`
+	OrderID      uint64                    `json:"order_id"`
+	CashierID    sql.NullInt64             `json:"cashier_id"`
+	StoreID      uint64                    `json:"store_id"`
+	ShiftID      sql.NullInt64             `json:"shift_id"`
+	Tender       InStoreOrderDetailsTender `json:"tender"`
+	Tendered     sql.NullFloat64           `json:"tendered"`
+	ClientID     sql.NullString            `json:"client_id"`
+	ClientNumber sql.NullString            `json:"client_number"`
 }
 
 func (q *Queries) InsertInStoreOrderDetails(ctx context.Context, arg InsertInStoreOrderDetailsParams) error {
-	_, err := q.db.ExecContext(ctx, insertInStoreOrderDetails, arg.OrderID, arg.CashierID, arg.StoreID)
+	_, err := q.db.ExecContext(ctx, insertInStoreOrderDetails,
+		arg.OrderID,
+		arg.CashierID,
+		arg.StoreID,
+		arg.ShiftID,
+		arg.Tender,
+		arg.Tendered,
+		arg.ClientID,
+		arg.ClientNumber,
+	)
 	return err
 }
 
 const insertOnlineOrderDetails = `-- name: InsertOnlineOrderDetails :exec
-INSERT INTO online_order_details (order_id, customer_id)
-VALUES (?, ?)
+INSERT INTO online_order_details (order_id, customer_id, store_id, address_id, delivery_recipient, delivery_phone, delivery_line1, delivery_line2, delivery_city, delivery_region, delivery_postal_code, delivery_country)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type InsertOnlineOrderDetailsParams struct {
-	OrderID    uint64        `json:"order_id"`
-	CustomerID sql.NullInt64 `json:"customer_id"`
+	OrderID            uint64         `json:"order_id"`
+	CustomerID         sql.NullInt64  `json:"customer_id"`
+	StoreID            sql.NullInt64  `json:"store_id"`
+	AddressID          sql.NullInt64  `json:"address_id"`
+	DeliveryRecipient  sql.NullString `json:"delivery_recipient"`
+	DeliveryPhone      sql.NullString `json:"delivery_phone"`
+	DeliveryLine1      sql.NullString `json:"delivery_line1"`
+	DeliveryLine2      sql.NullString `json:"delivery_line2"`
+	DeliveryCity       sql.NullString `json:"delivery_city"`
+	DeliveryRegion     sql.NullString `json:"delivery_region"`
+	DeliveryPostalCode sql.NullString `json:"delivery_postal_code"`
+	DeliveryCountry    sql.NullString `json:"delivery_country"`
 }
 
 func (q *Queries) InsertOnlineOrderDetails(ctx context.Context, arg InsertOnlineOrderDetailsParams) error {
-	_, err := q.db.ExecContext(ctx, insertOnlineOrderDetails, arg.OrderID, arg.CustomerID)
+	_, err := q.db.ExecContext(ctx, insertOnlineOrderDetails,
+		arg.OrderID,
+		arg.CustomerID,
+		arg.StoreID,
+		arg.AddressID,
+		arg.DeliveryRecipient,
+		arg.DeliveryPhone,
+		arg.DeliveryLine1,
+		arg.DeliveryLine2,
+		arg.DeliveryCity,
+		arg.DeliveryRegion,
+		arg.DeliveryPostalCode,
+		arg.DeliveryCountry,
+	)
 	return err
 }
 
@@ -423,15 +512,16 @@ func (q *Queries) InsertOrder(ctx context.Context, arg InsertOrderParams) (int64
 }
 
 const insertOrderItem = `-- name: InsertOrderItem :exec
-INSERT INTO order_items (order_id, product_id, quantity, price)
-VALUES (?, ?, ?, ?)
+INSERT INTO order_items (order_id, product_id, quantity, price, cogs)
+VALUES (?, ?, ?, ?, ?)
 `
 
 type InsertOrderItemParams struct {
-	OrderID   uint64  `json:"order_id"`
-	ProductID uint64  `json:"product_id"`
-	Quantity  int32   `json:"quantity"`
-	Price     float64 `json:"price"`
+	OrderID   uint64          `json:"order_id"`
+	ProductID uint64          `json:"product_id"`
+	Quantity  int32           `json:"quantity"`
+	Price     float64         `json:"price"`
+	Cogs      sql.NullFloat64 `json:"cogs"`
 }
 
 func (q *Queries) InsertOrderItem(ctx context.Context, arg InsertOrderItemParams) error {
@@ -440,6 +530,7 @@ func (q *Queries) InsertOrderItem(ctx context.Context, arg InsertOrderItemParams
 		arg.ProductID,
 		arg.Quantity,
 		arg.Price,
+		arg.Cogs,
 	)
 	return err
 }
@@ -32,7 +32,7 @@ func (q *Queries) DeleteProduct(ctx context.Context, id uint64) error {
 }
 
 const findProduct = `-- name: FindProduct :one
-SELECT id, slug, name, description, sku, category_id, status, visibility, created_at FROM products WHERE id = ?
+SELECT id, slug, name, description, sku, category_id, status, visibility, created_at, price, updated_at FROM products WHERE id = ?
 `
 
 func (q *Queries) FindProduct(ctx context.Context, id uint64) (Product, error) {
@@ -48,12 +48,14 @@ func (q *Queries) FindProduct(ctx context.Context, id uint64) (Product, error) {
 		&i.Status,
 		&i.Visibility,
 		&i.CreatedAt,
+		&i.Price,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
 const findProductBySKU = `-- name: FindProductBySKU :one
-SELECT id, slug, name, description, sku, category_id, status, visibility, created_at FROM products
+SELECT id, slug, name, description, sku, category_id, status, visibility, created_at, price, updated_at FROM products
 WHERE sku = ?
 `
 
@@ -70,12 +72,14 @@ func (q *Queries) FindProductBySKU(ctx context.Context, sku string) (Product, er
 		&i.Status,
 		&i.Visibility,
 		&i.CreatedAt,
+		&i.Price,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
 const findProductBySlug = `-- name: FindProductBySlug :one
-SELECT id, slug, name, description, sku, category_id, status, visibility, created_at FROM products
+SELECT id, slug, name, description, sku, category_id, status, visibility, created_at, price, updated_at FROM products
 WHERE slug = ?
 `
 
@@ -92,6 +96,8 @@ func (q *Queries) FindProductBySlug(ctx context.Context, slug string) (Product,
 		&i.Status,
 		&i.Visibility,
 		&i.CreatedAt,
+		&i.Price,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
@@ -133,7 +139,7 @@ func (q *Queries) FindProductStock(ctx context.Context, id uint64) (FindProductS
 }
 
 const findProducts = `-- name: FindProducts :many
-SELECT id, slug, name, description, sku, category_id, status, visibility, created_at FROM products
+SELECT id, slug, name, description, sku, category_id, status, visibility, created_at, price, updated_at FROM products
 ORDER BY id DESC
 LIMIT ? OFFSET ?
 `
@@ -162,6 +168,72 @@ func (q *Queries) FindProducts(ctx context.Context, arg FindProductsParams) ([]P
 			&i.Status,
 			&i.Visibility,
 			&i.CreatedAt,
+			&i.Price,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findProductsForExport = `-- name: FindProductsForExport :many
+SELECT
+    p.id,
+    p.name,
+    p.sku,
+    p.description,
+    c.slug AS category_slug,
+    p.status,
+    p.visibility,
+    p.price,
+    (SELECT GROUP_CONCAT(i.name ORDER BY i.id SEPARATOR '|')
+        FROM images i
+        JOIN product_images pi ON pi.image_id = i.id
+        WHERE pi.product_id = p.id) AS images
+FROM products p
+LEFT JOIN categories c ON c.id = p.category_id
+ORDER BY p.id
+`
+
+type FindProductsForExportRow struct {
+	ID           uint64          `json:"id"`
+	Name         string          `json:"name"`
+	Sku          string          `json:"sku"`
+	Description  sql.NullString  `json:"description"`
+	CategorySlug sql.NullString  `json:"category_slug"`
+	Status       bool            `json:"status"`
+	Visibility   bool            `json:"visibility"`
+	Price        sql.NullFloat64 `json:"price"`
+	Images       sql.NullString  `json:"images"`
+}
+
+func (q *Queries) FindProductsForExport(ctx context.Context) ([]FindProductsForExportRow, error) {
+	rows, err := q.db.QueryContext(ctx, findProductsForExport)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindProductsForExportRow
+	for rows.Next() {
+		var i FindProductsForExportRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Sku,
+			&i.Description,
+			&i.CategorySlug,
+			&i.Status,
+			&i.Visibility,
+			&i.Price,
+			&i.Images,
 		); err != nil {
 			return nil, err
 		}
@@ -177,7 +249,7 @@ func (q *Queries) FindProducts(ctx context.Context, arg FindProductsParams) ([]P
 }
 
 const findStockProduct = `-- name: FindStockProduct :one
-SELECT DISTINCT p.id, p.slug, p.name, p.description, p.sku, p.category_id, p.status, p.visibility, p.created_at
+SELECT DISTINCT p.id, p.slug, p.name, p.description, p.sku, p.category_id, p.status, p.visibility, p.created_at, p.price, p.updated_at
 FROM products p
 JOIN purchases pur ON pur.product_id = p.id
 WHERE p.sku = ?
@@ -197,12 +269,14 @@ func (q *Queries) FindStockProduct(ctx context.Context, sku string) (Product, er
 		&i.Status,
 		&i.Visibility,
 		&i.CreatedAt,
+		&i.Price,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
 const findStockProducts = `-- name: FindStockProducts :many
-SELECT DISTINCT p.id, p.slug, p.name, p.description, p.sku, p.category_id, p.status, p.visibility, p.created_at
+SELECT DISTINCT p.id, p.slug, p.name, p.description, p.sku, p.category_id, p.status, p.visibility, p.created_at, p.price, p.updated_at
 FROM products p
 JOIN purchases pur ON pur.product_id = p.id
 ORDER BY p.id DESC
@@ -233,6 +307,8 @@ func (q *Queries) FindStockProducts(ctx context.Context, arg FindStockProductsPa
 			&i.Status,
 			&i.Visibility,
 			&i.CreatedAt,
+			&i.Price,
+			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -248,18 +324,19 @@ func (q *Queries) FindStockProducts(ctx context.Context, arg FindStockProductsPa
 }
 
 const insertProduct = `-- name: InsertProduct :execlastid
-INSERT INTO products (slug, name, description, sku, category_id, status, visibility)
-VALUES (?, ?, ?, ?, ?, ?, ?)
+INSERT INTO products (slug, name, description, sku, category_id, status, visibility, price)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type InsertProductParams struct {
-	Slug        string         `json:"slug"`
-	Name        string         `json:"name"`
-	Description sql.NullString `json:"description"`
-	Sku         string         `json:"sku"`
-	CategoryID  sql.NullInt64  `json:"category_id"`
-	Status      bool           `json:"status"`
-	Visibility  bool           `json:"visibility"`
+	Slug        string          `json:"slug"`
+	Name        string          `json:"name"`
+	Description sql.NullString  `json:"description"`
+	Sku         string          `json:"sku"`
+	CategoryID  sql.NullInt64   `json:"category_id"`
+	Status      bool            `json:"status"`
+	Visibility  bool            `json:"visibility"`
+	Price       sql.NullFloat64 `json:"price"`
 }
 
 func (q *Queries) InsertProduct(ctx context.Context, arg InsertProductParams) (int64, error) {
@@ -271,6 +348,7 @@ func (q *Queries) InsertProduct(ctx context.Context, arg InsertProductParams) (i
 		arg.CategoryID,
 		arg.Status,
 		arg.Visibility,
+		arg.Price,
 	)
 	if err != nil {
 		return 0, err
@@ -279,7 +357,7 @@ func (q *Queries) InsertProduct(ctx context.Context, arg InsertProductParams) (i
 }
 
 const searchProducts = `-- name: SearchProducts :many
-SELECT id, slug, name, description, sku, category_id, status, visibility, created_at FROM products
+SELECT id, slug, name, description, sku, category_id, status, visibility, created_at, price, updated_at FROM products
 WHERE name LIKE ?
 ORDER BY id DESC
 LIMIT ? OFFSET ?
@@ -310,6 +388,8 @@ func (q *Queries) SearchProducts(ctx context.Context, arg SearchProductsParams)
 			&i.Status,
 			&i.Visibility,
 			&i.CreatedAt,
+			&i.Price,
+			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -332,19 +412,21 @@ SET name = COALESCE(?, name),
     sku = COALESCE(?, sku),
     category_id = ?,
     status = COALESCE(?, status),
-    visibility = COALESCE(?, visibility)
+    visibility = COALESCE(?, visibility),
+    price = COALESCE(?, price)
 WHERE id = ?
 `
 
 type UpdateProductParams struct {
-	Name        string         `json:"name"`
-	Slug        string         `json:"slug"`
-	Description sql.NullString `json:"description"`
-	Sku         string         `json:"sku"`
-	CategoryID  sql.NullInt64  `json:"category_id"`
-	Status      bool           `json:"status"`
-	Visibility  bool           `json:"visibility"`
-	ID          uint64         `json:"id"`
+	Name        string          `json:"name"`
+	Slug        string          `json:"slug"`
+	Description sql.NullString  `json:"description"`
+	Sku         string          `json:"sku"`
+	CategoryID  sql.NullInt64   `json:"category_id"`
+	Status      bool            `json:"status"`
+	Visibility  bool            `json:"visibility"`
+	Price       sql.NullFloat64 `json:"price"`
+	ID          uint64          `json:"id"`
 }
 
 func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) error {
@@ -356,6 +438,7 @@ func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) er
 		arg.CategoryID,
 		arg.Status,
 		arg.Visibility,
+		arg.Price,
 		arg.ID,
 	)
 	return err
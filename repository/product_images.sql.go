@@ -10,7 +10,7 @@ import (
 )
 
 const assignProductImage = `-- name: AssignProductImage :exec
-INSERT INTO product_images (product_id, image_id) VALUES (?, ?)
+INSERT IGNORE INTO product_images (product_id, image_id) VALUES (?, ?)
 `
 
 type AssignProductImageParams struct {
@@ -14,10 +14,11 @@ import (
 const countPurchases = `-- name: CountPurchases :one
 SELECT COUNT(*) AS count
 FROM purchases
+WHERE (? IS NULL OR FIND_IN_SET(store_id, ?))
 `
 
-func (q *Queries) CountPurchases(ctx context.Context) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countPurchases)
+func (q *Queries) CountPurchases(ctx context.Context, stores sql.NullString) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countPurchases, stores, stores)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -34,7 +35,7 @@ func (q *Queries) DeletePurchase(ctx context.Context, id uint64) error {
 }
 
 const findPurchase = `-- name: FindPurchase :one
-SELECT id, product_id, date, quantity, order_price, selling_price, store_id, user_id, created_at, updated_at FROM purchases WHERE id  = ?
+SELECT id, product_id, date, quantity, order_price, selling_price, store_id, user_id, created_at, updated_at, goods_received_note_id, remaining FROM purchases WHERE id  = ?
 `
 
 func (q *Queries) FindPurchase(ctx context.Context, id uint64) (Purchase, error) {
@@ -51,12 +52,14 @@ func (q *Queries) FindPurchase(ctx context.Context, id uint64) (Purchase, error)
 		&i.UserID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.GoodsReceivedNoteID,
+		&i.Remaining,
 	)
 	return i, err
 }
 
 const findPurchaseByProductSKU = `-- name: FindPurchaseByProductSKU :one
-SELECT s.id, s.product_id, s.date, s.quantity, s.order_price, s.selling_price, s.store_id, s.user_id, s.created_at, s.updated_at FROM purchases s
+SELECT s.id, s.product_id, s.date, s.quantity, s.order_price, s.selling_price, s.store_id, s.user_id, s.created_at, s.updated_at, s.goods_received_note_id, s.remaining FROM purchases s
 JOIN products p ON p.id = s.product_id
 WHERE p.sku = ?
 LIMIT 1
@@ -76,23 +79,74 @@ func (q *Queries) FindPurchaseByProductSKU(ctx context.Context, sku string) (Pur
 		&i.UserID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.GoodsReceivedNoteID,
+		&i.Remaining,
 	)
 	return i, err
 }
 
+const findPurchasesByGoodsReceivedNote = `-- name: FindPurchasesByGoodsReceivedNote :many
+SELECT id, product_id, date, quantity, order_price, selling_price, store_id, user_id, created_at, updated_at, goods_received_note_id, remaining FROM purchases
+WHERE goods_received_note_id = ?
+ORDER BY id
+`
+
+func (q *Queries) FindPurchasesByGoodsReceivedNote(ctx context.Context, goodsReceivedNoteID sql.NullInt64) ([]Purchase, error) {
+	rows, err := q.db.QueryContext(ctx, findPurchasesByGoodsReceivedNote, goodsReceivedNoteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Purchase
+	for rows.Next() {
+		var i Purchase
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.Date,
+			&i.Quantity,
+			&i.OrderPrice,
+			&i.SellingPrice,
+			&i.StoreID,
+			&i.UserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.GoodsReceivedNoteID,
+			&i.Remaining,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const findPurchases = `-- name: FindPurchases :many
-SELECT id, product_id, date, quantity, order_price, selling_price, store_id, user_id, created_at, updated_at FROM purchases
+SELECT id, product_id, date, quantity, order_price, selling_price, store_id, user_id, created_at, updated_at, goods_received_note_id, remaining FROM purchases
+WHERE (? IS NULL OR FIND_IN_SET(store_id, ?))
 ORDER BY id DESC
 LIMIT ? OFFSET ?
 `
 
 type FindPurchasesParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+	Stores sql.NullString `json:"stores"`
+	Limit  int32          `json:"limit"`
+	Offset int32          `json:"offset"`
 }
 
 func (q *Queries) FindPurchases(ctx context.Context, arg FindPurchasesParams) ([]Purchase, error) {
-	rows, err := q.db.QueryContext(ctx, findPurchases, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, findPurchases,
+		arg.Stores,
+		arg.Stores,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -111,6 +165,8 @@ func (q *Queries) FindPurchases(ctx context.Context, arg FindPurchasesParams) ([
 			&i.UserID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.GoodsReceivedNoteID,
+			&i.Remaining,
 		); err != nil {
 			return nil, err
 		}
@@ -126,7 +182,7 @@ func (q *Queries) FindPurchases(ctx context.Context, arg FindPurchasesParams) ([
 }
 
 const findPurchasesByProductSKU = `-- name: FindPurchasesByProductSKU :many
-SELECT s.id, s.product_id, s.date, s.quantity, s.order_price, s.selling_price, s.store_id, s.user_id, s.created_at, s.updated_at FROM purchases s
+SELECT s.id, s.product_id, s.date, s.quantity, s.order_price, s.selling_price, s.store_id, s.user_id, s.created_at, s.updated_at, s.goods_received_note_id, s.remaining FROM purchases s
 JOIN products p ON p.id = s.product_id
 WHERE p.sku = ?
 ORDER BY id DESC
@@ -159,6 +215,8 @@ func (q *Queries) FindPurchasesByProductSKU(ctx context.Context, arg FindPurchas
 			&i.UserID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.GoodsReceivedNoteID,
+			&i.Remaining,
 		); err != nil {
 			return nil, err
 		}
@@ -174,18 +232,19 @@ func (q *Queries) FindPurchasesByProductSKU(ctx context.Context, arg FindPurchas
 }
 
 const insertPurchase = `-- name: InsertPurchase :execlastid
-INSERT INTO purchases (product_id, date, quantity, order_price, selling_price, store_id, user_id)
-VALUES (?, ?, ?, ?, ?, ?, ?)
+INSERT INTO purchases (product_id, date, quantity, order_price, selling_price, store_id, user_id, goods_received_note_id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type InsertPurchaseParams struct {
-	ProductID    uint64        `json:"product_id"`
-	Date         time.Time     `json:"date"`
-	Quantity     int32         `json:"quantity"`
-	OrderPrice   float64       `json:"order_price"`
-	SellingPrice float64       `json:"selling_price"`
-	StoreID      sql.NullInt64 `json:"store_id"`
-	UserID       sql.NullInt64 `json:"user_id"`
+	ProductID           uint64        `json:"product_id"`
+	Date                time.Time     `json:"date"`
+	Quantity            int32         `json:"quantity"`
+	OrderPrice          float64       `json:"order_price"`
+	SellingPrice        float64       `json:"selling_price"`
+	StoreID             sql.NullInt64 `json:"store_id"`
+	UserID              sql.NullInt64 `json:"user_id"`
+	GoodsReceivedNoteID sql.NullInt64 `json:"goods_received_note_id"`
 }
 
 func (q *Queries) InsertPurchase(ctx context.Context, arg InsertPurchaseParams) (int64, error) {
@@ -197,6 +256,7 @@ func (q *Queries) InsertPurchase(ctx context.Context, arg InsertPurchaseParams)
 		arg.SellingPrice,
 		arg.StoreID,
 		arg.UserID,
+		arg.GoodsReceivedNoteID,
 	)
 	if err != nil {
 		return 0, err
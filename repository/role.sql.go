@@ -9,6 +9,28 @@ import (
 	"context"
 )
 
+const countRoleUsers = `-- name: CountRoleUsers :one
+SELECT
+    (SELECT COUNT(*) FROM user_roles WHERE role_id = ?)
+    + (SELECT COUNT(*) FROM store_users WHERE role_id = ?) AS count
+`
+
+func (q *Queries) CountRoleUsers(ctx context.Context, roleID uint64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countRoleUsers, roleID, roleID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteRole = `-- name: DeleteRole :exec
+DELETE FROM roles WHERE id = ?
+`
+
+func (q *Queries) DeleteRole(ctx context.Context, id uint64) error {
+	_, err := q.db.ExecContext(ctx, deleteRole, id)
+	return err
+}
+
 const findRole = `-- name: FindRole :one
 SELECT id, name, created_at FROM roles WHERE id = ?
 `
@@ -31,6 +53,33 @@ func (q *Queries) FindRoleByName(ctx context.Context, name string) (Role, error)
 	return i, err
 }
 
+const findRoles = `-- name: FindRoles :many
+SELECT id, name, created_at FROM roles ORDER BY id
+`
+
+func (q *Queries) FindRoles(ctx context.Context) ([]Role, error) {
+	rows, err := q.db.QueryContext(ctx, findRoles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Role
+	for rows.Next() {
+		var i Role
+		if err := rows.Scan(&i.ID, &i.Name, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const insertRole = `-- name: InsertRole :execlastid
 INSERT INTO roles (name) VALUES (?)
 `
@@ -42,3 +91,17 @@ func (q *Queries) InsertRole(ctx context.Context, name string) (int64, error) {
 	}
 	return result.LastInsertId()
 }
+
+const updateRole = `-- name: UpdateRole :exec
+UPDATE roles SET name = ? WHERE id = ?
+`
+
+type UpdateRoleParams struct {
+	Name string `json:"name"`
+	ID   uint64 `json:"id"`
+}
+
+func (q *Queries) UpdateRole(ctx context.Context, arg UpdateRoleParams) error {
+	_, err := q.db.ExecContext(ctx, updateRole, arg.Name, arg.ID)
+	return err
+}
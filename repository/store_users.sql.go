@@ -10,16 +10,17 @@ import (
 )
 
 const assignStoreUser = `-- name: AssignStoreUser :exec
-INSERT INTO store_users (store_id, user_id) VALUES (?, ?)
+INSERT INTO store_users (store_id, user_id, role_id) VALUES (?, ?, ?)
 `
 
 type AssignStoreUserParams struct {
 	StoreID uint64 `json:"store_id"`
 	UserID  uint64 `json:"user_id"`
+	RoleID  uint64 `json:"role_id"`
 }
 
 func (q *Queries) AssignStoreUser(ctx context.Context, arg AssignStoreUserParams) error {
-	_, err := q.db.ExecContext(ctx, assignStoreUser, arg.StoreID, arg.UserID)
+	_, err := q.db.ExecContext(ctx, assignStoreUser, arg.StoreID, arg.UserID, arg.RoleID)
 	return err
 }
 
@@ -56,8 +57,63 @@ func (q *Queries) DeleteStoreUser(ctx context.Context, arg DeleteStoreUserParams
 	return err
 }
 
+const findUserPermissionStores = `-- name: FindUserPermissionStores :many
+SELECT su.store_id
+FROM store_users AS su
+JOIN role_permissions AS rp ON rp.role_id = su.role_id
+JOIN permissions AS p ON p.id = rp.permission_id
+WHERE su.user_id = ? AND p.name = ?
+`
+
+type FindUserPermissionStoresParams struct {
+	UserID uint64 `json:"user_id"`
+	Name   string `json:"name"`
+}
+
+func (q *Queries) FindUserPermissionStores(ctx context.Context, arg FindUserPermissionStoresParams) ([]uint64, error) {
+	rows, err := q.db.QueryContext(ctx, findUserPermissionStores, arg.UserID, arg.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uint64
+	for rows.Next() {
+		var store_id uint64
+		if err := rows.Scan(&store_id); err != nil {
+			return nil, err
+		}
+		items = append(items, store_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findStoreUserRole = `-- name: FindStoreUserRole :one
+SELECT r.name
+FROM store_users AS su
+JOIN roles AS r ON r.id = su.role_id
+WHERE su.store_id = ? AND su.user_id = ?
+`
+
+type FindStoreUserRoleParams struct {
+	StoreID uint64 `json:"store_id"`
+	UserID  uint64 `json:"user_id"`
+}
+
+func (q *Queries) FindStoreUserRole(ctx context.Context, arg FindStoreUserRoleParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, findStoreUserRole, arg.StoreID, arg.UserID)
+	var name string
+	err := row.Scan(&name)
+	return name, err
+}
+
 const findStoreUsers = `-- name: FindStoreUsers :many
-SELECT u.id, u.firstname, u.lastname, u.email, u.phone, u.password
+SELECT u.id, u.firstname, u.lastname, u.email, u.phone, u.password, u.email_verified_at, u.phone_verified_at, u.deactivated_at, u.deleted_at
 FROM store_users AS su
 JOIN users AS u ON u.id = su.user_id
 WHERE su.store_id = ?
@@ -79,6 +135,10 @@ func (q *Queries) FindStoreUsers(ctx context.Context, storeID uint64) ([]User, e
 			&i.Email,
 			&i.Phone,
 			&i.Password,
+			&i.EmailVerifiedAt,
+			&i.PhoneVerifiedAt,
+			&i.DeactivatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -7,13 +7,78 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 )
 
+const anonymiseUser = `-- name: AnonymiseUser :exec
+UPDATE users
+SET firstname = 'Deleted',
+    lastname = 'User',
+    email = NULL,
+    phone = NULL,
+    password = '',
+    email_verified_at = NULL,
+    phone_verified_at = NULL,
+    deactivated_at = NOW(),
+    deleted_at = NOW()
+WHERE id = ?
+`
+
+func (q *Queries) AnonymiseUser(ctx context.Context, id uint64) error {
+	_, err := q.db.ExecContext(ctx, anonymiseUser, id)
+	return err
+}
+
+const countSearchUsers = `-- name: CountSearchUsers :one
+SELECT COUNT(*) AS count
+FROM users
+WHERE firstname LIKE ?
+    OR lastname LIKE ?
+    OR email LIKE ?
+    OR phone LIKE ?
+`
+
+func (q *Queries) CountSearchUsers(ctx context.Context, search string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSearchUsers,
+		search,
+		search,
+		search,
+		search,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countUsers = `-- name: CountUsers :one
+SELECT COUNT(*) AS count
+FROM users
+`
+
+func (q *Queries) CountUsers(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countUsers)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deactivateUser = `-- name: DeactivateUser :execrows
+UPDATE users SET deactivated_at = NOW() WHERE id = ? AND deactivated_at IS NULL
+`
+
+func (q *Queries) DeactivateUser(ctx context.Context, id uint64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deactivateUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const findUserByEmail = `-- name: FindUserByEmail :one
-SELECT id, firstname, lastname, email, phone, password FROM users WHERE email = ?
+SELECT id, firstname, lastname, email, phone, password, email_verified_at, phone_verified_at, deactivated_at, deleted_at FROM users WHERE email = ?
 `
 
-func (q *Queries) FindUserByEmail(ctx context.Context, email string) (User, error) {
+func (q *Queries) FindUserByEmail(ctx context.Context, email sql.NullString) (User, error) {
 	row := q.db.QueryRowContext(ctx, findUserByEmail, email)
 	var i User
 	err := row.Scan(
@@ -23,12 +88,16 @@ func (q *Queries) FindUserByEmail(ctx context.Context, email string) (User, erro
 		&i.Email,
 		&i.Phone,
 		&i.Password,
+		&i.EmailVerifiedAt,
+		&i.PhoneVerifiedAt,
+		&i.DeactivatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const findUserByID = `-- name: FindUserByID :one
-SELECT id, firstname, lastname, email, phone, password FROM users WHERE id = ? LIMIT 1
+SELECT id, firstname, lastname, email, phone, password, email_verified_at, phone_verified_at, deactivated_at, deleted_at FROM users WHERE id = ? LIMIT 1
 `
 
 func (q *Queries) FindUserByID(ctx context.Context, id uint64) (User, error) {
@@ -41,51 +110,68 @@ func (q *Queries) FindUserByID(ctx context.Context, id uint64) (User, error) {
 		&i.Email,
 		&i.Phone,
 		&i.Password,
+		&i.EmailVerifiedAt,
+		&i.PhoneVerifiedAt,
+		&i.DeactivatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
-const findUserStore = `-- name: FindUserStore :one
-SELECT s.id, s.slug, s.name, s.status FROM stores s
-JOIN store_users su ON su.store_id = s.id
-JOIN users u ON u.id = su.user_id 
-WHERE u.id = ?
-LIMIT 1
+const findUserByPhone = `-- name: FindUserByPhone :one
+SELECT id, firstname, lastname, email, phone, password, email_verified_at, phone_verified_at, deactivated_at, deleted_at FROM users WHERE phone = ?
 `
 
-func (q *Queries) FindUserStore(ctx context.Context, id uint64) (Store, error) {
-	row := q.db.QueryRowContext(ctx, findUserStore, id)
-	var i Store
+func (q *Queries) FindUserByPhone(ctx context.Context, phone sql.NullString) (User, error) {
+	row := q.db.QueryRowContext(ctx, findUserByPhone, phone)
+	var i User
 	err := row.Scan(
 		&i.ID,
-		&i.Slug,
-		&i.Name,
-		&i.Status,
+		&i.Firstname,
+		&i.Lastname,
+		&i.Email,
+		&i.Phone,
+		&i.Password,
+		&i.EmailVerifiedAt,
+		&i.PhoneVerifiedAt,
+		&i.DeactivatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const findUserStores = `-- name: FindUserStores :many
-SELECT s.id, s.slug, s.name, s.status FROM stores s
+SELECT s.id, s.slug, s.name, s.status, r.name AS role
+FROM stores s
 JOIN store_users su ON su.store_id = s.id
-JOIN users u ON u.id = su.user_id 
-WHERE u.id = ?
+JOIN roles r ON r.id = su.role_id
+WHERE su.user_id = ?
+ORDER BY s.name
 `
 
-func (q *Queries) FindUserStores(ctx context.Context, id uint64) ([]Store, error) {
-	rows, err := q.db.QueryContext(ctx, findUserStores, id)
+type FindUserStoresRow struct {
+	ID     uint64 `json:"id"`
+	Slug   string `json:"slug"`
+	Name   string `json:"name"`
+	Status bool   `json:"status"`
+	Role   string `json:"role"`
+}
+
+func (q *Queries) FindUserStores(ctx context.Context, userID uint64) ([]FindUserStoresRow, error) {
+	rows, err := q.db.QueryContext(ctx, findUserStores, userID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Store
+	var items []FindUserStoresRow
 	for rows.Next() {
-		var i Store
+		var i FindUserStoresRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Slug,
 			&i.Name,
 			&i.Status,
+			&i.Role,
 		); err != nil {
 			return nil, err
 		}
@@ -100,15 +186,104 @@ func (q *Queries) FindUserStores(ctx context.Context, id uint64) ([]Store, error
 	return items, nil
 }
 
+const findUsers = `-- name: FindUsers :many
+SELECT id, firstname, lastname, email, phone, password, email_verified_at, phone_verified_at, deactivated_at, deleted_at FROM users
+ORDER BY id DESC
+LIMIT ? OFFSET ?
+`
+
+type FindUsersParams struct {
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+func (q *Queries) FindUsers(ctx context.Context, arg FindUsersParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, findUsers, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Firstname,
+			&i.Lastname,
+			&i.Email,
+			&i.Phone,
+			&i.Password,
+			&i.EmailVerifiedAt,
+			&i.PhoneVerifiedAt,
+			&i.DeactivatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertPhoneUser = `-- name: InsertPhoneUser :execlastid
+INSERT INTO users (firstname, lastname, phone, password, phone_verified_at) VALUES (?, ?, ?, '', NOW())
+`
+
+type InsertPhoneUserParams struct {
+	Firstname string         `json:"firstname"`
+	Lastname  string         `json:"lastname"`
+	Phone     sql.NullString `json:"phone"`
+}
+
+func (q *Queries) InsertPhoneUser(ctx context.Context, arg InsertPhoneUserParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, insertPhoneUser, arg.Firstname, arg.Lastname, arg.Phone)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+const insertStaffUser = `-- name: InsertStaffUser :execlastid
+INSERT INTO users (firstname, lastname, email, phone, password) VALUES (?, ?, ?, ?, ?)
+`
+
+type InsertStaffUserParams struct {
+	Firstname string         `json:"firstname"`
+	Lastname  string         `json:"lastname"`
+	Email     sql.NullString `json:"email"`
+	Phone     sql.NullString `json:"phone"`
+	Password  string         `json:"password"`
+}
+
+func (q *Queries) InsertStaffUser(ctx context.Context, arg InsertStaffUserParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, insertStaffUser,
+		arg.Firstname,
+		arg.Lastname,
+		arg.Email,
+		arg.Phone,
+		arg.Password,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
 const insertUser = `-- name: InsertUser :execlastid
 INSERT INTO users (firstname, lastname, email, password) VALUES (?, ?, ?, ?)
 `
 
 type InsertUserParams struct {
-	Firstname string `json:"firstname"`
-	Lastname  string `json:"lastname"`
-	Email     string `json:"email"`
-	Password  string `json:"password"`
+	Firstname string         `json:"firstname"`
+	Lastname  string         `json:"lastname"`
+	Email     sql.NullString `json:"email"`
+	Password  string         `json:"password"`
 }
 
 func (q *Queries) InsertUser(ctx context.Context, arg InsertUserParams) (int64, error) {
@@ -123,3 +298,136 @@ func (q *Queries) InsertUser(ctx context.Context, arg InsertUserParams) (int64,
 	}
 	return result.LastInsertId()
 }
+
+const reactivateUser = `-- name: ReactivateUser :execrows
+UPDATE users SET deactivated_at = NULL WHERE id = ? AND deactivated_at IS NOT NULL AND deleted_at IS NULL
+`
+
+func (q *Queries) ReactivateUser(ctx context.Context, id uint64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, reactivateUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const searchUsers = `-- name: SearchUsers :many
+SELECT id, firstname, lastname, email, phone, password, email_verified_at, phone_verified_at, deactivated_at, deleted_at FROM users
+WHERE firstname LIKE ?
+    OR lastname LIKE ?
+    OR email LIKE ?
+    OR phone LIKE ?
+ORDER BY id DESC
+LIMIT ? OFFSET ?
+`
+
+type SearchUsersParams struct {
+	Search string `json:"search"`
+	Limit  int32  `json:"limit"`
+	Offset int32  `json:"offset"`
+}
+
+func (q *Queries) SearchUsers(ctx context.Context, arg SearchUsersParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, searchUsers,
+		arg.Search,
+		arg.Search,
+		arg.Search,
+		arg.Search,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Firstname,
+			&i.Lastname,
+			&i.Email,
+			&i.Phone,
+			&i.Password,
+			&i.EmailVerifiedAt,
+			&i.PhoneVerifiedAt,
+			&i.DeactivatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateUser = `-- name: UpdateUser :exec
+UPDATE users
+SET firstname = ?,
+    lastname = ?,
+    phone_verified_at = IF(phone <=> ?, phone_verified_at, NULL),
+    phone = ?
+WHERE id = ?
+`
+
+type UpdateUserParams struct {
+	Firstname string         `json:"firstname"`
+	Lastname  string         `json:"lastname"`
+	Phone     sql.NullString `json:"phone"`
+	ID        uint64         `json:"id"`
+}
+
+func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) error {
+	_, err := q.db.ExecContext(ctx, updateUser,
+		arg.Firstname,
+		arg.Lastname,
+		arg.Phone,
+		arg.Phone,
+		arg.ID,
+	)
+	return err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :exec
+UPDATE users SET password = ? WHERE id = ?
+`
+
+type UpdateUserPasswordParams struct {
+	Password string `json:"password"`
+	ID       uint64 `json:"id"`
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserPassword, arg.Password, arg.ID)
+	return err
+}
+
+const updateUserPhone = `-- name: UpdateUserPhone :exec
+UPDATE users SET phone = ?, phone_verified_at = NOW() WHERE id = ?
+`
+
+type UpdateUserPhoneParams struct {
+	Phone sql.NullString `json:"phone"`
+	ID    uint64         `json:"id"`
+}
+
+func (q *Queries) UpdateUserPhone(ctx context.Context, arg UpdateUserPhoneParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserPhone, arg.Phone, arg.ID)
+	return err
+}
+
+const verifyUserEmail = `-- name: VerifyUserEmail :exec
+UPDATE users SET email_verified_at = NOW() WHERE id = ? AND email_verified_at IS NULL
+`
+
+func (q *Queries) VerifyUserEmail(ctx context.Context, id uint64) error {
+	_, err := q.db.ExecContext(ctx, verifyUserEmail, id)
+	return err
+}
@@ -41,6 +41,23 @@ func (q *Queries) CheckUserRole(ctx context.Context, arg CheckUserRoleParams) (b
 	return column_1, err
 }
 
+const deleteUserRole = `-- name: DeleteUserRole :execrows
+DELETE FROM user_roles WHERE user_id = ? AND role_id = ?
+`
+
+type DeleteUserRoleParams struct {
+	UserID uint64 `json:"user_id"`
+	RoleID uint64 `json:"role_id"`
+}
+
+func (q *Queries) DeleteUserRole(ctx context.Context, arg DeleteUserRoleParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteUserRole, arg.UserID, arg.RoleID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const findUserRoles = `-- name: FindUserRoles :many
 SELECT r.id, r.name
 FROM roles AS r